@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+)
+
+// ErrNoAnswers is returned when an answers document is empty.
+var ErrNoAnswers = errors.New("no answers given")
+
+var (
+	_ FullPrompter = (*TerminalPrompter)(nil)
+	_ FullPrompter = (*AnswersPrompter)(nil)
+)
+
+// Answers are the answers to the questions of the prompt flow and of the wizard, as given in an answers file. An
+// unanswered question gets the default answer of the interactive prompt.
+type Answers struct {
+	CaseNumber           string `json:"caseNumber" yaml:"caseNumber"`
+	SourceServerID       string `json:"sourceServerId" yaml:"sourceServerId"`
+	TargetServerID       string `json:"targetServerId" yaml:"targetServerId"`
+	IncludeLogs          *bool  `json:"includeLogs" yaml:"includeLogs"`
+	LogsStartDate        string `json:"logsStartDate" yaml:"logsStartDate"`
+	LogsEndDate          string `json:"logsEndDate" yaml:"logsEndDate"`
+	IncludeConfiguration *bool  `json:"includeConfiguration" yaml:"includeConfiguration"`
+	IncludeSystem        *bool  `json:"includeSystem" yaml:"includeSystem"`
+	IncludeThreadDump    *bool  `json:"includeThreadDump" yaml:"includeThreadDump"`
+	ThreadDumpCount      *uint  `json:"threadDumpCount" yaml:"threadDumpCount"`
+	ThreadDumpInterval   *uint  `json:"threadDumpInterval" yaml:"threadDumpInterval"`
+	Description          string `json:"description" yaml:"description"`
+	Confirm              *bool  `json:"confirm" yaml:"confirm"`
+}
+
+// AnswersPrompter is a Prompter that gets answers from a YAML or JSON document instead of asking the user, for
+// environments without a terminal.
+type AnswersPrompter struct {
+	Answers Answers
+}
+
+// ReadAnswers reads the answers from a YAML or JSON document. Unknown questions are rejected so that a typo does not
+// silently give a default answer.
+func ReadAnswers(source string, r io.Reader) (*AnswersPrompter, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoAnswers, source)
+	}
+	prompter := &AnswersPrompter{}
+	if err = yaml.UnmarshalStrict(content, &prompter.Answers); err != nil {
+		return nil, fmt.Errorf("invalid answers from %s: %w", source, err)
+	}
+	return prompter, nil
+}
+
+// AskIncludeLogs tells if logs must be included.
+func (a *AnswersPrompter) AskIncludeLogs() (bool, error) {
+	return boolOrDefault(a.Answers.IncludeLogs, true), nil
+}
+
+// AskIncludeSystem tells if system info must be included.
+func (a *AnswersPrompter) AskIncludeSystem() (bool, error) {
+	return boolOrDefault(a.Answers.IncludeSystem, true), nil
+}
+
+// AskIncludeConfiguration tells if configuration must be included.
+func (a *AnswersPrompter) AskIncludeConfiguration() (bool, error) {
+	return boolOrDefault(a.Answers.IncludeConfiguration, true), nil
+}
+
+// AskThreadDump tells if thread dumps must be included.
+func (a *AnswersPrompter) AskThreadDump() (bool, error) {
+	return boolOrDefault(a.Answers.IncludeThreadDump, true), nil
+}
+
+// AskLogsDateRange tells the first and last days of the logs to include.
+func (a *AnswersPrompter) AskLogsDateRange(defaultStartDate string, defaultEndDate string) (string, string, error) {
+	startDate := stringOrDefault(a.Answers.LogsStartDate, defaultStartDate)
+	if err := validateDate(startDate); err != nil {
+		return "", "", fmt.Errorf("invalid logsStartDate answer: %w", err)
+	}
+	endDate := stringOrDefault(a.Answers.LogsEndDate, defaultEndDate)
+	if err := validateDate(endDate); err != nil {
+		return "", "", fmt.Errorf("invalid logsEndDate answer: %w", err)
+	}
+	return startDate, endDate, nil
+}
+
+// AskThreadDumpCount tells how many thread dumps must be included.
+func (a *AnswersPrompter) AskThreadDumpCount(defaultCount uint) (uint, error) {
+	if a.Answers.ThreadDumpCount == nil {
+		return defaultCount, nil
+	}
+	return *a.Answers.ThreadDumpCount, nil
+}
+
+// AskThreadDumpInterval tells the interval between thread dumps, in milliseconds.
+func (a *AnswersPrompter) AskThreadDumpInterval(defaultInterval uint) (uint, error) {
+	if a.Answers.ThreadDumpInterval == nil {
+		return defaultInterval, nil
+	}
+	return *a.Answers.ThreadDumpInterval, nil
+}
+
+// AskDescription tells the description of the Support Bundle.
+func (a *AnswersPrompter) AskDescription(defaultDescription string) (string, error) {
+	return stringOrDefault(a.Answers.Description, defaultDescription), nil
+}
+
+// ConfirmOptions tells if the options are confirmed.
+func (a *AnswersPrompter) ConfirmOptions(string) (bool, error) {
+	return boolOrDefault(a.Answers.Confirm, true), nil
+}
+
+// AskCaseNumber tells the JFrog Support case number, which has no default answer.
+func (a *AnswersPrompter) AskCaseNumber() (CaseNumber, error) {
+	if a.Answers.CaseNumber == "" {
+		return "", errors.New("missing caseNumber answer")
+	}
+	if err := validatePathSegment("case number", a.Answers.CaseNumber); err != nil {
+		return "", err
+	}
+	return CaseNumber(a.Answers.CaseNumber), nil
+}
+
+// AskSourceServerID tells which of the given services of JFrog CLI configuration to create the Support Bundle on.
+func (a *AnswersPrompter) AskSourceServerID(serverIDs []string, defaultServerID string) (string, error) {
+	return selectAnswer("sourceServerId", a.Answers.SourceServerID, serverIDs, defaultServerID)
+}
+
+// AskTargetServerID tells which of the given services to upload the Support Bundle to.
+func (a *AnswersPrompter) AskTargetServerID(serverIDs []string, defaultServerID string) (string, error) {
+	return selectAnswer("targetServerId", a.Answers.TargetServerID, serverIDs, defaultServerID)
+}
+
+func selectAnswer(question string, answer string, options []string, defaultOption string) (string, error) {
+	if answer == "" {
+		return defaultOption, nil
+	}
+	for _, option := range options {
+		if option == answer {
+			return answer, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s answer %q, expected one of: %v", question, answer, options)
+}
+
+func boolOrDefault(value *bool, defaultValue bool) bool {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
@@ -0,0 +1,164 @@
+package actions
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ReadAnswers(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectAnswers Answers
+		expectErr     string
+	}{
+		{
+			name:    "YAML",
+			content: "caseNumber: \"1234\"\nincludeLogs: false\nthreadDumpCount: 3\n",
+			expectAnswers: Answers{
+				CaseNumber:      "1234",
+				IncludeLogs:     boolPointer(false),
+				ThreadDumpCount: uintPointer(3),
+			},
+		},
+		{
+			name:    "JSON",
+			content: `{"sourceServerId": "dev", "confirm": true}`,
+			expectAnswers: Answers{
+				SourceServerID: "dev",
+				Confirm:        boolPointer(true),
+			},
+		},
+		{
+			name:      "empty",
+			content:   " \n",
+			expectErr: "no answers given in stdin",
+		},
+		{
+			name:    "unknown question",
+			content: "includeLog: false\n",
+			expectErr: "invalid answers from stdin: yaml: unmarshal errors:\n" +
+				"  line 1: field includeLog not found in type actions.Answers",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			prompter, err := ReadAnswers("stdin", strings.NewReader(test.content))
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, cmp.Diff(test.expectAnswers, prompter.Answers))
+		})
+	}
+}
+
+func Test_AnswersPrompter_GetOptions(t *testing.T) {
+	tests := []struct {
+		name              string
+		answers           Answers
+		expectDescription string
+		expectParameters  http.SupportBundleParameters
+		expectErr         string
+	}{
+		{
+			name: "defaults",
+			expectParameters: http.SupportBundleParameters{
+				Configuration: true,
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-31",
+					EndDate:   "2012-11-01",
+				},
+				System:     true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 1},
+			},
+		},
+		{
+			name: "answered",
+			answers: Answers{
+				IncludeLogs:        boolPointer(true),
+				LogsStartDate:      "2012-10-25",
+				IncludeSystem:      boolPointer(false),
+				ThreadDumpCount:    uintPointer(3),
+				ThreadDumpInterval: uintPointer(500),
+				Description:        "Slow startup",
+			},
+			expectDescription: "Slow startup",
+			expectParameters: http.SupportBundleParameters{
+				Configuration: true,
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-25",
+					EndDate:   "2012-11-01",
+				},
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 3, Interval: 500},
+			},
+		},
+		{
+			name:      "invalid date",
+			answers:   Answers{LogsEndDate: "yesterday"},
+			expectErr: `invalid logsEndDate answer: "yesterday" is not a date in the YYYY-MM-DD format`,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			provider := NewPromptOptionsProvider(func() time.Time {
+				return time.Unix(1351807721, 0).UTC()
+			}, &AnswersPrompter{Answers: test.answers})
+			options, err := provider.GetOptions("foo")
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			expectDescription := test.expectDescription
+			if expectDescription == "" {
+				expectDescription = "Generated on 2012-11-01T22:08:41Z"
+			}
+			assert.Equal(t, expectDescription, options.Description)
+			assert.Empty(t, cmp.Diff(test.expectParameters, *options.Parameters))
+		})
+	}
+}
+
+func TestAnswersPrompter_AskCaseNumber(t *testing.T) {
+	caseNumber, err := (&AnswersPrompter{Answers: Answers{CaseNumber: "1234"}}).AskCaseNumber()
+	require.NoError(t, err)
+	assert.Equal(t, CaseNumber("1234"), caseNumber)
+
+	_, err = (&AnswersPrompter{}).AskCaseNumber()
+	assert.EqualError(t, err, "missing caseNumber answer")
+}
+
+func TestAnswersPrompter_AskServerID(t *testing.T) {
+	prompter := &AnswersPrompter{Answers: Answers{SourceServerID: "dev", TargetServerID: "staging"}}
+	serverID, err := prompter.AskSourceServerID([]string{"dev", "prod"}, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "dev", serverID)
+
+	_, err = prompter.AskTargetServerID([]string{"dev", "prod"}, "prod")
+	assert.EqualError(t, err, `invalid targetServerId answer "staging", expected one of: [dev prod]`)
+
+	serverID, err = (&AnswersPrompter{}).AskTargetServerID([]string{"dev", "prod"}, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", serverID)
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
+
+func uintPointer(value uint) *uint {
+	return &value
+}
@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"crypto/md5"  // nolint: gosec // MD5 is one of the checksums stored by Artifactory, not used for security
+	"crypto/sha1" // nolint: gosec // SHA-1 is one of the checksums stored by Artifactory, not used for security
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// checksumCalculator computes the checksums and the size of everything written to it.
+type checksumCalculator struct {
+	sha256 hash.Hash
+	sha1   hash.Hash
+	md5    hash.Hash
+	writer io.Writer
+	size   int64
+}
+
+func newChecksumCalculator() *checksumCalculator {
+	c := &checksumCalculator{
+		sha256: sha256.New(),
+		sha1:   sha1.New(), // nolint: gosec
+		md5:    md5.New(),  // nolint: gosec
+	}
+	c.writer = io.MultiWriter(c.sha256, c.sha1, c.md5)
+	return c
+}
+
+func (c *checksumCalculator) Write(p []byte) (int, error) {
+	n, err := c.writer.Write(p)
+	c.size += int64(n)
+	return n, err
+}
+
+func (c *checksumCalculator) checksums() flunkyhttp.Checksums {
+	return flunkyhttp.Checksums{
+		SHA256: hex.EncodeToString(c.sha256.Sum(nil)),
+		SHA1:   hex.EncodeToString(c.sha1.Sum(nil)),
+		MD5:    hex.EncodeToString(c.md5.Sum(nil)),
+	}
+}
+
+// ComputeChecksums computes the checksums of a local Support Bundle archive.
+func ComputeChecksums(sbFilePath string) (flunkyhttp.Checksums, error) {
+	file, err := os.Open(sbFilePath)
+	if err != nil {
+		return flunkyhttp.Checksums{}, err
+	}
+	defer handleClose(file)
+	calculator := newChecksumCalculator()
+	if _, err = io.Copy(calculator, file); err != nil {
+		return flunkyhttp.Checksums{}, err
+	}
+	return calculator.checksums(), nil
+}
+
+// verifyChecksums checks that the checksums reported by Artifactory for an uploaded archive match the expected ones.
+// The checksums missing from the report are not verified.
+func verifyChecksums(expected flunkyhttp.Checksums, actual flunkyhttp.Checksums) error {
+	pairs := []struct {
+		algorithm string
+		expected  string
+		actual    string
+	}{
+		{algorithm: "SHA-256", expected: expected.SHA256, actual: actual.SHA256},
+		{algorithm: "SHA-1", expected: expected.SHA1, actual: actual.SHA1},
+		{algorithm: "MD5", expected: expected.MD5, actual: actual.MD5},
+	}
+	verified := 0
+	for _, pair := range pairs {
+		if pair.actual == "" || pair.expected == "" {
+			continue
+		}
+		if !strings.EqualFold(pair.expected, pair.actual) {
+			return &ChecksumMismatchError{Algorithm: pair.algorithm, Expected: pair.expected, Actual: pair.actual}
+		}
+		verified++
+	}
+	if verified == 0 {
+		log.Warn("Artifactory did not report any checksum for the uploaded Support Bundle, its integrity is not verified")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"errors"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func Test_ComputeChecksums(t *testing.T) {
+	file, err := ioutil.TempFile("", "sb-*.zip")
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(file.Name()) }()
+	_, err = file.WriteString("helloworld")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	checksums, err := ComputeChecksums(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, helloworldChecksums, checksums)
+
+	_, err = ComputeChecksums("file/does/not/exist")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_verifyChecksums(t *testing.T) {
+	assert.NoError(t, verifyChecksums(helloworldChecksums, helloworldChecksums))
+	assert.NoError(t, verifyChecksums(helloworldChecksums, flunkyhttp.Checksums{SHA1: helloworldChecksums.SHA1}))
+	assert.NoError(t, verifyChecksums(helloworldChecksums, flunkyhttp.Checksums{}))
+
+	err := verifyChecksums(helloworldChecksums, flunkyhttp.Checksums{MD5: "0000"})
+	var mismatch *ChecksumMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, &ChecksumMismatchError{Algorithm: "MD5", Expected: helloworldChecksums.MD5, Actual: "0000"},
+		mismatch)
+}
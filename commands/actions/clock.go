@@ -5,6 +5,13 @@ import "time"
 // Clock is a provider of time
 type Clock func() time.Time
 
+// ClockIn gives a Clock telling the current time in the given location.
+func ClockIn(location *time.Location) Clock {
+	return func() time.Time {
+		return time.Now().In(location)
+	}
+}
+
 func formattedString(t time.Time) string {
-	return t.UTC().Format(time.RFC3339)
+	return t.Format(time.RFC3339)
 }
@@ -15,7 +15,7 @@ func Test_FormattedString(t *testing.T) {
 		expect string
 	}{
 		{
-			in:     time.Unix(0, 0),
+			in:     time.Unix(0, 0).UTC(),
 			expect: "1970-01-01T00:00:00Z",
 		},
 		{
@@ -24,7 +24,7 @@ func Test_FormattedString(t *testing.T) {
 		},
 		{
 			in:     time.Date(2020, 12, 3, 22, 10, 0, 13, newYork),
-			expect: "2020-12-04T03:10:00Z",
+			expect: "2020-12-03T22:10:00-05:00",
 		},
 	}
 
@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"fmt"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
@@ -9,7 +10,7 @@ import (
 
 type createSupportBundleHTTPClient interface {
 	GetURL() string
-	CreateSupportBundle(payload flunkyhttp.SupportBundleCreationOptions) (int, []byte, error)
+	CreateSupportBundle(ctx context.Context, payload flunkyhttp.SupportBundleCreationOptions) (int, []byte, error)
 }
 
 // OptionsProvider provides options for the creation of a Support Bundle.
@@ -18,28 +19,34 @@ type OptionsProvider interface {
 }
 
 // CreateSupportBundle creates a Support Bundle.
-func CreateSupportBundle(httpClient createSupportBundleHTTPClient, caseNumber CaseNumber,
+func CreateSupportBundle(ctx context.Context, httpClient createSupportBundleHTTPClient, caseNumber CaseNumber,
 	optionsProvider OptionsProvider) (BundleID, error) {
 	log.Debug(fmt.Sprintf("Create Support Bundle %s on %s", caseNumber, httpClient.GetURL()))
 	request, err := optionsProvider.GetOptions(caseNumber)
 	if err != nil {
 		return "", err
 	}
-	responseStatus, body, err := httpClient.CreateSupportBundle(request)
+	return CreateSupportBundleWithOptions(ctx, httpClient, request)
+}
+
+// CreateSupportBundleWithOptions creates a Support Bundle with options which have already been gathered.
+func CreateSupportBundleWithOptions(ctx context.Context, httpClient createSupportBundleHTTPClient,
+	request flunkyhttp.SupportBundleCreationOptions) (BundleID, error) {
+	responseStatus, body, err := httpClient.CreateSupportBundle(ctx, request)
 	if err != nil {
-		return "", err
+		return "", Categorize(CategoryCreation, err)
 	}
 	log.Debug(fmt.Sprintf("Got %d\n%s", responseStatus, string(body)))
 	if responseStatus != http.StatusOK {
-		return "", fmt.Errorf("http request failed with: %d", responseStatus)
+		return "", Categorize(CategoryCreation, newHTTPError(responseStatus, body))
 	}
 	json, err := flunkyhttp.ParseJSON(body)
 	if err != nil {
-		return "", err
+		return "", Categorize(CategoryCreation, err)
 	}
 	id, err := json.GetString("id")
 	if err != nil {
-		return "", err
+		return "", Categorize(CategoryCreation, err)
 	}
 	return BundleID(id), nil
 }
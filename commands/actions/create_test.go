@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
@@ -21,8 +22,8 @@ func (c *createSupportBundleHTTPClientStub) GetURL() string {
 	return "stub"
 }
 
-func (c *createSupportBundleHTTPClientStub) CreateSupportBundle(payload http.SupportBundleCreationOptions) (status int,
-	responseBytes []byte, err error) {
+func (c *createSupportBundleHTTPClientStub) CreateSupportBundle(_ context.Context,
+	payload http.SupportBundleCreationOptions) (status int, responseBytes []byte, err error) {
 	c.actualPayload = payload
 	return c.statusCode, []byte(c.response), c.err
 }
@@ -57,7 +58,7 @@ func Test_CreateSupportBundle(t *testing.T) {
 				response:   `{}`,
 				err:        nil,
 			},
-			expectErr: "http request failed with: 400",
+			expectErr: "http request failed with: 400 Bad Request",
 		},
 		{
 			name: "bad json",
@@ -114,7 +115,7 @@ func Test_CreateSupportBundle(t *testing.T) {
 			if optionsProvider == nil {
 				optionsProvider = &DefaultOptionsProvider{getDate: clock}
 			}
-			id, err := CreateSupportBundle(&test.givenHTTPClient, caseNumber, optionsProvider)
+			id, err := CreateSupportBundle(context.Background(), &test.givenHTTPClient, caseNumber, optionsProvider)
 			if test.expectErr != "" {
 				require.Error(t, err)
 				require.EqualError(t, err, test.expectErr)
@@ -0,0 +1,72 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"net/http"
+	"strings"
+)
+
+const storageAPIPath = "/api/storage/"
+
+// findUploadedSupportBundle gives the URL of an artifact of the case folder having the same SHA-256 as the Support
+// Bundle, or an empty string if there is none. As this is only an optimization, search failures are not reported.
+func findUploadedSupportBundle(ctx context.Context, client uploadHTTPClient, target UploadTarget,
+	checksums flunkyhttp.Checksums) string {
+	if checksums.SHA256 == "" {
+		return ""
+	}
+	statusCode, respBytes, err := client.SearchSupportBundlesByChecksum(ctx, target.RepoKey, checksums.SHA256)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to search for an uploaded Support Bundle: %+v", err))
+		return ""
+	}
+	if statusCode != http.StatusOK {
+		log.Debug(fmt.Sprintf("Failed to search for an uploaded Support Bundle: %d %s", statusCode,
+			http.StatusText(statusCode)))
+		return ""
+	}
+	var result flunkyhttp.ChecksumSearchResult
+	if err = json.Unmarshal(respBytes, &result); err != nil {
+		log.Debug(fmt.Sprintf("Failed to read the checksum search result: %+v", err))
+		return ""
+	}
+
+	caseFolder := fmt.Sprintf("%s/%s/", target.RepoKey, target.CaseNumber)
+	for _, item := range result.Results {
+		i := strings.Index(item.URI, storageAPIPath)
+		if i < 0 {
+			continue
+		}
+		path := item.URI[i+len(storageAPIPath):]
+		if strings.HasPrefix(path, caseFolder) {
+			return client.GetURL() + path
+		}
+	}
+	return ""
+}
+
+// deploySupportBundleChecksum attempts to deploy the Support Bundle by checksum. It tells whether the deploy succeeded,
+// in which case the upload of the content can be skipped.
+func deploySupportBundleChecksum(ctx context.Context, client uploadHTTPClient, target UploadTarget, filename string,
+	checksums flunkyhttp.Checksums, properties *servicesutils.Properties) (bool, error) {
+	if checksums.SHA1 == "" && checksums.SHA256 == "" {
+		return false, nil
+	}
+	statusCode, respBytes, err := client.DeploySupportBundleChecksum(ctx, target.RepoKey, string(target.CaseNumber),
+		filename, checksums, properties)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Checksum deploy failed: %+v", err))
+		return false, nil
+	}
+	if statusCode != http.StatusCreated {
+		log.Debug(fmt.Sprintf("Checksum deploy not possible: %d %s", statusCode, http.StatusText(statusCode)))
+		return false, nil
+	}
+	log.Debug("Support Bundle deployed by checksum")
+	return true, verifyUploadResponse(checksums, respBytes)
+}
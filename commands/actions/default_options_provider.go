@@ -3,23 +3,31 @@ package actions
 import (
 	"fmt"
 	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
-	"time"
+	"strings"
 )
 
+// supportBundleNamePrefix starts the name of every Support Bundle created by this plugin.
+const supportBundleNamePrefix = "JFrog Support Case number "
+
+// IsCreatedByFlunky tells if a Support Bundle name matches the names given by this plugin.
+func IsCreatedByFlunky(supportBundleName string) bool {
+	return strings.HasPrefix(supportBundleName, supportBundleNamePrefix)
+}
+
 // DefaultOptionsProvider provides default options for the creation of a Support Bundle.
 type DefaultOptionsProvider struct {
 	getDate Clock
 }
 
 // NewDefaultOptionsProvider creates a new DefaultOptionsProvider
-func NewDefaultOptionsProvider() *DefaultOptionsProvider {
-	return &DefaultOptionsProvider{getDate: time.Now}
+func NewDefaultOptionsProvider(getDate Clock) *DefaultOptionsProvider {
+	return &DefaultOptionsProvider{getDate: getDate}
 }
 
 // GetOptions gets the default options.
 func (p *DefaultOptionsProvider) GetOptions(caseNumber CaseNumber) (flunkyhttp.SupportBundleCreationOptions, error) {
 	return flunkyhttp.SupportBundleCreationOptions{
-		Name:        fmt.Sprintf("JFrog Support Case number %s", caseNumber),
+		Name:        fmt.Sprintf("%s%s", supportBundleNamePrefix, caseNumber),
 		Description: fmt.Sprintf("Generated on %s", formattedString(p.getDate())),
 		Parameters:  nil,
 	}, nil
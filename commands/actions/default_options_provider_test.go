@@ -20,7 +20,14 @@ func TestDefaultOptionsProvider_GetOptions(t *testing.T) {
 	require.Empty(t, cmp.Diff(o,
 		http.SupportBundleCreationOptions{
 			Name:        "JFrog Support Case number foo",
-			Description: "Generated on 2020-12-04T03:10:00Z",
+			Description: "Generated on 2020-12-03T22:10:00-05:00",
 			Parameters:  nil,
 		}))
 }
+
+func TestIsCreatedByFlunky(t *testing.T) {
+	o, err := NewDefaultOptionsProvider(time.Now).GetOptions("1234")
+	require.NoError(t, err)
+	require.True(t, IsCreatedByFlunky(o.Name))
+	require.False(t, IsCreatedByFlunky("Nightly Support Bundle"))
+}
@@ -0,0 +1,28 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"net/http"
+)
+
+type deleteSupportBundleHTTPClient interface {
+	GetURL() string
+	DeleteSupportBundle(ctx context.Context, bundleID string) (int, []byte, error)
+}
+
+// DeleteSupportBundle deletes a Support Bundle.
+func DeleteSupportBundle(ctx context.Context, client deleteSupportBundleHTTPClient, bundleID BundleID) error {
+	log.Debug(fmt.Sprintf("Delete Support Bundle %s from %s", bundleID, client.GetURL()))
+	statusCode, body, err := client.DeleteSupportBundle(ctx, string(bundleID))
+	if err != nil {
+		return err
+	}
+
+	log.Debug(fmt.Sprintf("Got HTTP response status: %d, body: %s", statusCode, body))
+	if statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+		return newHTTPError(statusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type deleteClientStub struct {
+	statusCode       int
+	err              error
+	deletedBundleIDs []string
+}
+
+func (s *deleteClientStub) GetURL() string {
+	return "stub"
+}
+
+func (s *deleteClientStub) DeleteSupportBundle(_ context.Context, bundleID string) (int, []byte, error) {
+	s.deletedBundleIDs = append(s.deletedBundleIDs, bundleID)
+	return s.statusCode, nil, s.err
+}
+
+func Test_DeleteSupportBundle(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientStub *deleteClientStub
+		expectErr  string
+	}{
+		{
+			name:       "success",
+			clientStub: &deleteClientStub{statusCode: http.StatusOK},
+		},
+		{
+			name:       "no content",
+			clientStub: &deleteClientStub{statusCode: http.StatusNoContent},
+		},
+		{
+			name:       "not found",
+			clientStub: &deleteClientStub{statusCode: http.StatusNotFound},
+			expectErr:  "http request failed with: 404 Not Found",
+		},
+		{
+			name:       "client error",
+			clientStub: &deleteClientStub{err: errors.New("oops")},
+			expectErr:  "oops",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := DeleteSupportBundle(context.Background(), test.clientStub, "bundleID")
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, []string{"bundleID"}, test.clientStub.deletedBundleIDs)
+		})
+	}
+}
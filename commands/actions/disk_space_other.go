@@ -0,0 +1,14 @@
+//go:build !darwin && !freebsd && !linux && !windows
+// +build !darwin,!freebsd,!linux,!windows
+
+package actions
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// freeDiskSpace gives the number of bytes available to this user on the volume holding path.
+func freeDiskSpace(path string) (uint64, error) {
+	return 0, fmt.Errorf("free disk space of %s is not available on %s", path, runtime.GOOS)
+}
@@ -0,0 +1,15 @@
+//go:build darwin || freebsd || linux
+// +build darwin freebsd linux
+
+package actions
+
+import "golang.org/x/sys/unix"
+
+// freeDiskSpace gives the number of bytes available to this user on the volume holding path.
+func freeDiskSpace(path string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
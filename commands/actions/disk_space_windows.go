@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package actions
+
+import "golang.org/x/sys/windows"
+
+// freeDiskSpace gives the number of bytes available to this user on the volume holding path.
+func freeDiskSpace(path string) (uint64, error) {
+	directoryName, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var available, total, free uint64
+	if err = windows.GetDiskFreeSpaceEx(directoryName, &available, &total, &free); err != nil {
+		return 0, err
+	}
+	return available, nil
+}
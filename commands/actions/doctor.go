@@ -0,0 +1,226 @@
+package actions
+
+import (
+	"context"
+	"crypto/md5"  // nolint: gosec // MD5 is one of the checksums stored by Artifactory, not used for security
+	"crypto/sha1" // nolint: gosec // SHA-1 is one of the checksums stored by Artifactory, not used for security
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CheckStatus is the outcome of a check of the doctor command.
+type CheckStatus string
+
+const (
+	// CheckPass is the status of a check which found nothing wrong.
+	CheckPass CheckStatus = "pass"
+	// CheckWarn is the status of a check which found something that may make support-case fail.
+	CheckWarn CheckStatus = "warn"
+	// CheckFail is the status of a check which found something that makes support-case fail.
+	CheckFail CheckStatus = "fail"
+)
+
+const (
+	// minFreeTempSpace is the free space of the temp directory below which a warning is given, as the Support Bundle
+	// of a busy service easily takes hundreds of MiB.
+	minFreeTempSpace = 1 << 30
+	// minSupportBundleAPIVersion is the major version of Artifactory providing the Support Bundle API.
+	minSupportBundleAPIVersion = 7
+	doctorProbeDirectory       = "sb-flunky-doctor"
+)
+
+// CheckResult is the outcome of a check, with a hint on how to fix what did not pass. A failed check tells the
+// category of the error support-case would fail with.
+type CheckResult struct {
+	Name     string        `json:"name" yaml:"name"`
+	Status   CheckStatus   `json:"status" yaml:"status"`
+	Category ErrorCategory `json:"category,omitempty" yaml:"category,omitempty"`
+	Message  string        `json:"message" yaml:"message"`
+	Hint     string        `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+type pingHTTPClient interface {
+	GetURL() string
+	Ping(ctx context.Context) (int, []byte, error)
+}
+
+type sourceCheckHTTPClient interface {
+	pingHTTPClient
+	versionHTTPClient
+	ListSupportBundles(ctx context.Context) (int, []byte, error)
+}
+
+type targetCheckHTTPClient interface {
+	pingHTTPClient
+	DeploySupportBundleChecksum(ctx context.Context, repoKey string, supportCaseDirectory string, filename string,
+		checksums flunkyhttp.Checksums, properties *servicesutils.Properties) (int, []byte, error)
+}
+
+// CheckSource checks that the source service is reachable, supports the Support Bundle API, and that its credentials
+// may list Support Bundles. The permission to create them cannot be checked without creating one, so it is not.
+func CheckSource(ctx context.Context, client sourceCheckHTTPClient) []CheckResult {
+	ping := checkPing(ctx, "source-ping", client)
+	if ping.Status == CheckFail {
+		return []CheckResult{ping, skippedCheck("source-version", "source"), skippedCheck("source-list-permission", "source")}
+	}
+	return []CheckResult{ping, checkSourceVersion(ctx, client), checkListPermission(ctx, client)}
+}
+
+// CheckTarget checks that the target service is reachable, and that its credentials may deploy to the target
+// repository. The deploy permission is checked with a deploy by checksum of content Artifactory does not store, which
+// is rejected without storing anything.
+func CheckTarget(ctx context.Context, client targetCheckHTTPClient, repoKey string, now Clock) []CheckResult {
+	ping := checkPing(ctx, "target-ping", client)
+	if ping.Status == CheckFail {
+		return []CheckResult{ping, skippedCheck("target-permissions", "target")}
+	}
+	return []CheckResult{ping, checkDeployPermission(ctx, client, repoKey, now)}
+}
+
+// CheckTempDir checks that Support Bundles can be downloaded to the given temp directory.
+func CheckTempDir(dir string) CheckResult {
+	return checkTempDir(dir, freeDiskSpace)
+}
+
+func checkPing(ctx context.Context, name string, client pingHTTPClient) CheckResult {
+	status, _, err := client.Ping(ctx)
+	switch {
+	case err != nil:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+			Message: fmt.Sprintf("%s is not reachable: %v", client.GetURL(), err),
+			Hint: "Check the URL of the server with 'jfrog rt config show', and that this host can connect to it, " +
+				"through a proxy if needed."}
+	case status == http.StatusUnauthorized:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryAuthentication,
+			Message: fmt.Sprintf("%s rejected the credentials: %d %s", client.GetURL(), status, http.StatusText(status)),
+			Hint:    "Update the credentials of the server with 'jfrog rt config'."}
+	case status != http.StatusOK:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+			Message: fmt.Sprintf("%s answered: %d %s", client.GetURL(), status, http.StatusText(status)),
+			Hint:    "Check that the URL of the server points to Artifactory, usually ending with /artifactory/."}
+	}
+	return CheckResult{Name: name, Status: CheckPass, Message: fmt.Sprintf("%s is reachable", client.GetURL())}
+}
+
+func skippedCheck(name string, service string) CheckResult {
+	return CheckResult{Name: name, Status: CheckWarn, Message: fmt.Sprintf("Skipped, the %s service is not reachable",
+		service)}
+}
+
+func checkSourceVersion(ctx context.Context, client sourceCheckHTTPClient) CheckResult {
+	const name = "source-version"
+	version, err := GetVersion(ctx, client)
+	if err != nil {
+		category := CategoryOf(Categorize(CategoryConfiguration, err))
+		hint := "Check that the URL of the source server points to Artifactory with 'jfrog rt config show'."
+		if category == CategoryAuthentication {
+			hint = "Update the credentials of the source server with 'jfrog rt config'."
+		}
+		return CheckResult{Name: name, Status: CheckFail, Category: category,
+			Message: fmt.Sprintf("Failed to get the version: %v", err), Hint: hint}
+	}
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil || major < minSupportBundleAPIVersion {
+		return CheckResult{Name: name, Status: CheckWarn,
+			Message: fmt.Sprintf("Artifactory %s may not provide the Support Bundle API", version),
+			Hint:    fmt.Sprintf("The Support Bundle API is provided by Artifactory %d and later.", minSupportBundleAPIVersion)}
+	}
+	return CheckResult{Name: name, Status: CheckPass, Message: fmt.Sprintf("Artifactory %s", version)}
+}
+
+func checkListPermission(ctx context.Context, client sourceCheckHTTPClient) CheckResult {
+	const name = "source-list-permission"
+	status, _, err := client.ListSupportBundles(ctx)
+	switch {
+	case err != nil:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+			Message: fmt.Sprintf("Failed to list Support Bundles: %v", err)}
+	case status == http.StatusOK:
+		return CheckResult{Name: name, Status: CheckPass, Message: "The credentials may list Support Bundles"}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryAuthentication,
+			Message: fmt.Sprintf("The credentials may not list Support Bundles: %d %s", status, http.StatusText(status)),
+			Hint: "Support Bundles can only be listed and created by an admin, configure the source server with the " +
+				"credentials of an admin user or an admin access token with 'jfrog rt config'."}
+	case status == http.StatusNotFound:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+			Message: "The Support Bundle API was not found", Hint: fmt.Sprintf(
+				"The Support Bundle API is provided by Artifactory %d and later.", minSupportBundleAPIVersion)}
+	}
+	return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+		Message: fmt.Sprintf("Failed to list Support Bundles: %d %s", status, http.StatusText(status))}
+}
+
+func checkTempDir(dir string, getFreeSpace func(path string) (uint64, error)) CheckResult {
+	const name = "temp-space"
+	const hint = "Free some space, set JFROG_CLI_TEMP_DIR to a directory of a larger volume, or use --stream to " +
+		"skip the local file."
+	file, err := ioutil.TempFile(dir, "sb-flunky-doctor-")
+	if err != nil {
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+			Message: fmt.Sprintf("Failed to write to %s: %v", dir, err),
+			Hint:    "Set JFROG_CLI_TEMP_DIR to a writable directory, or use --stream to skip the local file."}
+	}
+	if err = file.Close(); err == nil {
+		err = os.Remove(file.Name())
+	}
+	if err != nil {
+		return CheckResult{Name: name, Status: CheckWarn, Message: fmt.Sprintf("Failed to remove %s: %v", file.Name(), err)}
+	}
+	free, err := getFreeSpace(dir)
+	switch {
+	case err != nil:
+		return CheckResult{Name: name, Status: CheckWarn,
+			Message: fmt.Sprintf("Failed to get the free space of %s: %v", dir, err), Hint: hint}
+	case free < minFreeTempSpace:
+		return CheckResult{Name: name, Status: CheckWarn, Message: fmt.Sprintf("Only %d MiB free in %s", free>>20, dir),
+			Hint: hint}
+	}
+	return CheckResult{Name: name, Status: CheckPass, Message: fmt.Sprintf("%d MiB free in %s", free>>20, dir)}
+}
+
+func checkDeployPermission(ctx context.Context, client targetCheckHTTPClient, repoKey string, now Clock) CheckResult {
+	const name = "target-permissions"
+	timestamp := now()
+	filename := fmt.Sprintf("probe-%s.txt", timestamp.UTC().Format(timestampLayout))
+	status, body, err := client.DeploySupportBundleChecksum(ctx, repoKey, doctorProbeDirectory, filename,
+		probeChecksums(fmt.Sprintf("sb-flunky doctor probe %d", timestamp.UnixNano())), nil)
+	switch {
+	case err != nil:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryConfiguration,
+			Message: fmt.Sprintf("Failed to deploy to %s: %v", repoKey, err)}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return CheckResult{Name: name, Status: CheckFail, Category: CategoryAuthentication,
+			Message: fmt.Sprintf("The credentials may not deploy to %s: %d %s", repoKey, status, http.StatusText(status)),
+			Hint: fmt.Sprintf("Give the deploy permission on %s to the user of the target server, or choose another "+
+				"repository with --target-repo.", repoKey)}
+	case status == http.StatusNotFound && strings.Contains(strings.ToLower(string(body)), "checksum"),
+		status == http.StatusCreated:
+		return CheckResult{Name: name, Status: CheckPass, Message: fmt.Sprintf("The credentials may deploy to %s", repoKey)}
+	}
+	return CheckResult{Name: name, Status: CheckFail, Category: CategoryUploadRejected,
+		Message: fmt.Sprintf("Failed to deploy to %s: %d %s %s", repoKey, status, http.StatusText(status),
+			strings.TrimSpace(string(body))),
+		Hint: fmt.Sprintf("Check that the repository %s exists on the target server, or choose another one with "+
+			"--target-repo.", repoKey)}
+}
+
+// probeChecksums gives the checksums of the given content, which is never sent.
+func probeChecksums(content string) flunkyhttp.Checksums {
+	sha256Sum := sha256.Sum256([]byte(content))
+	sha1Sum := sha1.Sum([]byte(content)) // nolint: gosec
+	md5Sum := md5.Sum([]byte(content))   // nolint: gosec
+	return flunkyhttp.Checksums{
+		SHA256: hex.EncodeToString(sha256Sum[:]),
+		SHA1:   hex.EncodeToString(sha1Sum[:]),
+		MD5:    hex.EncodeToString(md5Sum[:]),
+	}
+}
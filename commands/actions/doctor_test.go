@@ -0,0 +1,252 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type doctorClientStub struct {
+	pingStatus   int
+	pingErr      error
+	version      string
+	versionCode  int
+	versionErr   error
+	listStatus   int
+	deployStatus int
+	deployBody   string
+	deployed     []string
+}
+
+func (s *doctorClientStub) GetURL() string {
+	return "http://stub/"
+}
+
+func (s *doctorClientStub) Ping(_ context.Context) (int, []byte, error) {
+	return s.pingStatus, []byte("OK"), s.pingErr
+}
+
+func (s *doctorClientStub) GetVersion(_ context.Context) (int, []byte, error) {
+	if s.versionCode == 0 {
+		s.versionCode = http.StatusOK
+	}
+	return s.versionCode, []byte(fmt.Sprintf(`{"version":"%s"}`, s.version)), s.versionErr
+}
+
+func (s *doctorClientStub) ListSupportBundles(_ context.Context) (int, []byte, error) {
+	return s.listStatus, []byte(`{"count":0,"bundles":[]}`), nil
+}
+
+func (s *doctorClientStub) DeploySupportBundleChecksum(_ context.Context, repoKey string, supportCaseDirectory string,
+	filename string, checksums flunkyhttp.Checksums, properties *servicesutils.Properties) (int, []byte, error) {
+	s.deployed = append(s.deployed, repoKey+"/"+supportCaseDirectory+"/"+filename)
+	return s.deployStatus, []byte(s.deployBody), nil
+}
+
+func Test_CheckSource(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *doctorClientStub
+		expectStatus   []CheckStatus
+		expectCategory ErrorCategory
+		expectHint     string
+	}{
+		{
+			name:         "ready",
+			client:       &doctorClientStub{pingStatus: http.StatusOK, version: "7.12.5", listStatus: http.StatusOK},
+			expectStatus: []CheckStatus{CheckPass, CheckPass, CheckPass},
+		},
+		{
+			name:         "not reachable",
+			client:       &doctorClientStub{pingErr: errors.New("connection refused")},
+			expectStatus: []CheckStatus{CheckFail, CheckWarn, CheckWarn},
+			expectHint: "Check the URL of the server with 'jfrog rt config show', and that this host can connect to it, " +
+				"through a proxy if needed.",
+		},
+		{
+			name:         "not an admin",
+			client:       &doctorClientStub{pingStatus: http.StatusOK, version: "7.12.5", listStatus: http.StatusForbidden},
+			expectStatus: []CheckStatus{CheckPass, CheckPass, CheckFail},
+			expectHint: "Support Bundles can only be listed and created by an admin, configure the source server " +
+				"with the credentials of an admin user or an admin access token with 'jfrog rt config'.",
+		},
+		{
+			name: "version rejected",
+			client: &doctorClientStub{pingStatus: http.StatusOK, versionCode: http.StatusUnauthorized,
+				listStatus: http.StatusOK},
+			expectStatus:   []CheckStatus{CheckPass, CheckFail, CheckPass},
+			expectCategory: CategoryAuthentication,
+			expectHint:     "Update the credentials of the source server with 'jfrog rt config'.",
+		},
+		{
+			name: "version unavailable",
+			client: &doctorClientStub{pingStatus: http.StatusOK, versionErr: errors.New("connection reset by peer"),
+				listStatus: http.StatusOK},
+			expectStatus:   []CheckStatus{CheckPass, CheckFail, CheckPass},
+			expectCategory: CategoryConfiguration,
+			expectHint:     "Check that the URL of the source server points to Artifactory with 'jfrog rt config show'.",
+		},
+		{
+			name:         "old version",
+			client:       &doctorClientStub{pingStatus: http.StatusOK, version: "6.23.3", listStatus: http.StatusNotFound},
+			expectStatus: []CheckStatus{CheckPass, CheckWarn, CheckFail},
+			expectHint:   "The Support Bundle API is provided by Artifactory 7 and later.",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			results := CheckSource(context.Background(), test.client)
+			assert.Empty(t, cmp.Diff(test.expectStatus, statuses(results)))
+			assert.Equal(t, test.expectHint, lastHint(results))
+			if test.expectCategory != "" {
+				assert.Equal(t, test.expectCategory, results[1].Category)
+			}
+		})
+	}
+}
+
+func Test_CheckTarget(t *testing.T) {
+	now := func() time.Time {
+		return time.Date(2020, 12, 3, 22, 10, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name          string
+		client        *doctorClientStub
+		expectStatus  []CheckStatus
+		expectMessage string
+	}{
+		{
+			name: "may deploy",
+			client: &doctorClientStub{pingStatus: http.StatusOK, deployStatus: http.StatusNotFound,
+				deployBody: `{"errors":[{"status":404,"message":"Checksum deploy failed. No existing file with SHA1"}]}`},
+			expectStatus:  []CheckStatus{CheckPass, CheckPass},
+			expectMessage: "The credentials may deploy to logs",
+		},
+		{
+			name:          "may not deploy",
+			client:        &doctorClientStub{pingStatus: http.StatusOK, deployStatus: http.StatusUnauthorized},
+			expectStatus:  []CheckStatus{CheckPass, CheckFail},
+			expectMessage: "The credentials may not deploy to logs: 401 Unauthorized",
+		},
+		{
+			name: "unknown repository",
+			client: &doctorClientStub{pingStatus: http.StatusOK, deployStatus: http.StatusNotFound,
+				deployBody: "Repository logs not found"},
+			expectStatus:  []CheckStatus{CheckPass, CheckFail},
+			expectMessage: "Failed to deploy to logs: 404 Not Found Repository logs not found",
+		},
+		{
+			name:          "not Artifactory",
+			client:        &doctorClientStub{pingStatus: http.StatusNotFound},
+			expectStatus:  []CheckStatus{CheckFail, CheckWarn},
+			expectMessage: "Skipped, the target service is not reachable",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			results := CheckTarget(context.Background(), test.client, "logs", now)
+			assert.Empty(t, cmp.Diff(test.expectStatus, statuses(results)))
+			assert.Equal(t, test.expectMessage, results[len(results)-1].Message)
+			if test.client.deployed != nil {
+				assert.Equal(t, []string{"logs/sb-flunky-doctor/probe-20201203-221000Z.txt"}, test.client.deployed)
+			}
+		})
+	}
+}
+
+func Test_checkTempDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "doctor")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	tests := []struct {
+		name          string
+		dir           string
+		free          uint64
+		freeErr       error
+		expectStatus  CheckStatus
+		expectMessage string
+	}{
+		{
+			name:          "enough space",
+			dir:           dir,
+			free:          5 << 30,
+			expectStatus:  CheckPass,
+			expectMessage: "5120 MiB free in " + dir,
+		},
+		{
+			name:          "little space",
+			dir:           dir,
+			free:          100 << 20,
+			expectStatus:  CheckWarn,
+			expectMessage: "Only 100 MiB free in " + dir,
+		},
+		{
+			name:          "unknown space",
+			dir:           dir,
+			freeErr:       errors.New("not supported"),
+			expectStatus:  CheckWarn,
+			expectMessage: "Failed to get the free space of " + dir + ": not supported",
+		},
+		{
+			name:         "missing directory",
+			dir:          filepath.Join(dir, "missing"),
+			expectStatus: CheckFail,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			result := checkTempDir(test.dir, func(path string) (uint64, error) {
+				return test.free, test.freeErr
+			})
+			assert.Equal(t, "temp-space", result.Name)
+			assert.Equal(t, test.expectStatus, result.Status)
+			if test.expectMessage != "" {
+				assert.Equal(t, test.expectMessage, result.Message)
+			}
+			files, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, files)
+		})
+	}
+}
+
+func Test_CheckTempDir(t *testing.T) {
+	result := CheckTempDir(os.TempDir())
+	assert.NotEqual(t, CheckFail, result.Status, result.Message)
+}
+
+func statuses(results []CheckResult) []CheckStatus {
+	var statuses []CheckStatus
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+func lastHint(results []CheckResult) string {
+	var hint string
+	for _, result := range results {
+		if result.Hint != "" {
+			hint = result.Hint
+		}
+	}
+	return hint
+}
@@ -16,105 +16,141 @@ import (
 
 type downloadSupportBundleHTTPClient interface {
 	GetURL() string
-	DownloadSupportBundle(bundleID string) (*http.Response, error)
-	GetSupportBundleStatus(bundleID string) (int, []byte, error)
+	DownloadSupportBundle(ctx context.Context, bundleID string, offset int64) (*http.Response, error)
+	GetSupportBundleStatus(ctx context.Context, bundleID string) (int, []byte, error)
 }
 
-// DownloadSupportBundle downloads a Support Bundle.
-func DownloadSupportBundle(ctx context.Context, client downloadSupportBundleHTTPClient, timeout time.Duration,
-	retryInterval time.Duration, bundleID BundleID) (string, error) {
+// transferPhase names the transfer of a Support Bundle in timeout errors.
+const transferPhase = "Support Bundle transfer"
+
+// DownloadTimeouts limit how long the download of a Support Bundle may take.
+type DownloadTimeouts struct {
+	// Ready limits the wait for the Support Bundle to be ready. The wait is not limited when it is not positive.
+	Ready time.Duration
+	// Transfer limits the transfer of the archive once ready, including the resumed attempts. The transfer is not
+	// limited when it is not positive.
+	Transfer time.Duration
+}
+
+// DownloadSupportBundle downloads a Support Bundle. It gives the path of the archive and its checksums.
+// The status of the Support Bundle is checked until it is ready, and an interrupted transfer is resumed, both waiting
+// according to the retry policy.
+// Errors are categorized, as a failure to transfer the Support Bundle unless Artifactory failed to generate it, it
+// was not ready in time, or ctx was cancelled or its deadline passed.
+func DownloadSupportBundle(ctx context.Context, client downloadSupportBundleHTTPClient, timeouts DownloadTimeouts,
+	retry flunkyhttp.RetryPolicy, bundleID BundleID) (string, flunkyhttp.Checksums, error) {
 	log.Debug(fmt.Sprintf("Download Support Bundle %s from %s", bundleID, client.GetURL()))
 
-	err := waitUntilSupportBundleIsReady(ctx, client, retryInterval, timeout, bundleID)
+	err := waitUntilSupportBundleIsReady(ctx, client, retry, timeouts.Ready, bundleID)
 	if err != nil {
-		return "", err
+		return "", flunkyhttp.Checksums{}, Categorize(CategoryDownloadTransfer, err)
 	}
 
 	dirPath, err := fileutils.CreateTempDir()
 	if err != nil {
-		return "", err
+		return "", flunkyhttp.Checksums{}, Categorize(CategoryDownloadTransfer, err)
 	}
 	tmpFilePath := filepath.Join(dirPath, fmt.Sprintf("%s.zip", bundleID))
-	tmpZipFile, err := os.Create(tmpFilePath)
-	if err != nil {
-		return "", err
-	}
-	defer handleClose(tmpZipFile)
-
-	err = downloadSupportBundleAndWriteToFile(client, tmpZipFile, bundleID)
+	checksums, err := downloadToFile(ctx, client, tmpFilePath, timeouts.Transfer, retry, bundleID)
 	if err != nil {
-		return "", err
+		// The partial archive is of no use, as a new download starts over
+		removeTempDir(dirPath)
+		return "", flunkyhttp.Checksums{}, Categorize(abortCategory(ctx, CategoryDownloadTransfer), err)
 	}
 
-	log.Debug(fmt.Sprintf("Downloaded Support Bundle to %s", tmpFilePath))
-	return tmpFilePath, nil
+	log.Debug(fmt.Sprintf("Downloaded Support Bundle to %s, SHA-256: %s", tmpFilePath, checksums.SHA256))
+	return tmpFilePath, checksums, nil
 }
 
-func downloadSupportBundleAndWriteToFile(client downloadSupportBundleHTTPClient, tmpZipFile *os.File, bundleID BundleID) error {
-	resp, err := client.DownloadSupportBundle(string(bundleID))
+// downloadToFile downloads a ready Support Bundle to a new file, closed once done.
+func downloadToFile(ctx context.Context, client downloadSupportBundleHTTPClient, path string, timeout time.Duration,
+	retry flunkyhttp.RetryPolicy, bundleID BundleID) (checksums flunkyhttp.Checksums, err error) {
+	tmpZipFile, err := os.Create(path)
 	if err != nil {
-		return err
-	}
-	defer handleClose(resp.Body)
-	log.Debug(fmt.Sprintf("Got %d", resp.StatusCode))
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http request failed with: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		return flunkyhttp.Checksums{}, err
 	}
-
-	_, err = io.Copy(tmpZipFile, resp.Body)
-	if err != nil {
+	defer handleClose(tmpZipFile)
+	err = RunWithTimeout(ctx, transferPhase, timeout, func(ctx context.Context) (err error) {
+		checksums, err = downloadSupportBundleAndWriteToFile(ctx, client, tmpZipFile, retry, bundleID)
 		return err
+	})
+	return checksums, err
+}
+
+func removeTempDir(dirPath string) {
+	log.Debug(fmt.Sprintf("Deleting the partially downloaded support bundle: %s", dirPath))
+	if err := fileutils.RemoveTempDir(dirPath); err != nil {
+		log.Warn(fmt.Sprintf("Error occurred while deleting the partially downloaded support bundle: %+v", err))
 	}
-	return nil
 }
 
+// waitUntilSupportBundleIsReady checks the status of a Support Bundle until it is ready, waiting between two checks
+// as between two attempts of a request: the wait starts at the retry interval and doubles up to the max interval,
+// minus the jitter, so that a long generation is not polled as often as a short one. The wait is not limited when
+// timeout is not positive.
 func waitUntilSupportBundleIsReady(ctx context.Context, client downloadSupportBundleHTTPClient,
-	retryInterval time.Duration, timeout time.Duration, bundleID BundleID) error {
-	ctxWithTimeout, cancelCtx := context.WithTimeout(ctx, timeout)
+	retry flunkyhttp.RetryPolicy, timeout time.Duration, bundleID BundleID) error {
+	ctxWithTimeout, cancelCtx := context.WithCancel(ctx)
+	if timeout > 0 {
+		cancelCtx()
+		ctxWithTimeout, cancelCtx = context.WithTimeout(ctx, timeout)
+	}
 	defer cancelCtx()
-	ticker := time.NewTicker(retryInterval)
-	defer ticker.Stop()
-	for {
+	for check := 1; ; check++ {
+		timer := time.NewTimer(retry.Backoff(check, 0))
 		select {
 		case <-ctxWithTimeout.Done():
-			return errors.New("timeout waiting for support bundle to be ready")
-		case <-ticker.C:
-			sbStatus, err := getBundleStatus(bundleID, client)
-			if err != nil {
-				return err
-			}
-
-			log.Debug(fmt.Sprintf("Support bundle status: %s", sbStatus))
-			if sbStatus != "in progress" {
-				return nil
-			}
+			timer.Stop()
+			return readyWaitAborted(ctx, ctxWithTimeout, nil)
+		case <-timer.C:
+		}
+		ready, err := isSupportBundleReady(ctxWithTimeout, client, bundleID)
+		if err != nil {
+			return readyWaitAborted(ctx, ctxWithTimeout, err)
+		}
+		if ready {
+			return nil
 		}
 	}
 }
 
-func getBundleStatus(bundleID BundleID, client downloadSupportBundleHTTPClient) (string, error) {
-	log.Debug(fmt.Sprintf("Attempting to get status for support bundle %s", bundleID))
-	statusCode, body, err := client.GetSupportBundleStatus(string(bundleID))
+// isSupportBundleReady tells whether a Support Bundle is ready, or fails when Artifactory failed to generate it.
+func isSupportBundleReady(ctx context.Context, client downloadSupportBundleHTTPClient, bundleID BundleID) (bool,
+	error) {
+	sbStatus, err := GetSupportBundleStatus(ctx, client, bundleID)
 	if err != nil {
-		return "", err
+		return false, err
 	}
-
-	log.Debug(fmt.Sprintf("Got HTTP response status: %d", statusCode))
-	if statusCode != http.StatusOK {
-		return "", fmt.Errorf("http request failed with: %d %s", statusCode, http.StatusText(statusCode))
+	log.Debug(fmt.Sprintf("Support bundle status: %s", sbStatus.Reported))
+	switch sbStatus.State {
+	case BundleStateSuccess:
+		return true, nil
+	case BundleStateFailed:
+		return false, Categorize(CategoryGenerationFailure,
+			&SupportBundleGenerationFailedError{BundleID: bundleID, Message: sbStatus.Message})
+	case BundleStateUnknown:
+		return false, Categorize(CategoryGenerationFailure,
+			&UnknownSupportBundleStatusError{BundleID: bundleID, Status: sbStatus.Reported})
+	default:
+		return false, nil
 	}
+}
 
-	parsedBody, err := flunkyhttp.ParseJSON(body)
-	if err != nil {
-		return "", err
+// readyWaitAborted gives why the wait for a Support Bundle to be ready failed with err: the error of ctx when it is
+// done, categorized as an interruption or a timeout, the timeout of the wait when it expired, err otherwise.
+// An error which is not caused by a context, such as a status error received after the timeout, is kept as is.
+func readyWaitAborted(ctx context.Context, waitCtx context.Context, err error) error {
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		return err
 	}
-
-	sbStatus, err := parsedBody.GetString("status")
-	if err != nil {
-		return "", err
+	switch {
+	case ctx.Err() != nil:
+		return Categorize(abortCategory(ctx, CategoryDownloadTimeout), ctx.Err())
+	case waitCtx.Err() != nil:
+		return Categorize(CategoryDownloadTimeout, ErrReadyTimeout)
+	default:
+		return err
 	}
-	return sbStatus, nil
 }
 
 func handleClose(closer io.Closer) {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -20,20 +23,25 @@ const (
 
 type checkStatusClientStub struct {
 	count            int
+	checked          []time.Time
 	statusCode       int
 	payloads         []string
 	err              error
+	delay            time.Duration
 	receivedBundleID string
 }
 
-func (cs *checkStatusClientStub) GetSupportBundleStatus(bundleID string) (status int, responseBytes []byte, err error) {
+func (cs *checkStatusClientStub) GetSupportBundleStatus(_ context.Context, bundleID string) (status int,
+	responseBytes []byte, err error) {
+	time.Sleep(cs.delay)
 	responseBytes = []byte(cs.payloads[cs.count])
+	cs.checked = append(cs.checked, time.Now())
 	cs.receivedBundleID = bundleID
 	cs.count++
 	return cs.statusCode, responseBytes, cs.err
 }
 
-func (cs *checkStatusClientStub) DownloadSupportBundle(string) (*http.Response, error) {
+func (cs *checkStatusClientStub) DownloadSupportBundle(context.Context, string, int64) (*http.Response, error) {
 	return nil, nil
 }
 
@@ -67,6 +75,34 @@ func Test_WaitUntilReady(t *testing.T) {
 				payloads:   []string{fmt.Sprintf(body, "in progress"), fmt.Sprintf(body, "success")},
 			},
 		},
+		{
+			name:          "no timeout",
+			retryInterval: 5 * time.Millisecond,
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{fmt.Sprintf(body, "in progress"), fmt.Sprintf(body, "success")},
+			},
+		},
+		{
+			name:          "generation failed",
+			timeout:       100 * time.Millisecond,
+			retryInterval: 5 * time.Millisecond,
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{fmt.Sprintf(body, "in progress"), `{"status":"failed","message":"disk full"}`},
+			},
+			expectedErrorMessage: "generation of support bundle bundleID failed: disk full",
+		},
+		{
+			name:          "unknown status",
+			timeout:       100 * time.Millisecond,
+			retryInterval: 5 * time.Millisecond,
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{fmt.Sprintf(body, "archived")},
+			},
+			expectedErrorMessage: `support bundle bundleID has unknown status "archived"`,
+		},
 		{
 			name:          "support bundle not found",
 			timeout:       100 * time.Millisecond,
@@ -144,8 +180,8 @@ func Test_WaitUntilReady(t *testing.T) {
 		test := tests[i]
 		t.Run(test.name, func(t *testing.T) {
 			ctx := context.Background()
-			err := waitUntilSupportBundleIsReady(ctx, test.clientStub, test.retryInterval, test.timeout,
-				"bundleID")
+			err := waitUntilSupportBundleIsReady(ctx, test.clientStub,
+				flunkyhttp.RetryPolicy{Interval: test.retryInterval}, test.timeout, "bundleID")
 			if test.expectedErrorMessage != "" {
 				require.Error(t, err)
 				assert.EqualError(t, err, test.expectedErrorMessage)
@@ -157,6 +193,24 @@ func Test_WaitUntilReady(t *testing.T) {
 	}
 }
 
+func Test_WaitUntilReady_Backoff(t *testing.T) {
+	inProgress := fmt.Sprintf(body, "in progress")
+	clientStub := &checkStatusClientStub{statusCode: http.StatusOK,
+		payloads: []string{inProgress, inProgress, inProgress, inProgress, fmt.Sprintf(body, "success")}}
+	retry := flunkyhttp.RetryPolicy{Interval: 2 * time.Millisecond, MaxInterval: 8 * time.Millisecond}
+
+	start := time.Now()
+	require.NoError(t, waitUntilSupportBundleIsReady(context.Background(), clientStub, retry, time.Second, "bundleID"))
+
+	require.Len(t, clientStub.checked, 5)
+	expectedWaits := []time.Duration{2, 4, 8, 8, 8}
+	previous := start
+	for i, checked := range clientStub.checked {
+		assert.GreaterOrEqual(t, int64(checked.Sub(previous)), int64(expectedWaits[i]*time.Millisecond), "check %d", i+1)
+		previous = checked
+	}
+}
+
 type downloadClientStub struct {
 	response           *http.Response
 	getStatusErr       error
@@ -164,11 +218,13 @@ type downloadClientStub struct {
 	downloadedBundleID string
 }
 
-func (dc *downloadClientStub) GetSupportBundleStatus(string) (status int, responseBytes []byte, err error) {
+func (dc *downloadClientStub) GetSupportBundleStatus(context.Context, string) (status int, responseBytes []byte,
+	err error) {
 	return http.StatusOK, []byte(fmt.Sprintf(body, "success")), dc.getStatusErr
 }
 
-func (dc *downloadClientStub) DownloadSupportBundle(bundleID string) (*http.Response, error) {
+func (dc *downloadClientStub) DownloadSupportBundle(_ context.Context, bundleID string, _ int64) (*http.Response,
+	error) {
 	dc.downloadedBundleID = bundleID
 	return dc.response, dc.downloadErr
 }
@@ -177,6 +233,99 @@ func (dc *downloadClientStub) GetURL() string {
 	return "url"
 }
 
+func Test_WaitUntilReady_TypedErrors(t *testing.T) {
+	err := waitUntilSupportBundleIsReady(context.Background(), &checkStatusClientStub{
+		statusCode: http.StatusOK,
+		payloads:   []string{`{"status":"failed","error":"disk full"}`},
+	}, flunkyhttp.RetryPolicy{Interval: time.Millisecond}, 100*time.Millisecond, "bundleID")
+	var failedErr *SupportBundleGenerationFailedError
+	require.True(t, errors.As(err, &failedErr))
+	assert.Equal(t, BundleID("bundleID"), failedErr.BundleID)
+	assert.Equal(t, "disk full", failedErr.Message)
+
+	err = waitUntilSupportBundleIsReady(context.Background(), &checkStatusClientStub{
+		statusCode: http.StatusOK,
+		payloads:   []string{fmt.Sprintf(body, "")},
+	}, flunkyhttp.RetryPolicy{Interval: time.Millisecond}, 100*time.Millisecond, "bundleID")
+	var unknownErr *UnknownSupportBundleStatusError
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, "", unknownErr.Status)
+}
+
+// hangingClientStub hangs on the requests until they are aborted, but the status requests when hangOnStatus is not set
+// which tell that the Support Bundle is ready.
+type hangingClientStub struct {
+	hangOnStatus bool
+}
+
+func (h *hangingClientStub) GetSupportBundleStatus(ctx context.Context, _ string) (int, []byte, error) {
+	if !h.hangOnStatus {
+		return http.StatusOK, []byte(fmt.Sprintf(body, "success")), nil
+	}
+	<-ctx.Done()
+	return -1, nil, ctx.Err()
+}
+
+func (h *hangingClientStub) DownloadSupportBundle(ctx context.Context, _ string, _ int64) (*http.Response, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (h *hangingClientStub) GetURL() string {
+	return url
+}
+
+func Test_WaitUntilReady_Aborted(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now())
+	defer cancelExpired()
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		expectErr      error
+		expectCategory ErrorCategory
+	}{
+		{name: "hung status request", ctx: context.Background(), expectErr: ErrReadyTimeout,
+			expectCategory: CategoryDownloadTimeout},
+		{name: "cancelled", ctx: cancelled, expectErr: context.Canceled, expectCategory: CategoryInterrupted},
+		{name: "deadline passed", ctx: expired, expectErr: context.DeadlineExceeded,
+			expectCategory: CategoryDownloadTimeout},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := waitUntilSupportBundleIsReady(test.ctx, &hangingClientStub{hangOnStatus: true},
+				flunkyhttp.RetryPolicy{Interval: time.Millisecond}, 20*time.Millisecond, "bundleID")
+			assert.True(t, errors.Is(err, test.expectErr), err)
+			assert.Equal(t, test.expectCategory, CategoryOf(err))
+		})
+	}
+}
+
+func Test_WaitUntilReady_ErrorAfterTimeout(t *testing.T) {
+	client := &checkStatusClientStub{statusCode: http.StatusNotFound, payloads: []string{""},
+		delay: 30 * time.Millisecond}
+
+	err := waitUntilSupportBundleIsReady(context.Background(), client,
+		flunkyhttp.RetryPolicy{Interval: time.Millisecond}, 10*time.Millisecond, "bundleID")
+
+	assert.EqualError(t, err, "http request failed with: 404 Not Found")
+	assert.False(t, errors.Is(err, ErrReadyTimeout))
+}
+
+func Test_DownloadSupportBundle_TransferTimeout(t *testing.T) {
+	_, _, err := DownloadSupportBundle(context.Background(), &hangingClientStub{},
+		DownloadTimeouts{Ready: time.Second, Transfer: 20 * time.Millisecond},
+		flunkyhttp.RetryPolicy{Interval: time.Millisecond}, "bundleID")
+
+	assert.EqualError(t, err, "Support Bundle transfer did not complete within 20ms: context deadline exceeded")
+	assert.Equal(t, CategoryDownloadTransfer, CategoryOf(err))
+	var timeoutErr *TimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+}
+
 func Test_DownloadSupportBundle(t *testing.T) {
 	tests := []struct {
 		name                    string
@@ -239,13 +388,22 @@ func Test_DownloadSupportBundle(t *testing.T) {
 	for i := range tests {
 		test := tests[i]
 		t.Run(test.name, func(t *testing.T) {
+			tempDirBase, err := ioutil.TempDir("", "download")
+			require.NoError(t, err)
+			defer func() { _ = os.RemoveAll(tempDirBase) }()
+			fileutils.SetTempDirBase(tempDirBase)
+			defer fileutils.SetTempDirBase(os.TempDir())
+
 			ctx := context.Background()
-			timeout := 10 * time.Millisecond
-			retryInterval := 5 * time.Millisecond
-			filePath, err := DownloadSupportBundle(ctx, test.clientStub, timeout, retryInterval, "bundleID")
+			timeouts := DownloadTimeouts{Ready: 10 * time.Millisecond}
+			retry := flunkyhttp.RetryPolicy{MaxAttempts: 5, Interval: 5 * time.Millisecond}
+			filePath, _, err := DownloadSupportBundle(ctx, test.clientStub, timeouts, retry, "bundleID")
 			if test.expectedErrorMessage != "" {
 				require.Error(t, err)
 				assert.EqualError(t, err, test.expectedErrorMessage)
+				tempDirs, err := ioutil.ReadDir(tempDirBase)
+				require.NoError(t, err)
+				assert.Empty(t, tempDirs, "the partial download is deleted")
 			} else {
 				require.NoError(t, err)
 				assert.Contains(t, filePath, "bundleID.zip")
@@ -0,0 +1,176 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// ErrorCategory tells what kind of failure an error is, so that callers can decide whether to retry, alert or page
+// someone.
+type ErrorCategory string
+
+const (
+	// CategoryConfiguration is a failure due to the flags, the files or the services given to the plugin.
+	CategoryConfiguration ErrorCategory = "configuration"
+	// CategoryAuthentication is a failure due to credentials rejected by a service or lacking a permission.
+	CategoryAuthentication ErrorCategory = "authentication"
+	// CategoryCreation is a failure to request the creation of a Support Bundle.
+	CategoryCreation ErrorCategory = "creation"
+	// CategoryGenerationFailure is a failure of Artifactory to generate a Support Bundle it accepted to create.
+	CategoryGenerationFailure ErrorCategory = "generation-failure"
+	// CategoryDownloadTimeout is a Support Bundle not ready within the download timeout.
+	CategoryDownloadTimeout ErrorCategory = "download-timeout"
+	// CategoryDownloadTransfer is a failure to transfer a ready Support Bundle from the source service.
+	CategoryDownloadTransfer ErrorCategory = "download-transfer"
+	// CategoryUploadRejected is a failure to upload a Support Bundle to the target service.
+	CategoryUploadRejected ErrorCategory = "upload-rejected"
+	// CategoryInterrupted is a command interrupted by a signal, such as Ctrl-C.
+	CategoryInterrupted ErrorCategory = "interrupted"
+)
+
+// maxErrorBodySize is the number of bytes of a response body kept in an HTTPError.
+const maxErrorBodySize = 4096
+
+// ErrReadyTimeout is returned when a Support Bundle is not ready within the download timeout.
+var ErrReadyTimeout = errors.New("timeout waiting for support bundle to be ready")
+
+// HTTPError is returned when Artifactory answers a request with an unexpected status.
+type HTTPError struct {
+	StatusCode int
+	// Body is the body of the response, truncated to its first bytes.
+	Body []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("http request failed with: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func newHTTPError(statusCode int, body []byte) *HTTPError {
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+	return &HTTPError{StatusCode: statusCode, Body: body}
+}
+
+// newHTTPErrorFromResponse gives the HTTPError of a response whose body has not been read. The body is read but not
+// closed.
+func newHTTPErrorFromResponse(resp *http.Response) *HTTPError {
+	if resp.Body == nil {
+		return newHTTPError(resp.StatusCode, nil)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		body = nil
+	}
+	return newHTTPError(resp.StatusCode, body)
+}
+
+// CategorizedError is an error of a known category.
+type CategorizedError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+func (e *CategorizedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CategorizedError) Unwrap() error {
+	return e.Err
+}
+
+// Categorize gives err as an error of the given category, unless it already has one. Errors due to credentials
+// rejected by Artifactory are of the authentication category whatever the given one.
+func Categorize(category ErrorCategory, err error) error {
+	if err == nil || CategoryOf(err) != "" {
+		return err
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusUnauthorized ||
+		httpErr.StatusCode == http.StatusForbidden) {
+		category = CategoryAuthentication
+	}
+	return &CategorizedError{Category: category, Err: err}
+}
+
+// abortCategory gives the category of an error due to ctx being done: interrupted when it was cancelled, a download
+// timeout when its deadline passed. It gives otherwise when ctx is not done.
+func abortCategory(ctx context.Context, otherwise ErrorCategory) ErrorCategory {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return CategoryInterrupted
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return CategoryDownloadTimeout
+	default:
+		return otherwise
+	}
+}
+
+// CategoryOf gives the category of err, or an empty one when it is not known.
+func CategoryOf(err error) ErrorCategory {
+	var categorized *CategorizedError
+	if errors.As(err, &categorized) {
+		return categorized.Category
+	}
+	return ""
+}
+
+// SupportBundleGenerationFailedError is returned when Artifactory reports that the generation of a Support Bundle
+// failed.
+type SupportBundleGenerationFailedError struct {
+	BundleID BundleID
+	// Message is the failure message reported by Artifactory, if any.
+	Message string
+}
+
+func (e *SupportBundleGenerationFailedError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("generation of support bundle %s failed", e.BundleID)
+	}
+	return fmt.Sprintf("generation of support bundle %s failed: %s", e.BundleID, e.Message)
+}
+
+// UnknownSupportBundleStatusError is returned when Artifactory reports a status of a Support Bundle that is not
+// supported.
+type UnknownSupportBundleStatusError struct {
+	BundleID BundleID
+	Status   string
+}
+
+func (e *UnknownSupportBundleStatusError) Error() string {
+	return fmt.Sprintf("support bundle %s has unknown status %q", e.BundleID, e.Status)
+}
+
+// ChecksumMismatchError is returned when the checksum reported by Artifactory for an uploaded Support Bundle differs
+// from the one computed locally.
+type ChecksumMismatchError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, Artifactory reported %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+// TimeoutError is returned when a phase, such as the transfer of a Support Bundle, does not complete within its
+// timeout. In-flight requests of the phase are aborted.
+type TimeoutError struct {
+	// Phase tells what did not complete, such as "Support Bundle transfer".
+	Phase   string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s did not complete within %s: %v", e.Phase, e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func Test_SupportBundleGenerationFailedError(t *testing.T) {
+	assert.EqualError(t, &SupportBundleGenerationFailedError{BundleID: "1"}, "generation of support bundle 1 failed")
+	assert.EqualError(t, &SupportBundleGenerationFailedError{BundleID: "1", Message: "oops"},
+		"generation of support bundle 1 failed: oops")
+}
+
+func Test_newHTTPErrorFromResponse(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), maxErrorBodySize+10)
+	err := newHTTPErrorFromResponse(&http.Response{StatusCode: http.StatusBadGateway,
+		Body: ioutil.NopCloser(bytes.NewReader(body))})
+	assert.EqualError(t, err, "http request failed with: 502 Bad Gateway")
+	assert.Equal(t, http.StatusBadGateway, err.StatusCode)
+	assert.Len(t, err.Body, maxErrorBodySize)
+
+	assert.Nil(t, newHTTPErrorFromResponse(&http.Response{StatusCode: http.StatusNotFound}).Body)
+}
+
+func Test_Categorize(t *testing.T) {
+	tests := []struct {
+		name           string
+		category       ErrorCategory
+		err            error
+		expectCategory ErrorCategory
+	}{
+		{
+			name:           "plain error",
+			category:       CategoryCreation,
+			err:            errors.New("connection refused"),
+			expectCategory: CategoryCreation,
+		},
+		{
+			name:           "unauthorized",
+			category:       CategoryUploadRejected,
+			err:            newHTTPError(http.StatusUnauthorized, nil),
+			expectCategory: CategoryAuthentication,
+		},
+		{
+			name:           "forbidden",
+			category:       CategoryCreation,
+			err:            fmt.Errorf("create: %w", newHTTPError(http.StatusForbidden, nil)),
+			expectCategory: CategoryAuthentication,
+		},
+		{
+			name:           "other status",
+			category:       CategoryUploadRejected,
+			err:            newHTTPError(http.StatusConflict, nil),
+			expectCategory: CategoryUploadRejected,
+		},
+		{
+			name:           "already categorized",
+			category:       CategoryDownloadTransfer,
+			err:            Categorize(CategoryDownloadTimeout, ErrReadyTimeout),
+			expectCategory: CategoryDownloadTimeout,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := Categorize(test.category, test.err)
+			assert.Equal(t, test.expectCategory, CategoryOf(err))
+			assert.EqualError(t, err, test.err.Error())
+			assert.True(t, errors.Is(err, test.err))
+		})
+	}
+	assert.Nil(t, Categorize(CategoryCreation, nil))
+	assert.Equal(t, ErrorCategory(""), CategoryOf(errors.New("unknown")))
+}
+
+func Test_Categorize_keepsHTTPError(t *testing.T) {
+	err := Categorize(CategoryUploadRejected, newHTTPError(http.StatusConflict, []byte("already exists")))
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusConflict, httpErr.StatusCode)
+	assert.Equal(t, []byte("already exists"), httpErr.Body)
+}
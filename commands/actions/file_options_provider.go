@@ -0,0 +1,184 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OptionsFileVersion is the version of the options file schema supported by this plugin.
+const OptionsFileVersion = 1
+
+const optionsDateLayout = "2006-01-02"
+
+// OptionsFile is the schema of a file describing the content of a Support Bundle.
+type OptionsFile struct {
+	Version int `json:"version" yaml:"version"`
+	// Description replaces the default description of the Support Bundle, if not empty.
+	Description   string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Configuration bool                  `json:"configuration" yaml:"configuration"`
+	System        bool                  `json:"system" yaml:"system"`
+	Logs          OptionsFileLogs       `json:"logs" yaml:"logs"`
+	ThreadDump    OptionsFileThreadDump `json:"threadDump" yaml:"threadDump"`
+}
+
+// OptionsFileLogs defines which logs are included in a Support Bundle. When logs are included without a start date,
+// the logs of the LastDays days before the end date are collected, the end date being today by default.
+type OptionsFileLogs struct {
+	Include   bool   `json:"include" yaml:"include"`
+	StartDate string `json:"startDate,omitempty" yaml:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty" yaml:"endDate,omitempty"`
+	LastDays  uint   `json:"lastDays,omitempty" yaml:"lastDays,omitempty"`
+}
+
+// OptionsFileThreadDump defines how many thread dumps are included in a Support Bundle, and the interval between them
+// in milliseconds.
+type OptionsFileThreadDump struct {
+	Count    uint `json:"count" yaml:"count"`
+	Interval uint `json:"interval" yaml:"interval"`
+}
+
+// FileOptionsProvider provides Support Bundle creation options read from a YAML or JSON file.
+type FileOptionsProvider struct {
+	GetDate Clock
+	Path    string
+}
+
+// NewFileOptionsProvider creates a new FileOptionsProvider.
+func NewFileOptionsProvider(path string, getDate Clock) *FileOptionsProvider {
+	return &FileOptionsProvider{
+		GetDate: getDate,
+		Path:    path,
+	}
+}
+
+// GetOptions gets the options described in the file.
+func (p *FileOptionsProvider) GetOptions(caseNumber CaseNumber) (http.SupportBundleCreationOptions, error) {
+	optionsFile, err := LoadOptionsFile(p.Path)
+	if err != nil {
+		return http.SupportBundleCreationOptions{}, err
+	}
+	return optionsFile.toCreationOptions(caseNumber, p.GetDate)
+}
+
+// LoadOptionsFile reads and validates an options file. Files with a .json extension are read as JSON, any other file
+// as YAML. Unknown fields are rejected so that a typo does not silently leave an option out.
+func LoadOptionsFile(path string) (*OptionsFile, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	optionsFile := &OptionsFile{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(optionsFile)
+	} else {
+		err = yaml.UnmarshalStrict(content, optionsFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid options file %s: %w", path, err)
+	}
+	if err = optionsFile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid options file %s: %w", path, err)
+	}
+	return optionsFile, nil
+}
+
+// Validate checks that the options are supported and consistent.
+func (o *OptionsFile) Validate() error {
+	if o.Version == 0 {
+		return fmt.Errorf("version is missing, expected %d", OptionsFileVersion)
+	}
+	if o.Version != OptionsFileVersion {
+		return fmt.Errorf("unsupported version %d, expected %d", o.Version, OptionsFileVersion)
+	}
+	startDate, err := parseOptionsDate("logs.startDate", o.Logs.StartDate)
+	if err != nil {
+		return err
+	}
+	endDate, err := parseOptionsDate("logs.endDate", o.Logs.EndDate)
+	if err != nil {
+		return err
+	}
+	if (o.Logs.StartDate != "" || o.Logs.EndDate != "" || o.Logs.LastDays > 0) && !o.Logs.Include {
+		return fmt.Errorf("logs.startDate, logs.endDate and logs.lastDays require logs.include to be true")
+	}
+	if o.Logs.StartDate != "" && o.Logs.LastDays > 0 {
+		return fmt.Errorf("logs.lastDays cannot be used with logs.startDate")
+	}
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		return fmt.Errorf("logs.endDate %s is before logs.startDate %s", o.Logs.EndDate, o.Logs.StartDate)
+	}
+	if o.ThreadDump.Interval > 0 && o.ThreadDump.Count < 2 {
+		return fmt.Errorf("threadDump.interval requires threadDump.count to be at least 2")
+	}
+	return nil
+}
+
+func parseOptionsDate(field string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(optionsDateLayout, value)
+	if err != nil {
+		return date, fmt.Errorf("%s %q is not a date in the YYYY-MM-DD format", field, value)
+	}
+	return date, nil
+}
+
+func (o *OptionsFile) toCreationOptions(caseNumber CaseNumber,
+	getDate Clock) (http.SupportBundleCreationOptions, error) {
+	options, err := (&DefaultOptionsProvider{getDate: getDate}).GetOptions(caseNumber)
+	if err != nil {
+		return options, err
+	}
+	if o.Description != "" {
+		options.Description = o.Description
+	}
+	options.Parameters, err = o.toParameters(getDate)
+	return options, err
+}
+
+func (o *OptionsFile) toParameters(getDate Clock) (*http.SupportBundleParameters, error) {
+	parameters := &http.SupportBundleParameters{
+		Configuration: o.Configuration,
+		Logs: &http.SupportBundleParametersLogs{
+			Include:   o.Logs.Include,
+			StartDate: o.Logs.StartDate,
+			EndDate:   o.Logs.EndDate,
+		},
+		System: o.System,
+		ThreadDump: &http.SupportBundleParametersThreadDump{
+			Count:    o.ThreadDump.Count,
+			Interval: o.ThreadDump.Interval,
+		},
+	}
+	if o.Logs.Include {
+		logs := parameters.Logs
+		if logs.EndDate == "" {
+			logs.EndDate = getDate().Format(optionsDateLayout)
+		}
+		if logs.StartDate == "" {
+			endDate, err := time.Parse(optionsDateLayout, logs.EndDate)
+			if err != nil {
+				return nil, err
+			}
+			logs.StartDate = endDate.AddDate(0, 0, -o.Logs.lastDays()).Format(optionsDateLayout)
+		}
+	}
+	return parameters, nil
+}
+
+func (l *OptionsFileLogs) lastDays() int {
+	if l.LastDays == 0 {
+		return 1
+	}
+	return int(l.LastDays)
+}
@@ -0,0 +1,200 @@
+package actions
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_FileOptionsProvider(t *testing.T) {
+	tests := []struct {
+		name              string
+		filename          string
+		content           string
+		expectDescription string
+		expectParameters  http.SupportBundleParameters
+		expectErr         string
+	}{
+		{
+			name:     "YAML file",
+			filename: "sb.yaml",
+			content: `version: 1
+description: Slow startup
+configuration: true
+system: true
+logs:
+  include: true
+  startDate: "2012-10-01"
+  endDate: "2012-10-03"
+threadDump:
+  count: 3
+  interval: 1000
+`,
+			expectDescription: "Slow startup",
+			expectParameters: http.SupportBundleParameters{
+				Configuration: true,
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-01",
+					EndDate:   "2012-10-03",
+				},
+				System: true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{
+					Count:    3,
+					Interval: 1000,
+				},
+			},
+		},
+		{
+			name:     "JSON file",
+			filename: "sb.json",
+			content:  `{"version":1,"system":true,"threadDump":{"count":1}}`,
+			expectParameters: http.SupportBundleParameters{
+				Logs:       &http.SupportBundleParametersLogs{},
+				System:     true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 1},
+			},
+		},
+		{
+			name:     "logs of the last day by default",
+			filename: "sb.yml",
+			content:  "version: 1\nlogs:\n  include: true\n",
+			expectParameters: http.SupportBundleParameters{
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-31",
+					EndDate:   "2012-11-01",
+				},
+				ThreadDump: &http.SupportBundleParametersThreadDump{},
+			},
+		},
+		{
+			name:     "logs of the day before the end date",
+			filename: "sb.yml",
+			content:  "version: 1\nlogs:\n  include: true\n  endDate: \"2012-03-01\"\n",
+			expectParameters: http.SupportBundleParameters{
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-02-29",
+					EndDate:   "2012-03-01",
+				},
+				ThreadDump: &http.SupportBundleParametersThreadDump{},
+			},
+		},
+		{
+			name:     "logs of the last days",
+			filename: "sb.yml",
+			content:  "version: 1\nlogs:\n  include: true\n  lastDays: 3\n",
+			expectParameters: http.SupportBundleParameters{
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-29",
+					EndDate:   "2012-11-01",
+				},
+				ThreadDump: &http.SupportBundleParametersThreadDump{},
+			},
+		},
+		{
+			name:      "last days with start date",
+			filename:  "sb.yaml",
+			content:   "version: 1\nlogs:\n  include: true\n  lastDays: 3\n  startDate: \"2012-10-02\"\n",
+			expectErr: "logs.lastDays cannot be used with logs.startDate",
+		},
+		{
+			name:      "missing version",
+			filename:  "sb.yaml",
+			content:   "system: true\n",
+			expectErr: "version is missing, expected 1",
+		},
+		{
+			name:      "unsupported version",
+			filename:  "sb.json",
+			content:   `{"version":2}`,
+			expectErr: "unsupported version 2, expected 1",
+		},
+		{
+			name:      "unknown YAML field",
+			filename:  "sb.yaml",
+			content:   "version: 1\nsytem: true\n",
+			expectErr: "yaml: unmarshal errors:\n  line 2: field sytem not found in type actions.OptionsFile",
+		},
+		{
+			name:      "unknown JSON field",
+			filename:  "sb.JSON",
+			content:   `{"version":1,"sytem":true}`,
+			expectErr: `json: unknown field "sytem"`,
+		},
+		{
+			name:      "invalid date",
+			filename:  "sb.yaml",
+			content:   "version: 1\nlogs:\n  include: true\n  startDate: 01/10/2012\n",
+			expectErr: `logs.startDate "01/10/2012" is not a date in the YYYY-MM-DD format`,
+		},
+		{
+			name:      "end date before start date",
+			filename:  "sb.yaml",
+			content:   "version: 1\nlogs:\n  include: true\n  startDate: \"2012-10-02\"\n  endDate: \"2012-10-01\"\n",
+			expectErr: "logs.endDate 2012-10-01 is before logs.startDate 2012-10-02",
+		},
+		{
+			name:      "dates without logs",
+			filename:  "sb.yaml",
+			content:   "version: 1\nlogs:\n  endDate: \"2012-10-01\"\n",
+			expectErr: "logs.startDate, logs.endDate and logs.lastDays require logs.include to be true",
+		},
+		{
+			name:      "interval without several thread dumps",
+			filename:  "sb.yaml",
+			content:   "version: 1\nthreadDump:\n  count: 1\n  interval: 500\n",
+			expectErr: "threadDump.interval requires threadDump.count to be at least 2",
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "options")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.filename)
+			require.NoError(t, ioutil.WriteFile(path, []byte(test.content), 0600))
+			provider := &FileOptionsProvider{
+				GetDate: func() time.Time {
+					return time.Unix(1351807721, 0)
+				},
+				Path: path,
+			}
+			options, err := provider.GetOptions("foo")
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, "invalid options file "+path+": "+test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			expectDescription := test.expectDescription
+			if expectDescription == "" {
+				expectDescription = "Generated on 2012-11-01T22:08:41Z"
+			}
+			assert.Empty(t, cmp.Diff(
+				http.SupportBundleCreationOptions{
+					Name:        "JFrog Support Case number foo",
+					Description: expectDescription,
+					Parameters:  &test.expectParameters,
+				},
+				options))
+		})
+	}
+}
+
+func Test_FileOptionsProvider_MissingFile(t *testing.T) {
+	_, err := NewFileOptionsProvider("does-not-exist.yaml", time.Now).GetOptions("foo")
+	require.Error(t, err)
+	assert.True(t, os.IsNotExist(err))
+}
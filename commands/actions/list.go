@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"net/http"
+)
+
+type listSupportBundlesHTTPClient interface {
+	supportBundleStatusHTTPClient
+	ListSupportBundles(ctx context.Context) (int, []byte, error)
+}
+
+// ListSupportBundles lists the Support Bundles available on an Artifactory service. The list only gives a summary of
+// each Support Bundle, so the details of each Support Bundle are fetched as well.
+func ListSupportBundles(ctx context.Context, client listSupportBundlesHTTPClient) ([]flunkyhttp.SupportBundleDetails,
+	error) {
+	log.Debug(fmt.Sprintf("List Support Bundles on %s", client.GetURL()))
+	statusCode, body, err := client.ListSupportBundles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug(fmt.Sprintf("Got HTTP response status: %d", statusCode))
+	if statusCode != http.StatusOK {
+		return nil, newHTTPError(statusCode, body)
+	}
+
+	var list flunkyhttp.SupportBundleList
+	err = json.Unmarshal(body, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	bundles := make([]flunkyhttp.SupportBundleDetails, 0, len(list.Bundles))
+	for i := range list.Bundles {
+		bundle := list.Bundles[i]
+		details, err := GetSupportBundleDetails(ctx, client, BundleID(bundle.ID))
+		if err != nil {
+			log.Warn(fmt.Sprintf("Could not get details of Support Bundle %s: %+v", bundle.ID, err))
+		} else {
+			bundle.Status = details.Status
+			bundle.Size = details.Size
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+// GetSupportBundleDetails gets the details of a Support Bundle.
+func GetSupportBundleDetails(ctx context.Context, client supportBundleStatusHTTPClient, bundleID BundleID) (
+	flunkyhttp.SupportBundleDetails, error) {
+	var details flunkyhttp.SupportBundleDetails
+	statusCode, body, err := client.GetSupportBundleStatus(ctx, string(bundleID))
+	if err != nil {
+		return details, err
+	}
+
+	log.Debug(fmt.Sprintf("Got HTTP response status: %d", statusCode))
+	if statusCode != http.StatusOK {
+		return details, newHTTPError(statusCode, body)
+	}
+
+	err = json.Unmarshal(body, &details)
+	return details, err
+}
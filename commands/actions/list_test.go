@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type listClientStub struct {
+	listStatusCode int
+	listResponse   string
+	listErr        error
+	details        map[string]string
+}
+
+func (s *listClientStub) GetURL() string {
+	return "stub"
+}
+
+func (s *listClientStub) ListSupportBundles(_ context.Context) (int, []byte, error) {
+	return s.listStatusCode, []byte(s.listResponse), s.listErr
+}
+
+func (s *listClientStub) GetSupportBundleStatus(_ context.Context, bundleID string) (int, []byte, error) {
+	details, ok := s.details[bundleID]
+	if !ok {
+		return http.StatusNotFound, []byte(`{}`), nil
+	}
+	return http.StatusOK, []byte(details), nil
+}
+
+func Test_ListSupportBundles(t *testing.T) {
+	tests := []struct {
+		name          string
+		clientStub    *listClientStub
+		expectBundles []flunkyhttp.SupportBundleDetails
+		expectErr     string
+	}{
+		{
+			name: "success",
+			clientStub: &listClientStub{
+				listStatusCode: http.StatusOK,
+				listResponse: `{"count":2,"bundles":[` +
+					`{"id":"1","name":"n1","description":"d1","created":"2020-12-01T10:00:00Z"},` +
+					`{"id":"2","name":"n2","description":"d2","created":"2020-12-02T10:00:00Z"}]}`,
+				details: map[string]string{
+					"1": `{"id":"1","status":"success","size":1024}`,
+				},
+			},
+			expectBundles: []flunkyhttp.SupportBundleDetails{
+				{ID: "1", Name: "n1", Description: "d1", Created: "2020-12-01T10:00:00Z", Status: "success", Size: 1024},
+				{ID: "2", Name: "n2", Description: "d2", Created: "2020-12-02T10:00:00Z"},
+			},
+		},
+		{
+			name: "empty",
+			clientStub: &listClientStub{
+				listStatusCode: http.StatusOK,
+				listResponse:   `{"count":0,"bundles":[]}`,
+			},
+			expectBundles: []flunkyhttp.SupportBundleDetails{},
+		},
+		{
+			name: "forbidden",
+			clientStub: &listClientStub{
+				listStatusCode: http.StatusForbidden,
+			},
+			expectErr: "http request failed with: 403 Forbidden",
+		},
+		{
+			name: "bad json",
+			clientStub: &listClientStub{
+				listStatusCode: http.StatusOK,
+				listResponse:   `bad json`,
+			},
+			expectErr: "invalid character 'b' looking for beginning of value",
+		},
+		{
+			name: "client error",
+			clientStub: &listClientStub{
+				listErr: errors.New("oops"),
+			},
+			expectErr: "oops",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			bundles, err := ListSupportBundles(context.Background(), test.clientStub)
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Empty(t, cmp.Diff(test.expectBundles, bundles))
+			}
+		})
+	}
+}
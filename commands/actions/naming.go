@@ -0,0 +1,228 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultNameTemplate is the name template used when none is configured. The hostname and the sequence number keep
+// apart the Support Bundles uploaded for the same case in the same second.
+const DefaultNameTemplate = "SB-{timestamp}-{hostname}-{seq}.zip"
+
+// Placeholders supported in a name template.
+const (
+	casePlaceholder      = "{case}"
+	serverIDPlaceholder  = "{serverId}"
+	bundleIDPlaceholder  = "{bundleId}"
+	timestampPlaceholder = "{timestamp}"
+	hostnamePlaceholder  = "{hostname}"
+	seqPlaceholder       = "{seq}"
+	timestampLayout      = "20060102-150405Z"
+	maxSequenceNumber    = 1000
+)
+
+var (
+	// safePathSegment matches the strings which can be used as a path segment of the upload URL: they can neither
+	// escape the case folder nor add matrix parameters.
+	safePathSegment     = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	unsafeCharacters    = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+	unknownPlaceholders = regexp.MustCompile(`{[^}]*}`)
+)
+
+// NameTemplate is the template of the name of an uploaded Support Bundle, such as "SB-{case}-{timestamp}.zip".
+type NameTemplate string
+
+// UploadTarget tells where and under which name a Support Bundle is uploaded.
+type UploadTarget struct {
+	RepoKey      string
+	CaseNumber   CaseNumber
+	NameTemplate NameTemplate
+	// ServerID is the ID of the source Artifactory service in JFrog CLI configuration, if known.
+	ServerID string
+	// Hostname is the name of the host running the upload.
+	Hostname   string
+	Properties UploadProperties
+}
+
+// Validate checks that the repository key, the case number and the name template cannot be used to upload outside
+// of the case folder.
+func (t UploadTarget) Validate() error {
+	if err := validatePathSegment("repository key", t.RepoKey); err != nil {
+		return err
+	}
+	if err := validatePathSegment("case number", string(t.CaseNumber)); err != nil {
+		return err
+	}
+	return t.getNameTemplate().validate()
+}
+
+func (t UploadTarget) getNameTemplate() NameTemplate {
+	if t.NameTemplate == "" {
+		return DefaultNameTemplate
+	}
+	return t.NameTemplate
+}
+
+func validatePathSegment(description string, value string) error {
+	if !safePathSegment.MatchString(value) || value == "." || value == ".." {
+		return fmt.Errorf("invalid %s %q, only letters, digits, '.', '_' and '-' are allowed", description, value)
+	}
+	return nil
+}
+
+func (t NameTemplate) validate() error {
+	// Placeholders are replaced with safe values, so only the literal part of the template needs to be checked.
+	withoutPlaceholders := t.render(map[string]string{
+		casePlaceholder: "c", serverIDPlaceholder: "s", bundleIDPlaceholder: "b", timestampPlaceholder: "t",
+		hostnamePlaceholder: "h", seqPlaceholder: "1",
+	})
+	if unknown := unknownPlaceholders.FindString(withoutPlaceholders); unknown != "" {
+		return fmt.Errorf("invalid name template %q, unknown placeholder %s", t, unknown)
+	}
+	if err := validatePathSegment("name template", withoutPlaceholders); err != nil {
+		return fmt.Errorf("invalid name template %q, only letters, digits, '.', '_', '-' and placeholders are allowed",
+			t)
+	}
+	return nil
+}
+
+func (t NameTemplate) render(values map[string]string) string {
+	name := string(t)
+	for placeholder, value := range values {
+		name = strings.ReplaceAll(name, placeholder, value)
+	}
+	return name
+}
+
+func (t NameTemplate) hasSequenceNumber() bool {
+	return strings.Contains(string(t), seqPlaceholder)
+}
+
+type caseFolderHTTPClient interface {
+	GetSupportCaseFolder(ctx context.Context, repoKey string, supportCaseDirectory string) (status int,
+		responseBytes []byte, err error)
+}
+
+// getSupportBundleFilename renders the name template of target. When the template contains a sequence number, the
+// lowest one giving a name which is not yet used in the case folder is selected. Otherwise, the upload is refused if
+// it would overwrite an existing file.
+func getSupportBundleFilename(ctx context.Context, client caseFolderHTTPClient, target UploadTarget, now Clock) (
+	string, error) {
+	template := target.getNameTemplate()
+	values := target.nameValues(now)
+	existing, err := listSupportCaseFolder(ctx, client, target)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to list the case folder, existing files cannot be checked: %+v", err))
+	}
+
+	if !template.hasSequenceNumber() {
+		filename := template.render(values)
+		if err = validatePathSegment("file name", filename); err != nil {
+			return "", err
+		}
+		if existing[filename] {
+			return "", fmt.Errorf("%s already exists in %s/%s, add %s or %s to the name template to keep both",
+				filename, target.RepoKey, target.CaseNumber, seqPlaceholder, hostnamePlaceholder)
+		}
+		return filename, nil
+	}
+	for seq := 1; seq <= maxSequenceNumber; seq++ {
+		values[seqPlaceholder] = strconv.Itoa(seq)
+		filename := template.render(values)
+		if err = validatePathSegment("file name", filename); err != nil {
+			return "", err
+		}
+		if !existing[filename] {
+			return filename, nil
+		}
+	}
+	return "", fmt.Errorf("no free sequence number left for %s in %s/%s", template, target.RepoKey,
+		target.CaseNumber)
+}
+
+// nameCollisionError tells that an upload was refused because another upload took its name meanwhile.
+type nameCollisionError struct {
+	err error
+}
+
+func (e *nameCollisionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *nameCollisionError) Unwrap() error {
+	return e.err
+}
+
+// checkNameCollision gives err as a nameCollisionError when the upload under filename was refused, with a conflict
+// or as it would overwrite a file, and filename is now used in the case folder. Only a name template with a sequence
+// number can select another name.
+func checkNameCollision(ctx context.Context, client caseFolderHTTPClient, target UploadTarget, filename string,
+	statusCode int, err error) error {
+	if !target.getNameTemplate().hasSequenceNumber() ||
+		(statusCode != http.StatusConflict && statusCode != http.StatusForbidden) {
+		return err
+	}
+	existing, listErr := listSupportCaseFolder(ctx, client, target)
+	if listErr != nil || !existing[filename] {
+		return err
+	}
+	return &nameCollisionError{err: err}
+}
+
+func (t UploadTarget) nameValues(now Clock) map[string]string {
+	return map[string]string{
+		casePlaceholder:      string(t.CaseNumber),
+		serverIDPlaceholder:  sanitize(t.ServerID),
+		bundleIDPlaceholder:  sanitize(string(t.Properties.BundleID)),
+		timestampPlaceholder: now().UTC().Format(timestampLayout),
+		hostnamePlaceholder:  sanitize(t.Hostname),
+	}
+}
+
+// previewFilename renders the name template of target without looking at the case folder. The ID of a Support Bundle
+// not created yet and the sequence number are left as placeholders.
+func (t UploadTarget) previewFilename(now Clock) string {
+	values := t.nameValues(now)
+	if t.Properties.BundleID == "" {
+		values[bundleIDPlaceholder] = bundleIDPlaceholder
+	}
+	return t.getNameTemplate().render(values)
+}
+
+// listSupportCaseFolder gives the names of the files in the case folder.
+func listSupportCaseFolder(ctx context.Context, client caseFolderHTTPClient, target UploadTarget) (map[string]bool,
+	error) {
+	statusCode, respBytes, err := client.GetSupportCaseFolder(ctx, target.RepoKey, string(target.CaseNumber))
+	if err != nil {
+		return nil, err
+	}
+	if statusCode == http.StatusNotFound {
+		return map[string]bool{}, nil
+	}
+	if statusCode != http.StatusOK {
+		return nil, newHTTPError(statusCode, respBytes)
+	}
+	var folder flunkyhttp.FolderInfo
+	if err = json.Unmarshal(respBytes, &folder); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(folder.Children))
+	for _, child := range folder.Children {
+		if !child.Folder {
+			names[strings.TrimPrefix(child.URI, "/")] = true
+		}
+	}
+	return names, nil
+}
+
+// sanitize replaces the characters which are not allowed in a file name.
+func sanitize(value string) string {
+	return unsafeCharacters.ReplaceAllString(value, "-")
+}
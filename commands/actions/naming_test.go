@@ -0,0 +1,160 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type caseFolderClientStub struct {
+	statusCode int
+	response   string
+	err        error
+}
+
+func (s *caseFolderClientStub) GetSupportCaseFolder(context.Context, string, string) (int, []byte, error) {
+	return s.statusCode, []byte(s.response), s.err
+}
+
+func Test_UploadTarget_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    UploadTarget
+		expectErr string
+	}{
+		{
+			name:   "valid",
+			target: UploadTarget{RepoKey: "logs", CaseNumber: "1234", NameTemplate: "SB-{case}_{seq}.zip"},
+		},
+		{
+			name:   "default template",
+			target: UploadTarget{RepoKey: "logs", CaseNumber: "1234"},
+		},
+		{
+			name:      "case number escaping the case folder",
+			target:    UploadTarget{RepoKey: "logs", CaseNumber: "../other"},
+			expectErr: `invalid case number "../other", only letters, digits, '.', '_' and '-' are allowed`,
+		},
+		{
+			name:      "case number being the parent folder",
+			target:    UploadTarget{RepoKey: "logs", CaseNumber: ".."},
+			expectErr: `invalid case number "..", only letters, digits, '.', '_' and '-' are allowed`,
+		},
+		{
+			name:      "empty case number",
+			target:    UploadTarget{RepoKey: "logs"},
+			expectErr: `invalid case number "", only letters, digits, '.', '_' and '-' are allowed`,
+		},
+		{
+			name:      "repo key with matrix parameter",
+			target:    UploadTarget{RepoKey: "logs;a=b", CaseNumber: "1234"},
+			expectErr: `invalid repository key "logs;a=b", only letters, digits, '.', '_' and '-' are allowed`,
+		},
+		{
+			name:      "template with a folder",
+			target:    UploadTarget{RepoKey: "logs", CaseNumber: "1234", NameTemplate: "../{case}.zip"},
+			expectErr: `invalid name template "../{case}.zip", only letters, digits, '.', '_', '-' and placeholders are allowed`,
+		},
+		{
+			name:      "template with unknown placeholder",
+			target:    UploadTarget{RepoKey: "logs", CaseNumber: "1234", NameTemplate: "{user}.zip"},
+			expectErr: `invalid name template "{user}.zip", unknown placeholder {user}`,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := test.target.Validate()
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_getSupportBundleFilename(t *testing.T) {
+	notFound := &caseFolderClientStub{statusCode: http.StatusNotFound}
+	existing := &caseFolderClientStub{
+		statusCode: http.StatusOK,
+		response: `{"children":[{"uri":"/SB-20201201-100000Z.zip","folder":false},` +
+			`{"uri":"/SB-1234-1.zip","folder":false},{"uri":"/SB-1234-2.zip","folder":false},` +
+			`{"uri":"/SB-1234-3.zip","folder":true}]}`,
+	}
+	tests := []struct {
+		name           string
+		client         *caseFolderClientStub
+		template       NameTemplate
+		expectFilename string
+		expectErr      string
+	}{
+		{
+			name:           "default template",
+			client:         notFound,
+			expectFilename: "SB-20201201-100000Z-host-1.local-1.zip",
+		},
+		{
+			name:           "all placeholders",
+			client:         notFound,
+			template:       "{case}_{serverId}_{bundleId}_{timestamp}_{hostname}_{seq}.zip",
+			expectFilename: "1234_my-server_20201201-0001_20201201-100000Z_host-1.local_1.zip",
+		},
+		{
+			name:           "next free sequence number",
+			client:         existing,
+			template:       "SB-{case}-{seq}.zip",
+			expectFilename: "SB-1234-3.zip",
+		},
+		{
+			name:     "existing file",
+			client:   existing,
+			template: "SB-{timestamp}.zip",
+			expectErr: "SB-20201201-100000Z.zip already exists in logs/1234, " +
+				"add {seq} or {hostname} to the name template to keep both",
+		},
+		{
+			name:           "case folder cannot be listed",
+			client:         &caseFolderClientStub{err: errors.New("oops")},
+			template:       "SB-{case}-{seq}.zip",
+			expectFilename: "SB-1234-1.zip",
+		},
+		{
+			name:      "empty name",
+			client:    notFound,
+			template:  "{bundleId}",
+			expectErr: `invalid file name "", only letters, digits, '.', '_' and '-' are allowed`,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			target := UploadTarget{
+				RepoKey:      "logs",
+				CaseNumber:   "1234",
+				NameTemplate: test.template,
+				ServerID:     "my server",
+				Hostname:     "host-1.local",
+			}
+			if test.template != "{bundleId}" {
+				target.Properties.BundleID = "20201201-0001"
+			}
+			filename, err := getSupportBundleFilename(context.Background(), test.client, target, func() time.Time {
+				return time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+			})
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectFilename, filename)
+			}
+		})
+	}
+}
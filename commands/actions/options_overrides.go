@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"time"
+)
+
+// OptionsOverrides holds Support Bundle creation options given on the command line. They take precedence over the
+// options of any OptionsProvider.
+type OptionsOverrides struct {
+	// LogsSince includes the logs from this long ago until today.
+	LogsSince time.Duration
+	// LogsFrom and LogsTo include the logs between these YYYY-MM-DD dates.
+	LogsFrom string
+	LogsTo   string
+	// ThreadDumps is the number of thread dumps to include, if not nil.
+	ThreadDumps        *uint
+	ThreadDumpInterval time.Duration
+}
+
+// IsEmpty tells if no option is overridden.
+func (o *OptionsOverrides) IsEmpty() bool {
+	return o.LogsSince == 0 && o.LogsFrom == "" && o.LogsTo == "" && o.ThreadDumps == nil &&
+		o.ThreadDumpInterval == 0
+}
+
+// OverridingOptionsProvider provides the options of another OptionsProvider, with some of them overridden.
+type OverridingOptionsProvider struct {
+	GetDate   Clock
+	Provider  OptionsProvider
+	Overrides OptionsOverrides
+}
+
+// GetOptions gets the options of the underlying provider, and applies the overrides. When the underlying provider
+// relies on Artifactory defaults, the parameters start from the same defaults: configuration, system info, logs of the
+// last day and a thread dump.
+func (p *OverridingOptionsProvider) GetOptions(caseNumber CaseNumber) (http.SupportBundleCreationOptions, error) {
+	options, err := p.Provider.GetOptions(caseNumber)
+	if err != nil {
+		return options, err
+	}
+	if p.Overrides.IsEmpty() {
+		return options, nil
+	}
+	if options.Parameters == nil {
+		defaults := OptionsFile{
+			Configuration: true,
+			System:        true,
+			Logs:          OptionsFileLogs{Include: true},
+			ThreadDump:    OptionsFileThreadDump{Count: 1},
+		}
+		options.Parameters, err = defaults.toParameters(p.GetDate)
+		if err != nil {
+			return options, err
+		}
+	}
+	if options.Parameters.Logs == nil {
+		options.Parameters.Logs = &http.SupportBundleParametersLogs{}
+	}
+	if options.Parameters.ThreadDump == nil {
+		options.Parameters.ThreadDump = &http.SupportBundleParametersThreadDump{}
+	}
+	if err = p.applyLogs(options.Parameters.Logs); err != nil {
+		return options, err
+	}
+	return options, p.applyThreadDump(options.Parameters.ThreadDump)
+}
+
+func (p *OverridingOptionsProvider) applyLogs(logs *http.SupportBundleParametersLogs) error {
+	overrides := &p.Overrides
+	if overrides.LogsSince == 0 && overrides.LogsFrom == "" && overrides.LogsTo == "" {
+		return nil
+	}
+	now := p.GetDate()
+	overridden := OptionsFile{Version: OptionsFileVersion, Logs: OptionsFileLogs{
+		Include:   true,
+		StartDate: overrides.LogsFrom,
+		EndDate:   overrides.LogsTo,
+	}}
+	if overrides.LogsSince > 0 {
+		overridden.Logs.StartDate = now.Add(-overrides.LogsSince).Format(optionsDateLayout)
+	}
+	parameters, err := overridden.toParameters(p.GetDate)
+	if err != nil {
+		return err
+	}
+	*logs = *parameters.Logs
+	return nil
+}
+
+func (p *OverridingOptionsProvider) applyThreadDump(threadDump *http.SupportBundleParametersThreadDump) error {
+	if p.Overrides.ThreadDumps != nil {
+		threadDump.Count = *p.Overrides.ThreadDumps
+	}
+	if p.Overrides.ThreadDumpInterval > 0 {
+		threadDump.Interval = uint(p.Overrides.ThreadDumpInterval / time.Millisecond)
+	}
+	if threadDump.Interval > 0 && threadDump.Count < 2 {
+		return fmt.Errorf("a thread dump interval requires at least 2 thread dumps, got %d", threadDump.Count)
+	}
+	return nil
+}
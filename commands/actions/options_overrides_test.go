@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type optionsProviderStub struct {
+	parameters *http.SupportBundleParameters
+	err        error
+}
+
+func (s *optionsProviderStub) GetOptions(CaseNumber) (http.SupportBundleCreationOptions, error) {
+	return http.SupportBundleCreationOptions{Name: "n", Parameters: s.parameters}, s.err
+}
+
+func Test_OverridingOptionsProvider(t *testing.T) {
+	five := uint(5)
+	zero := uint(0)
+	prompted := func() *http.SupportBundleParameters {
+		return &http.SupportBundleParameters{
+			Logs:       &http.SupportBundleParametersLogs{Include: false, StartDate: "2012-10-31", EndDate: "2012-11-01"},
+			System:     true,
+			ThreadDump: &http.SupportBundleParametersThreadDump{Count: 1},
+		}
+	}
+	tests := []struct {
+		name             string
+		parameters       *http.SupportBundleParameters
+		overrides        OptionsOverrides
+		expectParameters *http.SupportBundleParameters
+		expectErr        string
+	}{
+		{
+			name:             "no overrides keeps Artifactory defaults",
+			expectParameters: nil,
+		},
+		{
+			name:      "logs since on Artifactory defaults",
+			overrides: OptionsOverrides{LogsSince: 72 * time.Hour},
+			expectParameters: &http.SupportBundleParameters{
+				Configuration: true,
+				Logs:          &http.SupportBundleParametersLogs{Include: true, StartDate: "2012-10-29", EndDate: "2012-11-01"},
+				System:        true,
+				ThreadDump:    &http.SupportBundleParametersThreadDump{Count: 1},
+			},
+		},
+		{
+			name:       "logs range",
+			parameters: prompted(),
+			overrides:  OptionsOverrides{LogsFrom: "2012-09-01", LogsTo: "2012-09-05"},
+			expectParameters: &http.SupportBundleParameters{
+				Logs:       &http.SupportBundleParametersLogs{Include: true, StartDate: "2012-09-01", EndDate: "2012-09-05"},
+				System:     true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 1},
+			},
+		},
+		{
+			name:       "logs from until today",
+			parameters: prompted(),
+			overrides:  OptionsOverrides{LogsFrom: "2012-10-20"},
+			expectParameters: &http.SupportBundleParameters{
+				Logs:       &http.SupportBundleParametersLogs{Include: true, StartDate: "2012-10-20", EndDate: "2012-11-01"},
+				System:     true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 1},
+			},
+		},
+		{
+			name:       "thread dumps",
+			parameters: prompted(),
+			overrides:  OptionsOverrides{ThreadDumps: &five, ThreadDumpInterval: 10 * time.Second},
+			expectParameters: &http.SupportBundleParameters{
+				Logs:       &http.SupportBundleParametersLogs{Include: false, StartDate: "2012-10-31", EndDate: "2012-11-01"},
+				System:     true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 5, Interval: 10000},
+			},
+		},
+		{
+			name:       "no thread dump",
+			parameters: &http.SupportBundleParameters{ThreadDump: &http.SupportBundleParametersThreadDump{Count: 1}},
+			overrides:  OptionsOverrides{ThreadDumps: &zero},
+			expectParameters: &http.SupportBundleParameters{
+				Logs:       &http.SupportBundleParametersLogs{},
+				ThreadDump: &http.SupportBundleParametersThreadDump{Count: 0},
+			},
+		},
+		{
+			name:       "interval without several thread dumps",
+			parameters: prompted(),
+			overrides:  OptionsOverrides{ThreadDumpInterval: time.Second},
+			expectErr:  "a thread dump interval requires at least 2 thread dumps, got 1",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			provider := &OverridingOptionsProvider{
+				GetDate: func() time.Time {
+					return time.Unix(1351807721, 0).UTC()
+				},
+				Provider:  &optionsProviderStub{parameters: test.parameters},
+				Overrides: test.overrides,
+			}
+			options, err := provider.GetOptions("foo")
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "n", options.Name)
+			assert.Empty(t, cmp.Diff(test.expectParameters, options.Parameters))
+		})
+	}
+}
+
+func Test_OverridingOptionsProvider_Error(t *testing.T) {
+	provider := &OverridingOptionsProvider{
+		GetDate:   time.Now,
+		Provider:  &optionsProviderStub{err: errors.New("oops")},
+		Overrides: OptionsOverrides{LogsSince: time.Hour},
+	}
+	_, err := provider.GetOptions("foo")
+	assert.EqualError(t, err, "oops")
+}
@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"net/http"
+	neturl "net/url"
+	"strings"
+)
+
+// Placeholders of the values only known while support-case runs, used in an ExecutionPlan.
+const (
+	planBundleID = "{bundleId}"
+	planSHA256   = "{sha256}"
+)
+
+type planHTTPClient interface {
+	GetURL() string
+	CreateSupportBundleURL() string
+	SupportBundleURL(bundleID string) string
+	SupportBundleArchiveURL(bundleID string) string
+	VersionURL() string
+	ChecksumSearchURL(repoKey string, sha256 string) string
+	SupportCaseFolderURL(repoKey string, supportCaseDirectory string) string
+	UploadURL(repoKey string, supportCaseDirectory string, filename string,
+		properties *servicesutils.Properties) string
+}
+
+// PlanStep is an HTTP request support-case would send.
+type PlanStep struct {
+	Description string `json:"description" yaml:"description"`
+	Method      string `json:"method" yaml:"method"`
+	URL         string `json:"url" yaml:"url"`
+}
+
+// ExecutionPlan tells what support-case would do, without sending any request. Values only known while running, such
+// as the ID of the created Support Bundle, are given as placeholders.
+type ExecutionPlan struct {
+	SourceURL string                                  `json:"sourceUrl" yaml:"sourceUrl"`
+	TargetURL string                                  `json:"targetUrl" yaml:"targetUrl"`
+	Payload   flunkyhttp.SupportBundleCreationOptions `json:"payload" yaml:"payload"`
+	UploadURL string                                  `json:"uploadUrl" yaml:"uploadUrl"`
+	// Properties are the Artifactory properties attached to the uploaded Support Bundle, as key=value.
+	Properties []string   `json:"properties" yaml:"properties"`
+	Steps      []PlanStep `json:"steps" yaml:"steps"`
+}
+
+// PlanSupportBundle gives the execution plan of support-case: creating the Support Bundle with the given options on
+// the source service, and transferring it to the target, through a local file or streamed.
+func PlanSupportBundle(source planHTTPClient, targetClient planHTTPClient,
+	options flunkyhttp.SupportBundleCreationOptions, target UploadTarget, stream bool, now Clock) (*ExecutionPlan,
+	error) {
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	filename := target.previewFilename(now)
+	plan := &ExecutionPlan{
+		SourceURL: source.GetURL(),
+		TargetURL: targetClient.GetURL(),
+		Payload:   options,
+		UploadURL: getUploadURL(targetClient, target, filename),
+	}
+	for _, property := range target.Properties.toProperties(target.CaseNumber).Properties {
+		plan.Properties = append(plan.Properties, fmt.Sprintf("%s=%s", property.Key, property.Value))
+	}
+	plan.Steps = []PlanStep{
+		{"Create the Support Bundle", http.MethodPost, source.CreateSupportBundleURL()},
+		{"Get the version of the source service", http.MethodGet, source.VersionURL()},
+	}
+	plan.Steps = append(plan.Steps, planTransferSteps(source, targetClient, target, filename, stream)...)
+	return plan, nil
+}
+
+func planTransferSteps(source planHTTPClient, targetClient planHTTPClient, target UploadTarget, filename string,
+	stream bool) []PlanStep {
+	caseNumber := string(target.CaseNumber)
+	uploadURL := targetClient.UploadURL(target.RepoKey, caseNumber, filename, nil)
+	listCaseFolder := PlanStep{"List the case folder to name the Support Bundle", http.MethodGet,
+		targetClient.SupportCaseFolderURL(target.RepoKey, caseNumber)}
+	waitUntilReady := PlanStep{"Wait until the Support Bundle is ready", http.MethodGet,
+		source.SupportBundleURL(planBundleID)}
+	if stream {
+		return []PlanStep{
+			listCaseFolder,
+			waitUntilReady,
+			{"Stream the Support Bundle archive", http.MethodGet, source.SupportBundleArchiveURL(planBundleID)},
+			{"Upload the streamed Support Bundle", http.MethodPut, uploadURL},
+		}
+	}
+	return []PlanStep{
+		waitUntilReady,
+		{"Download the Support Bundle archive to a local file", http.MethodGet,
+			source.SupportBundleArchiveURL(planBundleID)},
+		{"Look for the same Support Bundle already uploaded", http.MethodGet,
+			// Keep the placeholder readable.
+			strings.Replace(targetClient.ChecksumSearchURL(target.RepoKey, planSHA256), neturl.QueryEscape(planSHA256),
+				planSHA256, 1)},
+		listCaseFolder,
+		{"Deploy the Support Bundle by checksum", http.MethodPut, uploadURL},
+		{"Upload the Support Bundle, unless deployed by checksum", http.MethodPut, uploadURL},
+	}
+}
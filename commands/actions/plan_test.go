@@ -0,0 +1,92 @@
+package actions
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_PlanSupportBundle(t *testing.T) {
+	source := &flunkyhttp.Client{RtDetails: &config.ArtifactoryDetails{Url: "http://source/"}}
+	target := &flunkyhttp.Client{RtDetails: &config.ArtifactoryDetails{Url: "http://target/"}}
+	now := func() time.Time {
+		return time.Date(2020, 12, 3, 22, 10, 0, 0, time.UTC)
+	}
+	uploadURL := "http://target/logs/1234/SB-1234-{bundleId}-20201203-221000Z.zip;uploadedBy=support-bundle-flunky"
+	tests := []struct {
+		name        string
+		target      UploadTarget
+		stream      bool
+		expectSteps []PlanStep
+		expectErr   string
+	}{
+		{
+			name: "download and upload",
+			target: UploadTarget{
+				RepoKey:      "logs",
+				CaseNumber:   "1234",
+				NameTemplate: "SB-{case}-{bundleId}-{timestamp}.zip",
+			},
+			expectSteps: []PlanStep{
+				{"Create the Support Bundle", "POST", "http://source/api/system/support/bundle"},
+				{"Get the version of the source service", "GET", "http://source/api/system/version"},
+				{"Wait until the Support Bundle is ready", "GET", "http://source/api/system/support/bundle/{bundleId}"},
+				{"Download the Support Bundle archive to a local file", "GET",
+					"http://source/api/system/support/bundle/{bundleId}/archive"},
+				{"Look for the same Support Bundle already uploaded", "GET",
+					"http://target/api/search/checksum?sha256={sha256}&repos=logs"},
+				{"List the case folder to name the Support Bundle", "GET", "http://target/api/storage/logs/1234"},
+				{"Deploy the Support Bundle by checksum", "PUT", uploadURL},
+				{"Upload the Support Bundle, unless deployed by checksum", "PUT", uploadURL},
+			},
+		},
+		{
+			name: "stream",
+			target: UploadTarget{
+				RepoKey:      "logs",
+				CaseNumber:   "1234",
+				NameTemplate: "SB-{case}-{bundleId}-{timestamp}.zip",
+			},
+			stream: true,
+			expectSteps: []PlanStep{
+				{"Create the Support Bundle", "POST", "http://source/api/system/support/bundle"},
+				{"Get the version of the source service", "GET", "http://source/api/system/version"},
+				{"List the case folder to name the Support Bundle", "GET", "http://target/api/storage/logs/1234"},
+				{"Wait until the Support Bundle is ready", "GET", "http://source/api/system/support/bundle/{bundleId}"},
+				{"Stream the Support Bundle archive", "GET",
+					"http://source/api/system/support/bundle/{bundleId}/archive"},
+				{"Upload the streamed Support Bundle", "PUT", uploadURL},
+			},
+		},
+		{
+			name:      "invalid target",
+			target:    UploadTarget{RepoKey: "logs", CaseNumber: "../1234"},
+			expectErr: `invalid case number "../1234", only letters, digits, '.', '_' and '-' are allowed`,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			test.target.Properties = UploadProperties{SourceURL: "http://source/", FlunkyVersion: "v1"}
+			plan, err := PlanSupportBundle(source, target, flunkyhttp.SupportBundleCreationOptions{Name: "sb"},
+				test.target, test.stream, now)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "http://target/logs/1234/SB-1234-{bundleId}-20201203-221000Z.zip", plan.UploadURL)
+			assert.Equal(t, []string{
+				"support.caseNumber=1234",
+				"support.sourceUrl=http://source/",
+				"support.flunkyVersion=v1",
+			}, plan.Properties)
+			assert.Empty(t, cmp.Diff(test.expectSteps, plan.Steps))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"strings"
+)
+
+// Preset is a built-in set of Support Bundle creation options for a common type of incident.
+type Preset struct {
+	Name        string      `json:"name" yaml:"name"`
+	Description string      `json:"description" yaml:"description"`
+	Options     OptionsFile `json:"options" yaml:"options"`
+}
+
+var presets = []Preset{
+	{
+		Name:        "minimal",
+		Description: "Questions about the setup of the service",
+		Options: OptionsFile{
+			Version:       OptionsFileVersion,
+			Configuration: true,
+		},
+	},
+	{
+		Name:        "full",
+		Description: "Any other issue, when unsure of what is needed",
+		Options: OptionsFile{
+			Version:       OptionsFileVersion,
+			Configuration: true,
+			System:        true,
+			Logs:          OptionsFileLogs{Include: true, LastDays: 7},
+			ThreadDump:    OptionsFileThreadDump{Count: 1},
+		},
+	},
+	{
+		Name:        "performance",
+		Description: "Slowness, high CPU usage or hanging requests",
+		Options: OptionsFile{
+			Version:       OptionsFileVersion,
+			Configuration: true,
+			System:        true,
+			Logs:          OptionsFileLogs{Include: true, LastDays: 1},
+			ThreadDump:    OptionsFileThreadDump{Count: 5, Interval: 2000},
+		},
+	},
+	{
+		Name: "startup-failure",
+		Description: "Service failing to start, with the logs of the last 3 days as an approximation of the logs " +
+			"since the last restart, use --logs-since to go further back",
+		Options: OptionsFile{
+			Version:       OptionsFileVersion,
+			Configuration: true,
+			System:        true,
+			Logs:          OptionsFileLogs{Include: true, LastDays: 3},
+		},
+	},
+}
+
+// Presets gives the built-in presets.
+func Presets() []Preset {
+	return append([]Preset(nil), presets...)
+}
+
+// GetPreset gives the built-in preset with the given name.
+func GetPreset(name string) (Preset, error) {
+	names := make([]string, len(presets))
+	for i := range presets {
+		if presets[i].Name == name {
+			return presets[i], nil
+		}
+		names[i] = presets[i].Name
+	}
+	return Preset{}, fmt.Errorf("unknown preset %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// PresetOptionsProvider provides Support Bundle creation options from a built-in preset.
+type PresetOptionsProvider struct {
+	GetDate Clock
+	Preset  Preset
+}
+
+// NewPresetOptionsProvider creates a new PresetOptionsProvider for the built-in preset with the given name.
+func NewPresetOptionsProvider(name string, getDate Clock) (*PresetOptionsProvider, error) {
+	preset, err := GetPreset(name)
+	if err != nil {
+		return nil, err
+	}
+	return &PresetOptionsProvider{
+		GetDate: getDate,
+		Preset:  preset,
+	}, nil
+}
+
+// GetOptions gets the options of the preset.
+func (p *PresetOptionsProvider) GetOptions(caseNumber CaseNumber) (http.SupportBundleCreationOptions, error) {
+	return p.Preset.Options.toCreationOptions(caseNumber, p.GetDate)
+}
@@ -0,0 +1,67 @@
+package actions
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_Presets(t *testing.T) {
+	for _, preset := range Presets() {
+		preset := preset
+		t.Run(preset.Name, func(t *testing.T) {
+			require.NoError(t, preset.Options.Validate())
+			provider, err := NewPresetOptionsProvider(preset.Name, time.Now)
+			require.NoError(t, err)
+			options, err := provider.GetOptions("foo")
+			require.NoError(t, err)
+			require.NotNil(t, options.Parameters)
+			assert.NotNil(t, options.Parameters.Logs)
+			assert.NotNil(t, options.Parameters.ThreadDump)
+			assert.True(t, IsCreatedByFlunky(options.Name))
+		})
+	}
+}
+
+func Test_PresetOptionsProvider(t *testing.T) {
+	preset, err := GetPreset("performance")
+	require.NoError(t, err)
+	provider := &PresetOptionsProvider{
+		GetDate: func() time.Time {
+			return time.Unix(1351807721, 0)
+		},
+		Preset: preset,
+	}
+
+	options, err := provider.GetOptions("foo")
+
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Diff(
+		http.SupportBundleCreationOptions{
+			Name:        "JFrog Support Case number foo",
+			Description: "Generated on 2012-11-01T22:08:41Z",
+			Parameters: &http.SupportBundleParameters{
+				Configuration: true,
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-31",
+					EndDate:   "2012-11-01",
+				},
+				System: true,
+				ThreadDump: &http.SupportBundleParametersThreadDump{
+					Count:    5,
+					Interval: 2000,
+				},
+			},
+		},
+		options))
+}
+
+func Test_GetPreset_Unknown(t *testing.T) {
+	_, err := NewPresetOptionsProvider("everything", time.Now)
+	assert.EqualError(t, err,
+		`unknown preset "everything", expected one of: minimal, full, performance, startup-failure`)
+}
@@ -1,16 +1,48 @@
 package actions
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
 	"time"
 )
 
+// ErrCreationCancelled is returned when the user does not confirm the options of the Support Bundle to create.
+var ErrCreationCancelled = errors.New("support bundle creation cancelled")
+
+// defaultThreadDumpInterval is the interval suggested between several thread dumps, in milliseconds.
+const defaultThreadDumpInterval = 1000
+
 // Prompter defines what options can be chosen by the user to configure the Support Bundle.
 type Prompter interface {
 	AskIncludeLogs() (bool, error)
 	AskIncludeSystem() (bool, error)
 	AskIncludeConfiguration() (bool, error)
 	AskThreadDump() (bool, error)
+	AskLogsDateRange(defaultStartDate string, defaultEndDate string) (startDate string, endDate string, err error)
+	AskThreadDumpCount(defaultCount uint) (uint, error)
+	AskThreadDumpInterval(defaultInterval uint) (uint, error)
+	AskDescription(defaultDescription string) (string, error)
+}
+
+// OptionsConfirmer asks the user to confirm the options of the Support Bundle to create.
+type OptionsConfirmer interface {
+	ConfirmOptions(payload string) (bool, error)
+}
+
+// WizardPrompter defines what is asked to run the support-case command without arguments.
+type WizardPrompter interface {
+	AskCaseNumber() (CaseNumber, error)
+	AskSourceServerID(serverIDs []string, defaultServerID string) (string, error)
+	AskTargetServerID(serverIDs []string, defaultServerID string) (string, error)
+}
+
+// FullPrompter asks every question of this plugin. Both TerminalPrompter and AnswersPrompter implement it, so that a
+// question added to the interactive flow also has to be answerable from an answers file.
+type FullPrompter interface {
+	Prompter
+	OptionsConfirmer
+	WizardPrompter
 }
 
 // PromptOptionsProvider provides Support Bundle creation options based on a Prompter.
@@ -20,10 +52,10 @@ type PromptOptionsProvider struct {
 }
 
 // NewPromptOptionsProvider creates a new PromptOptionsProvider.
-func NewPromptOptionsProvider() *PromptOptionsProvider {
+func NewPromptOptionsProvider(getDate Clock, prompter Prompter) *PromptOptionsProvider {
 	return &PromptOptionsProvider{
-		GetDate:  time.Now,
-		Prompter: &TerminalPrompter{},
+		GetDate:  getDate,
+		Prompter: prompter,
 	}
 }
 
@@ -33,12 +65,17 @@ func (p *PromptOptionsProvider) GetOptions(caseNumber CaseNumber) (http.SupportB
 	if err != nil {
 		return options, err
 	}
+	now := p.GetDate()
+	yesterday := now.Add(-24 * time.Hour)
 	options.Parameters = &http.SupportBundleParameters{
-		Logs:       &http.SupportBundleParametersLogs{},
+		Logs: &http.SupportBundleParametersLogs{
+			StartDate: yesterday.Format(optionsDateLayout),
+			EndDate:   now.Format(optionsDateLayout),
+		},
 		ThreadDump: &http.SupportBundleParametersThreadDump{},
 	}
 
-	if options.Parameters.Logs.Include, err = p.Prompter.AskIncludeLogs(); err != nil {
+	if err = p.askLogs(options.Parameters.Logs); err != nil {
 		return options, err
 	}
 	if options.Parameters.Configuration, err = p.Prompter.AskIncludeConfiguration(); err != nil {
@@ -47,17 +84,72 @@ func (p *PromptOptionsProvider) GetOptions(caseNumber CaseNumber) (http.SupportB
 	if options.Parameters.System, err = p.Prompter.AskIncludeSystem(); err != nil {
 		return options, err
 	}
-	if askThreadDump, err := p.Prompter.AskThreadDump(); err != nil {
+	if err = p.askThreadDump(options.Parameters.ThreadDump); err != nil {
 		return options, err
-	} else if askThreadDump {
-		options.Parameters.ThreadDump.Count = 1
-		options.Parameters.ThreadDump.Interval = 0
 	}
+	options.Description, err = p.Prompter.AskDescription(options.Description)
+	return options, err
+}
 
-	now := p.GetDate()
-	yesterday := now.Add(-24 * time.Hour)
-	options.Parameters.Logs.StartDate = yesterday.Format("2006-01-02")
-	options.Parameters.Logs.EndDate = now.Format("2006-01-02")
+func (p *PromptOptionsProvider) askLogs(logs *http.SupportBundleParametersLogs) error {
+	var err error
+	if logs.Include, err = p.Prompter.AskIncludeLogs(); err != nil || !logs.Include {
+		return err
+	}
+	startDate, endDate, err := p.Prompter.AskLogsDateRange(logs.StartDate, logs.EndDate)
+	if err != nil {
+		return err
+	}
+	logsOptions := OptionsFile{Version: OptionsFileVersion, Logs: OptionsFileLogs{
+		Include:   true,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}}
+	if err = logsOptions.Validate(); err != nil {
+		return err
+	}
+	logs.StartDate, logs.EndDate = startDate, endDate
+	return nil
+}
 
+func (p *PromptOptionsProvider) askThreadDump(threadDump *http.SupportBundleParametersThreadDump) error {
+	if include, err := p.Prompter.AskThreadDump(); err != nil || !include {
+		return err
+	}
+	count, err := p.Prompter.AskThreadDumpCount(1)
+	if err != nil {
+		return err
+	}
+	threadDump.Count = count
+	if count > 1 {
+		threadDump.Interval, err = p.Prompter.AskThreadDumpInterval(defaultThreadDumpInterval)
+	}
+	return err
+}
+
+// ConfirmingOptionsProvider shows the options of another OptionsProvider as the payload sent to Artifactory, and only
+// gives them once confirmed by the user.
+type ConfirmingOptionsProvider struct {
+	Provider  OptionsProvider
+	Confirmer OptionsConfirmer
+}
+
+// GetOptions gets the options of the underlying provider, once confirmed.
+func (p *ConfirmingOptionsProvider) GetOptions(caseNumber CaseNumber) (http.SupportBundleCreationOptions, error) {
+	options, err := p.Provider.GetOptions(caseNumber)
+	if err != nil {
+		return options, err
+	}
+	payload, err := json.MarshalIndent(options, "", "  ")
+	if err != nil {
+		return options, err
+	}
+	confirmed, err := p.Confirmer.ConfirmOptions(string(payload))
+	if err != nil {
+		return options, err
+	}
+	if !confirmed {
+		return options, ErrCreationCancelled
+	}
 	return options, nil
 }
@@ -12,10 +12,11 @@ import (
 
 func Test_Prompt(t *testing.T) {
 	tests := []struct {
-		name             string
-		stub             PrompterStub
-		expectErr        string
-		expectParameters http.SupportBundleParameters
+		name              string
+		stub              PrompterStub
+		expectErr         string
+		expectDescription string
+		expectParameters  http.SupportBundleParameters
 	}{
 		{
 			name: "Include all",
@@ -113,6 +114,88 @@ func Test_Prompt(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Logs range and thread dumps",
+			stub: PrompterStub{
+				IncludeLogs:        true,
+				IncludeThreadDump:  true,
+				LogsStartDate:      "2012-10-01",
+				LogsEndDate:        "2012-10-03",
+				ThreadDumpCount:    5,
+				ThreadDumpInterval: 2000,
+				Description:        "Slow startup",
+			},
+			expectDescription: "Slow startup",
+			expectParameters: http.SupportBundleParameters{
+				Logs: &http.SupportBundleParametersLogs{
+					Include:   true,
+					StartDate: "2012-10-01",
+					EndDate:   "2012-10-03",
+				},
+				ThreadDump: &http.SupportBundleParametersThreadDump{
+					Count:    5,
+					Interval: 2000,
+				},
+			},
+		},
+		{
+			name: "Several thread dumps with default interval",
+			stub: PrompterStub{
+				IncludeThreadDump: true,
+				ThreadDumpCount:   3,
+			},
+			expectParameters: http.SupportBundleParameters{
+				Logs: &http.SupportBundleParametersLogs{
+					StartDate: "2012-10-31",
+					EndDate:   "2012-11-01",
+				},
+				ThreadDump: &http.SupportBundleParametersThreadDump{
+					Count:    3,
+					Interval: 1000,
+				},
+			},
+		},
+		{
+			name: "Logs range in the wrong order",
+			stub: PrompterStub{
+				IncludeLogs:   true,
+				LogsStartDate: "2012-10-03",
+				LogsEndDate:   "2012-10-01",
+			},
+			expectErr: "logs.endDate 2012-10-01 is before logs.startDate 2012-10-03",
+		},
+		{
+			name: "Error on LogsDateRange",
+			stub: PrompterStub{
+				IncludeLogs:      true,
+				LogsDateRangeErr: errors.New("oops"),
+			},
+			expectErr: "oops",
+		},
+		{
+			name: "Error on ThreadDumpCount",
+			stub: PrompterStub{
+				IncludeThreadDump:  true,
+				ThreadDumpCountErr: errors.New("oops"),
+			},
+			expectErr: "oops",
+		},
+		{
+			name: "Error on ThreadDumpInterval",
+			stub: PrompterStub{
+				IncludeThreadDump:     true,
+				ThreadDumpCount:       2,
+				ThreadDumpIntervalErr: errors.New("oops"),
+			},
+			expectErr: "oops",
+		},
+		{
+			name: "Error on Description",
+			stub: PrompterStub{
+				DescriptionErr: errors.New("oops"),
+			},
+			expectErr: "oops",
+		},
 		{
 			name: "Error on IncludeLogs",
 			stub: PrompterStub{
@@ -157,10 +240,14 @@ func Test_Prompt(t *testing.T) {
 				assert.EqualError(t, err, test.expectErr)
 			} else {
 				require.NoError(t, err)
+				expectDescription := test.expectDescription
+				if expectDescription == "" {
+					expectDescription = "Generated on 2012-11-01T22:08:41Z"
+				}
 				assert.Empty(t, cmp.Diff(
 					http.SupportBundleCreationOptions{
 						Name:        "JFrog Support Case number foo",
-						Description: "Generated on 2012-11-01T22:08:41Z",
+						Description: expectDescription,
 						Parameters:  &test.expectParameters,
 					},
 					options))
@@ -168,3 +255,47 @@ func Test_Prompt(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConfirmingOptionsProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		stub      PrompterStub
+		expectErr string
+	}{
+		{
+			name: "Confirmed",
+			stub: PrompterStub{Confirm: true},
+		},
+		{
+			name:      "Not confirmed",
+			stub:      PrompterStub{Confirm: false},
+			expectErr: "support bundle creation cancelled",
+		},
+		{
+			name:      "Error on Confirm",
+			stub:      PrompterStub{ConfirmErr: errors.New("oops")},
+			expectErr: "oops",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			provider := &ConfirmingOptionsProvider{
+				Provider: &DefaultOptionsProvider{getDate: func() time.Time {
+					return time.Unix(1351807721, 0).UTC()
+				}},
+				Confirmer: &test.stub,
+			}
+			options, err := provider.GetOptions("foo")
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "JFrog Support Case number foo", options.Name)
+			}
+			assert.Equal(t, "{\n  \"name\": \"JFrog Support Case number foo\",\n"+
+				"  \"description\": \"Generated on 2012-11-01T22:08:41Z\",\n  \"parameters\": {}\n}",
+				test.stub.ConfirmedPayload)
+		})
+	}
+}
@@ -1,15 +1,34 @@
 package actions
 
-// PrompterStub is a stub for a Prompter, used for tests.
+// PrompterStub is a stub for a FullPrompter, used for tests. Unset answers to questions with a default answer give the
+// default answer.
 type PrompterStub struct {
 	IncludeLogs             bool
 	IncludeSystem           bool
 	IncludeConfiguration    bool
 	IncludeThreadDump       bool
+	LogsStartDate           string
+	LogsEndDate             string
+	ThreadDumpCount         uint
+	ThreadDumpInterval      uint
+	Description             string
+	Confirm                 bool
+	CaseNumber              CaseNumber
+	SourceServerID          string
+	TargetServerID          string
 	IncludeLogsErr          error
 	IncludeSystemErr        error
 	IncludeConfigurationErr error
 	IncludeThreadDumpErr    error
+	LogsDateRangeErr        error
+	ThreadDumpCountErr      error
+	ThreadDumpIntervalErr   error
+	DescriptionErr          error
+	ConfirmErr              error
+	CaseNumberErr           error
+	ServerIDErr             error
+	// ConfirmedPayload is the payload given to ConfirmOptions.
+	ConfirmedPayload string
 }
 
 // AskIncludeLogs tells if logs must be included.
@@ -31,3 +50,59 @@ func (s *PrompterStub) AskIncludeConfiguration() (bool, error) {
 func (s *PrompterStub) AskThreadDump() (bool, error) {
 	return s.IncludeThreadDump, s.IncludeThreadDumpErr
 }
+
+// AskLogsDateRange tells the first and last days of the logs to include.
+func (s *PrompterStub) AskLogsDateRange(defaultStartDate string, defaultEndDate string) (string, string, error) {
+	return stringOrDefault(s.LogsStartDate, defaultStartDate), stringOrDefault(s.LogsEndDate, defaultEndDate),
+		s.LogsDateRangeErr
+}
+
+// AskThreadDumpCount tells how many thread dumps must be included.
+func (s *PrompterStub) AskThreadDumpCount(defaultCount uint) (uint, error) {
+	return uintOrDefault(s.ThreadDumpCount, defaultCount), s.ThreadDumpCountErr
+}
+
+// AskThreadDumpInterval tells the interval between thread dumps, in milliseconds.
+func (s *PrompterStub) AskThreadDumpInterval(defaultInterval uint) (uint, error) {
+	return uintOrDefault(s.ThreadDumpInterval, defaultInterval), s.ThreadDumpIntervalErr
+}
+
+// AskDescription tells the description of the Support Bundle.
+func (s *PrompterStub) AskDescription(defaultDescription string) (string, error) {
+	return stringOrDefault(s.Description, defaultDescription), s.DescriptionErr
+}
+
+// ConfirmOptions records the payload and tells if it is confirmed.
+func (s *PrompterStub) ConfirmOptions(payload string) (bool, error) {
+	s.ConfirmedPayload = payload
+	return s.Confirm, s.ConfirmErr
+}
+
+// AskCaseNumber tells the JFrog Support case number.
+func (s *PrompterStub) AskCaseNumber() (CaseNumber, error) {
+	return s.CaseNumber, s.CaseNumberErr
+}
+
+// AskSourceServerID tells which of the given services to create the Support Bundle on.
+func (s *PrompterStub) AskSourceServerID(_ []string, defaultServerID string) (string, error) {
+	return stringOrDefault(s.SourceServerID, defaultServerID), s.ServerIDErr
+}
+
+// AskTargetServerID tells which of the given services to upload the Support Bundle to.
+func (s *PrompterStub) AskTargetServerID(_ []string, defaultServerID string) (string, error) {
+	return stringOrDefault(s.TargetServerID, defaultServerID), s.ServerIDErr
+}
+
+func stringOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func uintOrDefault(value uint, defaultValue uint) uint {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package actions
+
+import (
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"strconv"
+	"strings"
+)
+
+// Keys of the Artifactory properties attached to an uploaded Support Bundle.
+const (
+	propertyPrefix             = "support."
+	caseNumberProperty         = propertyPrefix + "caseNumber"
+	sourceURLProperty          = propertyPrefix + "sourceUrl"
+	sourceVersionProperty      = propertyPrefix + "sourceVersion"
+	bundleIDProperty           = propertyPrefix + "bundleId"
+	flunkyVersionProperty      = propertyPrefix + "flunkyVersion"
+	optionsPropertyPrefix      = propertyPrefix + "options."
+	configurationProperty      = optionsPropertyPrefix + "configuration"
+	systemProperty             = optionsPropertyPrefix + "system"
+	logsProperty               = optionsPropertyPrefix + "logs"
+	logsStartDateProperty      = optionsPropertyPrefix + "logs.startDate"
+	logsEndDateProperty        = optionsPropertyPrefix + "logs.endDate"
+	threadDumpCountProperty    = optionsPropertyPrefix + "threadDump.count"
+	threadDumpIntervalProperty = optionsPropertyPrefix + "threadDump.interval"
+)
+
+// UploadProperties describes where a Support Bundle comes from. It is attached to the uploaded archive as Artifactory
+// properties, so that it can be found with AQL. Empty fields are left out.
+type UploadProperties struct {
+	SourceURL     string
+	SourceVersion string
+	BundleID      BundleID
+	Options       *flunkyhttp.SupportBundleParameters
+	FlunkyVersion string
+	// Custom holds the properties given by the user.
+	Custom []servicesutils.Property
+}
+
+// ParseCustomProperties parses properties given by the user as "key1=value1;key2=value2".
+func ParseCustomProperties(properties string) ([]servicesutils.Property, error) {
+	parsed, err := servicesutils.ParseProperties(properties, servicesutils.JoinCommas)
+	if err != nil {
+		return nil, err
+	}
+	for i := range parsed.Properties {
+		parsed.Properties[i].Key = strings.TrimSpace(parsed.Properties[i].Key)
+		parsed.Properties[i].Value = strings.TrimSpace(parsed.Properties[i].Value)
+	}
+	return parsed.Properties, nil
+}
+
+func (p UploadProperties) toProperties(caseNumber CaseNumber) *servicesutils.Properties {
+	props := &servicesutils.Properties{}
+	add := func(key string, value string) {
+		if value != "" {
+			props.Properties = append(props.Properties, servicesutils.Property{Key: key, Value: value})
+		}
+	}
+	add(caseNumberProperty, string(caseNumber))
+	add(sourceURLProperty, p.SourceURL)
+	add(sourceVersionProperty, p.SourceVersion)
+	add(bundleIDProperty, string(p.BundleID))
+	if p.Options != nil {
+		add(configurationProperty, strconv.FormatBool(p.Options.Configuration))
+		add(systemProperty, strconv.FormatBool(p.Options.System))
+		if p.Options.Logs != nil {
+			add(logsProperty, strconv.FormatBool(p.Options.Logs.Include))
+			add(logsStartDateProperty, p.Options.Logs.StartDate)
+			add(logsEndDateProperty, p.Options.Logs.EndDate)
+		}
+		if p.Options.ThreadDump != nil {
+			add(threadDumpCountProperty, strconv.FormatUint(uint64(p.Options.ThreadDump.Count), 10))
+			add(threadDumpIntervalProperty, strconv.FormatUint(uint64(p.Options.ThreadDump.Interval), 10))
+		}
+	}
+	add(flunkyVersionProperty, p.FlunkyVersion)
+	props.Properties = append(props.Properties, p.Custom...)
+	return props
+}
@@ -0,0 +1,50 @@
+package actions
+
+import (
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_UploadProperties_toProperties(t *testing.T) {
+	properties := UploadProperties{
+		SourceURL:     "https://acme.jfrog.io/artifactory/",
+		SourceVersion: "7.12.5",
+		BundleID:      "20201201-0001",
+		Options: &flunkyhttp.SupportBundleParameters{
+			Configuration: true,
+			Logs:          &flunkyhttp.SupportBundleParametersLogs{Include: true, StartDate: "2020-11-30"},
+			System:        false,
+			ThreadDump:    &flunkyhttp.SupportBundleParametersThreadDump{Count: 1, Interval: 0},
+		},
+		FlunkyVersion: "v0.1.0",
+		Custom:        []servicesutils.Property{{Key: "team", Value: "core"}},
+	}
+	assert.Equal(t, []servicesutils.Property{
+		{Key: "support.caseNumber", Value: "1234"},
+		{Key: "support.sourceUrl", Value: "https://acme.jfrog.io/artifactory/"},
+		{Key: "support.sourceVersion", Value: "7.12.5"},
+		{Key: "support.bundleId", Value: "20201201-0001"},
+		{Key: "support.options.configuration", Value: "true"},
+		{Key: "support.options.system", Value: "false"},
+		{Key: "support.options.logs", Value: "true"},
+		{Key: "support.options.logs.startDate", Value: "2020-11-30"},
+		{Key: "support.options.threadDump.count", Value: "1"},
+		{Key: "support.options.threadDump.interval", Value: "0"},
+		{Key: "support.flunkyVersion", Value: "v0.1.0"},
+		{Key: "team", Value: "core"},
+	}, properties.toProperties("1234").Properties)
+
+	assert.Equal(t, []servicesutils.Property{{Key: "support.caseNumber", Value: "1234"}},
+		UploadProperties{}.toProperties("1234").Properties)
+}
+
+func Test_ParseCustomProperties(t *testing.T) {
+	properties, err := ParseCustomProperties("a=1;b = x,y ;")
+	assert.NoError(t, err)
+	assert.Equal(t, []servicesutils.Property{{Key: "a", Value: "1"}, {Key: "b", Value: "x,y"}}, properties)
+
+	_, err = ParseCustomProperties("a=")
+	assert.EqualError(t, err, "Invalid property: a=")
+}
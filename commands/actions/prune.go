@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"sort"
+	"time"
+)
+
+type pruneSupportBundlesHTTPClient interface {
+	listSupportBundlesHTTPClient
+	DeleteSupportBundle(ctx context.Context, bundleID string) (int, []byte, error)
+}
+
+// RetentionPolicy defines which Support Bundles are kept when pruning.
+type RetentionPolicy struct {
+	// KeepLast is the number of most recent Support Bundles that are always kept.
+	KeepLast int
+	// OlderThan restricts pruning to Support Bundles older than this duration. Zero means any age.
+	OlderThan time.Duration
+	// FlunkyOnly restricts pruning to Support Bundles created by this plugin.
+	FlunkyOnly bool
+}
+
+// PruneSupportBundles deletes the Support Bundles that are not retained by the policy, and returns them. When dryRun
+// is true, nothing is deleted.
+func PruneSupportBundles(ctx context.Context, client pruneSupportBundlesHTTPClient, policy RetentionPolicy, dryRun bool,
+	now Clock) ([]flunkyhttp.SupportBundleDetails, error) {
+	if policy.KeepLast <= 0 && policy.OlderThan <= 0 {
+		return nil, Categorize(CategoryConfiguration,
+			errors.New("at least one of the keep-last or older-than retention rules is required"))
+	}
+	bundles, err := ListSupportBundles(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	toPrune := selectSupportBundlesToPrune(bundles, policy, now())
+	if dryRun {
+		return toPrune, nil
+	}
+
+	failures := 0
+	for i := range toPrune {
+		err = DeleteSupportBundle(ctx, client, BundleID(toPrune[i].ID))
+		if err != nil {
+			log.Warn(fmt.Sprintf("Could not delete Support Bundle %s: %+v", toPrune[i].ID, err))
+			failures++
+		}
+	}
+	if failures > 0 {
+		return toPrune, fmt.Errorf("failed to delete %d of %d Support Bundles", failures, len(toPrune))
+	}
+	return toPrune, nil
+}
+
+type datedSupportBundle struct {
+	details flunkyhttp.SupportBundleDetails
+	created time.Time
+}
+
+func selectSupportBundlesToPrune(bundles []flunkyhttp.SupportBundleDetails, policy RetentionPolicy,
+	now time.Time) []flunkyhttp.SupportBundleDetails {
+	candidates := make([]datedSupportBundle, 0, len(bundles))
+	for i := range bundles {
+		bundle := bundles[i]
+		if policy.FlunkyOnly && !IsCreatedByFlunky(bundle.Name) {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, bundle.Created)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Keeping Support Bundle %s with unknown creation date %s", bundle.ID, bundle.Created))
+			continue
+		}
+		candidates = append(candidates, datedSupportBundle{details: bundle, created: created})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].created.After(candidates[j].created)
+	})
+
+	toPrune := make([]flunkyhttp.SupportBundleDetails, 0, len(candidates))
+	for i := range candidates {
+		candidate := candidates[i]
+		if i < policy.KeepLast {
+			continue
+		}
+		if policy.OlderThan > 0 && now.Sub(candidate.created) <= policy.OlderThan {
+			continue
+		}
+		if parseBundleState(candidate.details.Status) == BundleStateInProgress {
+			continue
+		}
+		toPrune = append(toPrune, candidate.details)
+	}
+	return toPrune
+}
@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type pruneClientStub struct {
+	listClientStub
+	deleteClientStub
+}
+
+func (s *pruneClientStub) GetURL() string {
+	return "stub"
+}
+
+func newPruneClientStub() *pruneClientStub {
+	return &pruneClientStub{
+		listClientStub: listClientStub{
+			listStatusCode: http.StatusOK,
+			listResponse: `{"count":5,"bundles":[` +
+				`{"id":"1","name":"JFrog Support Case number 1","created":"2020-12-01T10:00:00Z"},` +
+				`{"id":"2","name":"Nightly","created":"2020-12-02T10:00:00Z"},` +
+				`{"id":"3","name":"JFrog Support Case number 3","created":"2020-12-03T10:00:00Z"},` +
+				`{"id":"4","name":"JFrog Support Case number 4","created":"2020-12-04T10:00:00Z"},` +
+				`{"id":"5","name":"JFrog Support Case number 5","created":"not a date"}]}`,
+			details: map[string]string{
+				"1": `{"status":"success"}`,
+				"2": `{"status":"success"}`,
+				"3": `{"status":"success"}`,
+				"4": `{"status":"in progress"}`,
+				"5": `{"status":"success"}`,
+			},
+		},
+		deleteClientStub: deleteClientStub{statusCode: http.StatusOK},
+	}
+}
+
+func Test_PruneSupportBundles(t *testing.T) {
+	now := func() time.Time { return time.Date(2020, 12, 5, 10, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name          string
+		policy        RetentionPolicy
+		dryRun        bool
+		deleteStatus  int
+		expectPruned  []string
+		expectDeleted []string
+		expectErr     string
+	}{
+		{
+			name:          "keep last 2",
+			policy:        RetentionPolicy{KeepLast: 2},
+			expectPruned:  []string{"2", "1"},
+			expectDeleted: []string{"2", "1"},
+		},
+		{
+			name:          "keep last 1 created by flunky",
+			policy:        RetentionPolicy{KeepLast: 1, FlunkyOnly: true},
+			expectPruned:  []string{"3", "1"},
+			expectDeleted: []string{"3", "1"},
+		},
+		{
+			name:          "older than 60 hours",
+			policy:        RetentionPolicy{OlderThan: 60 * time.Hour},
+			expectPruned:  []string{"2", "1"},
+			expectDeleted: []string{"2", "1"},
+		},
+		{
+			name:          "in progress bundles are never pruned",
+			policy:        RetentionPolicy{OlderThan: time.Hour},
+			expectPruned:  []string{"3", "2", "1"},
+			expectDeleted: []string{"3", "2", "1"},
+		},
+		{
+			name:         "dry run",
+			policy:       RetentionPolicy{KeepLast: 2},
+			dryRun:       true,
+			expectPruned: []string{"2", "1"},
+		},
+		{
+			name:          "delete fails",
+			policy:        RetentionPolicy{KeepLast: 3},
+			deleteStatus:  http.StatusForbidden,
+			expectPruned:  []string{"1"},
+			expectDeleted: []string{"1"},
+			expectErr:     "failed to delete 1 of 1 Support Bundles",
+		},
+		{
+			name:      "no retention rule",
+			policy:    RetentionPolicy{FlunkyOnly: true},
+			expectErr: "at least one of the keep-last or older-than retention rules is required",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			stub := newPruneClientStub()
+			if test.deleteStatus != 0 {
+				stub.deleteClientStub.statusCode = test.deleteStatus
+			}
+			pruned, err := PruneSupportBundles(context.Background(), stub, test.policy, test.dryRun, now)
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			var prunedIDs []string
+			for i := range pruned {
+				prunedIDs = append(prunedIDs, pruned[i].ID)
+			}
+			assert.Equal(t, test.expectPruned, prunedIDs)
+			assert.Equal(t, test.expectDeleted, stub.deletedBundleIDs)
+		})
+	}
+}
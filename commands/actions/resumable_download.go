@@ -0,0 +1,203 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transferInterruptedError marks an error after which the download can be attempted again, resuming where it stopped
+// when possible.
+type transferInterruptedError struct {
+	err error
+}
+
+func (e *transferInterruptedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transferInterruptedError) Unwrap() error {
+	return e.err
+}
+
+// resumableDownload writes a Support Bundle archive to a file, resuming with a Range request after an interruption
+// when the server accepts it. The checksums of the archive are computed as it is written.
+type resumableDownload struct {
+	client     downloadSupportBundleHTTPClient
+	file       *os.File
+	bundleID   BundleID
+	written    int64
+	resumable  bool
+	calculator *checksumCalculator
+}
+
+func downloadSupportBundleAndWriteToFile(ctx context.Context, client downloadSupportBundleHTTPClient,
+	tmpZipFile *os.File, retry flunkyhttp.RetryPolicy, bundleID BundleID) (flunkyhttp.Checksums, error) {
+	d := &resumableDownload{client: client, file: tmpZipFile, bundleID: bundleID, calculator: newChecksumCalculator()}
+	for attempt := 1; ; attempt++ {
+		err := d.downloadRemaining(ctx)
+		if err == nil {
+			return d.calculator.checksums(), nil
+		}
+		var interrupted *transferInterruptedError
+		if !errors.As(err, &interrupted) {
+			return flunkyhttp.Checksums{}, err
+		}
+		if attempt >= retry.Attempts() {
+			return flunkyhttp.Checksums{}, interrupted.err
+		}
+		backoff := retry.Backoff(attempt, 0)
+		log.Warn(fmt.Sprintf("Download of Support Bundle %s interrupted after %d bytes (attempt %d of %d), "+
+			"retrying in %s: %+v", bundleID, d.written, attempt, retry.Attempts(), backoff.Round(time.Millisecond),
+			interrupted.err))
+		if err = sleep(ctx, backoff); err != nil {
+			return flunkyhttp.Checksums{}, err
+		}
+	}
+}
+
+func (d *resumableDownload) downloadRemaining(ctx context.Context) error {
+	if !d.resumable && d.written > 0 {
+		if err := d.restart(); err != nil {
+			return err
+		}
+	}
+	// Failed requests are already retried by the client.
+	resp, err := d.client.DownloadSupportBundle(ctx, string(d.bundleID), d.written)
+	if err != nil {
+		return err
+	}
+	defer handleClose(resp.Body)
+	log.Debug(fmt.Sprintf("Got %d", resp.StatusCode))
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if d.written > 0 {
+			log.Debug("Server sent the whole Support Bundle, restarting the download")
+			if err = d.restart(); err != nil {
+				return err
+			}
+		}
+		d.resumable = resp.Header.Get(flunkyhttp.HTTPAcceptRanges) == "bytes"
+	case http.StatusPartialContent:
+		start, ok := parseContentRangeStart(resp.Header.Get(flunkyhttp.HTTPContentRange))
+		if !ok || start != d.written {
+			d.resumable = false
+			return &transferInterruptedError{err: fmt.Errorf("unexpected content range %q when resuming at %d",
+				resp.Header.Get(flunkyhttp.HTTPContentRange), d.written)}
+		}
+		log.Debug(fmt.Sprintf("Resuming download at %d", start))
+	case http.StatusRequestedRangeNotSatisfiable:
+		return d.resumeRefused(resp)
+	default:
+		return newHTTPErrorFromResponse(resp)
+	}
+
+	body := &readErrorRecorder{r: resp.Body}
+	n, err := io.Copy(io.MultiWriter(d.file, d.calculator), body)
+	d.written += n
+	if err != nil {
+		if body.err != nil {
+			return &transferInterruptedError{err: err}
+		}
+		return err
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return &transferInterruptedError{err: fmt.Errorf("incomplete download: received %d of %d bytes", n,
+			resp.ContentLength)}
+	}
+	return nil
+}
+
+// resumeRefused handles a server refusing the range of a resumed download. When it tells that the whole archive has
+// been received already, which happens when it announced a wrong Content-Length, the download is complete. Otherwise
+// the download is restarted.
+func (d *resumableDownload) resumeRefused(resp *http.Response) error {
+	if d.written == 0 {
+		return newHTTPErrorFromResponse(resp)
+	}
+	if size, ok := parseContentRangeSize(resp.Header.Get(flunkyhttp.HTTPContentRange)); ok && size == d.written {
+		log.Debug(fmt.Sprintf("All the %d bytes of the Support Bundle have already been received", size))
+		return nil
+	}
+	d.resumable = false
+	return &transferInterruptedError{err: fmt.Errorf("resuming at %d refused: %w", d.written,
+		newHTTPErrorFromResponse(resp))}
+}
+
+func (d *resumableDownload) restart() error {
+	d.written = 0
+	d.calculator = newChecksumCalculator()
+	if err := d.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := d.file.Seek(0, io.SeekStart)
+	return err
+}
+
+// parseContentRangeStart parses the first byte position of a Content-Range header such as "bytes 200-1000/1001".
+func parseContentRangeStart(contentRange string) (int64, bool) {
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, false
+	}
+	byteRange := strings.TrimPrefix(contentRange, "bytes ")
+	dash := strings.Index(byteRange, "-")
+	if dash < 0 {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(byteRange[:dash], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
+// parseContentRangeSize parses the complete length of a Content-Range header such as "bytes */1001".
+func parseContentRangeSize(contentRange string) (int64, bool) {
+	slash := strings.LastIndex(contentRange, "/")
+	if !strings.HasPrefix(contentRange, "bytes ") || slash < 0 {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(contentRange[slash+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return size, true
+}
+
+// readErrorRecorder records the errors occurring while reading, so that they can be told apart from write errors,
+// and whether the end of the content was reached.
+type readErrorRecorder struct {
+	r    io.Reader
+	err  error
+	done bool
+}
+
+func (r *readErrorRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if errors.Is(err, io.EOF) {
+		r.done = true
+	} else if err != nil {
+		r.err = err
+	}
+	return n, err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,264 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var helloworldChecksums = flunkyhttp.Checksums{
+	SHA256: "936a185caaa266bb9cbe981e9e05cb78cd732b0b3280eb944412bb6f8f8f07af",
+	SHA1:   "6adfb183a4a2c94a2f92dab5ade762a47889a5a1",
+	MD5:    "fc5e038d38a57032085441e7fe7010b0",
+}
+
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, f.err
+	}
+	return n, err
+}
+
+type downloadResponse struct {
+	statusCode    int
+	headers       map[string]string
+	contentLength int64
+	body          string
+	bodyErr       error
+	err           error
+}
+
+type resumableClientStub struct {
+	responses       []downloadResponse
+	receivedOffsets []int64
+}
+
+func (s *resumableClientStub) GetURL() string {
+	return "stub"
+}
+
+func (s *resumableClientStub) GetSupportBundleStatus(context.Context, string) (int, []byte, error) {
+	return http.StatusOK, []byte(`{"status":"success"}`), nil
+}
+
+func (s *resumableClientStub) DownloadSupportBundle(_ context.Context, _ string, offset int64) (*http.Response, error) {
+	s.receivedOffsets = append(s.receivedOffsets, offset)
+	r := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	var body io.Reader = strings.NewReader(r.body)
+	if r.bodyErr != nil {
+		body = &failingReader{r: body, err: r.bodyErr}
+	}
+	header := http.Header{}
+	for k, v := range r.headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode:    r.statusCode,
+		Header:        header,
+		ContentLength: r.contentLength,
+		Body:          ioutil.NopCloser(body),
+	}, nil
+}
+
+func Test_downloadSupportBundleAndWriteToFile(t *testing.T) {
+	acceptRanges := map[string]string{"Accept-Ranges": "bytes"}
+	reset := errors.New("connection reset by peer")
+	tests := []struct {
+		name          string
+		responses     []downloadResponse
+		expectContent string
+		expectOffsets []int64
+		expectErr     string
+	}{
+		{
+			name: "single download",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, contentLength: 10, body: "helloworld"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0},
+		},
+		{
+			name: "resumed after connection reset",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, body: "hello", bodyErr: reset},
+				{statusCode: http.StatusPartialContent, headers: map[string]string{"Content-Range": "bytes 5-9/10"},
+					contentLength: 5, body: "world"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 5},
+		},
+		{
+			name: "resumed after truncated body",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, body: "hel"},
+				{statusCode: http.StatusPartialContent, headers: map[string]string{"Content-Range": "bytes 3-9/10"},
+					contentLength: 7, body: "loworld"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 3},
+		},
+		{
+			name: "restarted when ranges are not accepted",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, contentLength: 10, body: "hello", bodyErr: reset},
+				{statusCode: http.StatusOK, contentLength: 10, body: "helloworld"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 0},
+		},
+		{
+			name: "restarted when server ignores the range",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, body: "hello", bodyErr: reset},
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, body: "helloworld"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 5},
+		},
+		{
+			name: "restarted on unexpected content range",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, body: "hello", bodyErr: reset},
+				{statusCode: http.StatusPartialContent, headers: map[string]string{"Content-Range": "bytes 0-9/10"},
+					contentLength: 10, body: "helloworld"},
+				{statusCode: http.StatusOK, contentLength: 10, body: "helloworld"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 5, 0},
+		},
+		{
+			name: "complete despite a wrong content length",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 20, body: "helloworld"},
+				{statusCode: http.StatusRequestedRangeNotSatisfiable,
+					headers: map[string]string{"Content-Range": "bytes */10"}},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 10},
+		},
+		{
+			name: "restarted when resuming is refused",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, body: "hello", bodyErr: reset},
+				{statusCode: http.StatusRequestedRangeNotSatisfiable,
+					headers: map[string]string{"Content-Range": "bytes */10"}},
+				{statusCode: http.StatusOK, contentLength: 10, body: "helloworld"},
+			},
+			expectContent: "helloworld",
+			expectOffsets: []int64{0, 5, 0},
+		},
+		{
+			name: "gives up after max attempts",
+			responses: []downloadResponse{
+				{statusCode: http.StatusOK, headers: acceptRanges, contentLength: 10, bodyErr: reset},
+			},
+			expectOffsets: []int64{0, 0, 0, 0, 0},
+			expectErr:     "connection reset by peer",
+		},
+		{
+			name: "does not retry failed requests, retried by the client",
+			responses: []downloadResponse{
+				{err: reset},
+			},
+			expectOffsets: []int64{0},
+			expectErr:     "connection reset by peer",
+		},
+		{
+			name: "does not retry http errors",
+			responses: []downloadResponse{
+				{statusCode: http.StatusNotFound},
+			},
+			expectOffsets: []int64{0},
+			expectErr:     "http request failed with: 404 Not Found",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			file, err := ioutil.TempFile("", "sb-*.zip")
+			require.NoError(t, err)
+			defer func() { _ = os.Remove(file.Name()) }()
+			defer func() { _ = file.Close() }()
+
+			stub := &resumableClientStub{responses: test.responses}
+			checksums, err := downloadSupportBundleAndWriteToFile(context.Background(), stub, file,
+				flunkyhttp.RetryPolicy{MaxAttempts: 5, Interval: time.Millisecond}, "bundleID")
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				content, err := ioutil.ReadFile(file.Name())
+				require.NoError(t, err)
+				assert.Equal(t, test.expectContent, string(content))
+				assert.Equal(t, helloworldChecksums, checksums)
+			}
+			assert.Equal(t, test.expectOffsets, stub.receivedOffsets)
+		})
+	}
+}
+
+func Test_downloadSupportBundleAndWriteToFile_Cancelled(t *testing.T) {
+	file, err := ioutil.TempFile("", "sb-*.zip")
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(file.Name()) }()
+	defer func() { _ = file.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stub := &resumableClientStub{responses: []downloadResponse{{statusCode: http.StatusOK, contentLength: 10,
+		bodyErr: errors.New("connection reset by peer")}}}
+	_, err = downloadSupportBundleAndWriteToFile(ctx, stub, file,
+		flunkyhttp.RetryPolicy{MaxAttempts: 5, Interval: time.Hour}, "bundleID")
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, []int64{0}, stub.receivedOffsets)
+}
+
+func Test_parseContentRangeStart(t *testing.T) {
+	start, ok := parseContentRangeStart("bytes 200-1000/1001")
+	assert.True(t, ok)
+	assert.Equal(t, int64(200), start)
+
+	_, ok = parseContentRangeStart("bytes */1001")
+	assert.False(t, ok)
+	_, ok = parseContentRangeStart("")
+	assert.False(t, ok)
+}
+
+func Test_parseContentRangeSize(t *testing.T) {
+	size, ok := parseContentRangeSize("bytes */1001")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1001), size)
+
+	size, ok = parseContentRangeSize("bytes 200-1000/1001")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1001), size)
+
+	_, ok = parseContentRangeSize("bytes 200-1000/*")
+	assert.False(t, ok)
+	_, ok = parseContentRangeSize("")
+	assert.False(t, ok)
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"net/http"
+	"strings"
+)
+
+// BundleState is a state in the lifecycle of a Support Bundle. A Support Bundle starts in progress and ends either
+// successfully or failed.
+type BundleState string
+
+const (
+	// BundleStateInProgress means the Support Bundle is being generated.
+	BundleStateInProgress BundleState = "in progress"
+	// BundleStateSuccess means the Support Bundle has been generated and can be downloaded.
+	BundleStateSuccess BundleState = "success"
+	// BundleStateFailed means the generation of the Support Bundle failed.
+	BundleStateFailed BundleState = "failed"
+	// BundleStateUnknown means the status reported by Artifactory is not supported.
+	BundleStateUnknown BundleState = "unknown"
+)
+
+func parseBundleState(status string) BundleState {
+	switch state := BundleState(strings.ToLower(strings.TrimSpace(status))); state {
+	case BundleStateInProgress, BundleStateSuccess, BundleStateFailed:
+		return state
+	default:
+		return BundleStateUnknown
+	}
+}
+
+// SupportBundleStatus is the status of the creation process of a Support Bundle.
+type SupportBundleStatus struct {
+	State BundleState
+	// Reported is the status as reported by Artifactory.
+	Reported string
+	// Message is the failure message reported by Artifactory, if any.
+	Message string
+}
+
+type supportBundleStatusHTTPClient interface {
+	GetURL() string
+	GetSupportBundleStatus(ctx context.Context, bundleID string) (int, []byte, error)
+}
+
+// GetSupportBundleStatus gets the status of the creation process of a Support Bundle.
+func GetSupportBundleStatus(ctx context.Context, client supportBundleStatusHTTPClient, bundleID BundleID) (
+	SupportBundleStatus, error) {
+	log.Debug(fmt.Sprintf("Attempting to get status for support bundle %s", bundleID))
+	statusCode, body, err := client.GetSupportBundleStatus(ctx, string(bundleID))
+	if err != nil {
+		return SupportBundleStatus{}, err
+	}
+
+	log.Debug(fmt.Sprintf("Got HTTP response status: %d", statusCode))
+	if statusCode != http.StatusOK {
+		return SupportBundleStatus{}, newHTTPError(statusCode, body)
+	}
+
+	parsedBody, err := flunkyhttp.ParseJSON(body)
+	if err != nil {
+		return SupportBundleStatus{}, err
+	}
+
+	sbStatus, err := parsedBody.GetString("status")
+	if err != nil {
+		return SupportBundleStatus{}, err
+	}
+	return SupportBundleStatus{
+		State:    parseBundleState(sbStatus),
+		Reported: sbStatus,
+		Message:  getFailureMessage(parsedBody),
+	}, nil
+}
+
+func getFailureMessage(parsedBody flunkyhttp.JSONObject) string {
+	for _, property := range []string{"message", "error"} {
+		if message, err := parsedBody.GetString(property); err == nil {
+			return message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func Test_GetSupportBundleStatus(t *testing.T) {
+	tests := []struct {
+		name                 string
+		clientStub           *checkStatusClientStub
+		expectedStatus       SupportBundleStatus
+		expectedErrorMessage string
+	}{
+		{
+			name: "success",
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{fmt.Sprintf(body, "success")},
+			},
+			expectedStatus: SupportBundleStatus{State: BundleStateSuccess, Reported: "success"},
+		},
+		{
+			name: "in progress",
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{fmt.Sprintf(body, "in progress")},
+			},
+			expectedStatus: SupportBundleStatus{State: BundleStateInProgress, Reported: "in progress"},
+		},
+		{
+			name: "failed with message",
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{`{"status":"Failed","message":"disk full"}`},
+			},
+			expectedStatus: SupportBundleStatus{State: BundleStateFailed, Reported: "Failed", Message: "disk full"},
+		},
+		{
+			name: "failed with error",
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{`{"status":"failed","error":"disk full"}`},
+			},
+			expectedStatus: SupportBundleStatus{State: BundleStateFailed, Reported: "failed", Message: "disk full"},
+		},
+		{
+			name: "unknown",
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusOK,
+				payloads:   []string{fmt.Sprintf(body, "archived")},
+			},
+			expectedStatus: SupportBundleStatus{State: BundleStateUnknown, Reported: "archived"},
+		},
+		{
+			name: "support bundle not found",
+			clientStub: &checkStatusClientStub{
+				statusCode: http.StatusNotFound,
+				payloads:   []string{`{}`},
+			},
+			expectedErrorMessage: "http request failed with: 404 Not Found",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			status, err := GetSupportBundleStatus(context.Background(), test.clientStub, "bundleID")
+			if test.expectedErrorMessage != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedErrorMessage)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedStatus, status)
+			}
+			assert.Equal(t, "bundleID", test.clientStub.receivedBundleID)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"io"
+	"net/http"
+)
+
+type streamUploadHTTPClient interface {
+	UploadSupportBundleStream(ctx context.Context, content io.Reader, size int64, repoKey string,
+		supportCaseDirectory string, filename string, properties *servicesutils.Properties) (status int,
+		responseBytes []byte, err error)
+	DeleteUploadedSupportBundle(ctx context.Context, repoKey string, supportCaseDirectory string,
+		filename string) (status int, responseBytes []byte, err error)
+	caseFolderHTTPClient
+	GetURL() string
+}
+
+// StreamResult gives details on a Support Bundle streamed from the source to the target service.
+type StreamResult struct {
+	UploadURL string
+	Size      int64
+	Checksums flunkyhttp.Checksums
+}
+
+// StreamSupportBundle pipes a Support Bundle from the source service into an upload to the target service, without
+// writing it to a local file. The checksums of the archive are computed while it goes through, and compared with the
+// ones reported by Artifactory once uploaded. The transfer timeout limits both the download and the upload.
+// Unlike DownloadSupportBundle, an interrupted transfer is not resumed as the upload cannot be rewound.
+// An uploaded archive which turns out to be incomplete or corrupt is deleted from the target.
+// Errors are categorized by the side which failed, the same way as with DownloadSupportBundle and UploadSupportBundle.
+func StreamSupportBundle(ctx context.Context, source downloadSupportBundleHTTPClient,
+	targetClient streamUploadHTTPClient, timeouts DownloadTimeouts, retry flunkyhttp.RetryPolicy, bundleID BundleID,
+	target UploadTarget, now Clock) (*StreamResult, error) {
+	result := &StreamResult{}
+	if err := target.Validate(); err != nil {
+		return result, Categorize(CategoryConfiguration, err)
+	}
+	filename, err := getSupportBundleFilename(ctx, targetClient, target, now)
+	if err != nil {
+		return result, Categorize(CategoryUploadRejected, err)
+	}
+	result.UploadURL = getUploadURL(targetClient, target, filename)
+	log.Debug(fmt.Sprintf("Streaming Support Bundle %s from %s to %s", bundleID, source.GetURL(), result.UploadURL))
+
+	err = waitUntilSupportBundleIsReady(ctx, source, retry, timeouts.Ready, bundleID)
+	if err != nil {
+		return result, Categorize(CategoryDownloadTransfer, err)
+	}
+	err = RunWithTimeout(ctx, transferPhase, timeouts.Transfer, func(ctx context.Context) error {
+		return streamReadySupportBundle(ctx, source, targetClient, bundleID, target, filename, result)
+	})
+	if err != nil {
+		return result, err
+	}
+	log.Debug(fmt.Sprintf("Streamed %d bytes, SHA-256: %s", result.Size, result.Checksums.SHA256))
+	return result, nil
+}
+
+// streamReadySupportBundle pipes the archive of a ready Support Bundle into an upload, filling in the size and the
+// checksums of the result.
+func streamReadySupportBundle(ctx context.Context, source downloadSupportBundleHTTPClient,
+	targetClient streamUploadHTTPClient, bundleID BundleID, target UploadTarget, filename string,
+	result *StreamResult) error {
+	resp, err := source.DownloadSupportBundle(ctx, string(bundleID), 0)
+	if err != nil {
+		return Categorize(CategoryDownloadTransfer, err)
+	}
+	defer handleClose(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return Categorize(CategoryDownloadTransfer, newHTTPErrorFromResponse(resp))
+	}
+
+	body := &readErrorRecorder{r: resp.Body}
+	calculator := newChecksumCalculator()
+	statusCode, respBytes, err := targetClient.UploadSupportBundleStream(ctx, io.TeeReader(body, calculator),
+		resp.ContentLength, target.RepoKey, string(target.CaseNumber), filename,
+		target.Properties.toProperties(target.CaseNumber))
+	result.Size = calculator.size
+	result.Checksums = calculator.checksums()
+	uploaded := err == nil && statusCode == http.StatusCreated
+	if downloadErr := checkStreamedDownload(body, resp.ContentLength, result.Size); downloadErr != nil {
+		if uploaded {
+			deleteCorruptUpload(ctx, targetClient, target, filename)
+		}
+		return Categorize(CategoryDownloadTransfer, downloadErr)
+	}
+	if err != nil {
+		return Categorize(CategoryUploadRejected, err)
+	}
+
+	log.Debug(fmt.Sprintf("Got HTTP response status: %d, body: %s", statusCode, respBytes))
+	if !uploaded {
+		return Categorize(CategoryUploadRejected, newHTTPError(statusCode, respBytes))
+	}
+	if err = verifyUploadResponse(result.Checksums, respBytes); err != nil {
+		deleteCorruptUpload(ctx, targetClient, target, filename)
+		return Categorize(CategoryUploadRejected, err)
+	}
+	return nil
+}
+
+// checkStreamedDownload gives the error which interrupted the download of a streamed archive, if any, or reports the
+// download as incomplete when its end was reached with fewer bytes than announced. A download which stopped because
+// the upload failed is not reported.
+func checkStreamedDownload(body *readErrorRecorder, contentLength int64, size int64) error {
+	if body.err != nil {
+		return body.err
+	}
+	if body.done && contentLength >= 0 && size != contentLength {
+		return fmt.Errorf("incomplete download: received %d of %d bytes", size, contentLength)
+	}
+	return nil
+}
+
+// deleteCorruptUpload deletes an uploaded archive which failed verification, so that it is not mistaken for a valid
+// Support Bundle. A failure is only logged, as the verification error is the one to report.
+func deleteCorruptUpload(ctx context.Context, client streamUploadHTTPClient, target UploadTarget, filename string) {
+	url := getUploadURL(client, target, filename)
+	statusCode, respBytes, err := client.DeleteUploadedSupportBundle(ctx, target.RepoKey, string(target.CaseNumber),
+		filename)
+	if err == nil && statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+		err = newHTTPError(statusCode, respBytes)
+	}
+	if err != nil {
+		log.Warn(fmt.Sprintf("Failed to delete the corrupt Support Bundle %s, please delete it manually: %v", url, err))
+		return
+	}
+	log.Info(fmt.Sprintf("Deleted the corrupt Support Bundle %s", url))
+}
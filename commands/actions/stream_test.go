@@ -0,0 +1,193 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type streamUploadClientStub struct {
+	statusCode         int
+	response           string
+	err                error
+	readLimit          int64
+	deleteStatusCode   int
+	deleted            string
+	receivedContent    string
+	receivedSize       int64
+	receivedRepo       string
+	receivedCase       string
+	receivedFile       string
+	receivedProperties *servicesutils.Properties
+}
+
+func (s *streamUploadClientStub) UploadSupportBundleStream(_ context.Context, content io.Reader, size int64,
+	repoKey string, supportCaseDirectory string, filename string, properties *servicesutils.Properties) (int, []byte,
+	error) {
+	s.receivedProperties = properties
+	s.receivedSize = size
+	s.receivedRepo = repoKey
+	s.receivedCase = supportCaseDirectory
+	s.receivedFile = filename
+	if s.readLimit > 0 {
+		content = io.LimitReader(content, s.readLimit)
+	}
+	bytes, err := ioutil.ReadAll(content)
+	s.receivedContent = string(bytes)
+	if err != nil {
+		return 0, nil, err
+	}
+	return s.statusCode, []byte(s.response), s.err
+}
+
+func (s *streamUploadClientStub) DeleteUploadedSupportBundle(_ context.Context, repoKey string,
+	supportCaseDirectory string, filename string) (int, []byte, error) {
+	s.deleted = fmt.Sprintf("%s/%s/%s", repoKey, supportCaseDirectory, filename)
+	return s.deleteStatusCode, nil, nil
+}
+
+func (s *streamUploadClientStub) GetSupportCaseFolder(context.Context, string, string) (int, []byte, error) {
+	return http.StatusNotFound, nil, nil
+}
+
+func (s *streamUploadClientStub) GetURL() string {
+	return "http://foo.bar/"
+}
+
+func Test_StreamSupportBundle(t *testing.T) {
+	helloWorldChecksums := flunkyhttp.Checksums{
+		SHA256: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		SHA1:   "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed",
+		MD5:    "5eb63bbbe01eeed093cb22bb8f5acdc3",
+	}
+	tests := []struct {
+		name           string
+		download       downloadResponse
+		upload         *streamUploadClientStub
+		expectResult   StreamResult
+		expectUploaded string
+		expectDeleted  bool
+		expectErr      string
+		expectCategory ErrorCategory
+	}{
+		{
+			name:     "success",
+			download: downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello world"},
+			upload: &streamUploadClientStub{statusCode: http.StatusCreated, response: fmt.Sprintf(uploadResponse,
+				helloWorldChecksums.SHA1, helloWorldChecksums.MD5, helloWorldChecksums.SHA256)},
+			expectResult:   StreamResult{Size: 11, Checksums: helloWorldChecksums},
+			expectUploaded: "hello world",
+		},
+		{
+			name:           "download failure",
+			download:       downloadResponse{statusCode: http.StatusNotFound},
+			upload:         &streamUploadClientStub{statusCode: http.StatusCreated},
+			expectResult:   StreamResult{},
+			expectErr:      "http request failed with: 404 Not Found",
+			expectCategory: CategoryDownloadTransfer,
+		},
+		{
+			name: "download interrupted",
+			download: downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello",
+				bodyErr: errors.New("connection reset by peer")},
+			upload:         &streamUploadClientStub{statusCode: http.StatusCreated},
+			expectUploaded: "hello",
+			expectErr:      "connection reset by peer",
+			expectCategory: CategoryDownloadTransfer,
+		},
+		{
+			name:           "download truncated",
+			download:       downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello"},
+			upload:         &streamUploadClientStub{statusCode: http.StatusCreated, deleteStatusCode: http.StatusNoContent},
+			expectUploaded: "hello",
+			expectDeleted:  true,
+			expectErr:      "incomplete download: received 5 of 11 bytes",
+			expectCategory: CategoryDownloadTransfer,
+		},
+		{
+			name:     "checksum mismatch",
+			download: downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello world"},
+			upload: &streamUploadClientStub{statusCode: http.StatusCreated, response: fmt.Sprintf(uploadResponse,
+				helloWorldChecksums.SHA1, helloWorldChecksums.MD5, "0000"), deleteStatusCode: http.StatusNoContent},
+			expectUploaded: "hello world",
+			expectDeleted:  true,
+			expectErr: "SHA-256 checksum mismatch: expected " +
+				"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9, Artifactory reported 0000",
+			expectCategory: CategoryUploadRejected,
+		},
+		{
+			name:     "checksum mismatch, deletion failure",
+			download: downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello world"},
+			upload: &streamUploadClientStub{statusCode: http.StatusCreated, response: fmt.Sprintf(uploadResponse,
+				helloWorldChecksums.SHA1, helloWorldChecksums.MD5, "0000"), deleteStatusCode: http.StatusForbidden},
+			expectUploaded: "hello world",
+			expectDeleted:  true,
+			expectErr: "SHA-256 checksum mismatch: expected " +
+				"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9, Artifactory reported 0000",
+			expectCategory: CategoryUploadRejected,
+		},
+		{
+			name:           "upload failure",
+			download:       downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello world"},
+			upload:         &streamUploadClientStub{statusCode: http.StatusForbidden},
+			expectUploaded: "hello world",
+			expectErr:      "http request failed with: 403 Forbidden",
+			expectCategory: CategoryAuthentication,
+		},
+		{
+			name:     "upload interrupted",
+			download: downloadResponse{statusCode: http.StatusOK, contentLength: 11, body: "hello world"},
+			upload: &streamUploadClientStub{readLimit: 5,
+				err: errors.New("write: connection reset by peer")},
+			expectUploaded: "hello",
+			expectErr:      "write: connection reset by peer",
+			expectCategory: CategoryUploadRejected,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			source := &resumableClientStub{responses: []downloadResponse{test.download}}
+			result, err := StreamSupportBundle(context.Background(), source, test.upload,
+				DownloadTimeouts{Ready: time.Second}, flunkyhttp.RetryPolicy{Interval: time.Millisecond}, "bundleID",
+				UploadTarget{RepoKey: "logs", CaseNumber: "1234", Hostname: "host-1",
+					Properties: UploadProperties{BundleID: "bundleID"}},
+				func() time.Time {
+					return time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+				})
+			require.NotNil(t, result)
+			assert.Equal(t, "http://foo.bar/logs/1234/SB-20201201-100000Z-host-1-1.zip", result.UploadURL)
+			if test.expectErr != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectErr)
+				assert.Equal(t, test.expectCategory, CategoryOf(err))
+			} else {
+				require.NoError(t, err)
+				test.expectResult.UploadURL = result.UploadURL
+				assert.Equal(t, test.expectResult, *result)
+				assert.Equal(t, "logs", test.upload.receivedRepo)
+				assert.Equal(t, "1234", test.upload.receivedCase)
+				assert.Equal(t, "SB-20201201-100000Z-host-1-1.zip", test.upload.receivedFile)
+				assert.Equal(t, test.download.contentLength, test.upload.receivedSize)
+				assert.Equal(t, "support.caseNumber=1234;support.bundleId=bundleID",
+					test.upload.receivedProperties.ToEncodedString())
+			}
+			assert.Equal(t, test.expectUploaded, test.upload.receivedContent)
+			if test.expectDeleted {
+				assert.Equal(t, "logs/1234/SB-20201201-100000Z-host-1-1.zip", test.upload.deleted)
+			} else {
+				assert.Empty(t, test.upload.deleted)
+			}
+		})
+	}
+}
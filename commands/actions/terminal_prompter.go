@@ -1,6 +1,11 @@
 package actions
 
-import "github.com/AlecAivazis/survey/v2"
+import (
+	"fmt"
+	"github.com/AlecAivazis/survey/v2"
+	"strconv"
+	"time"
+)
 
 // TerminalPrompter is a Prompter that gets answers through questions to the user.
 type TerminalPrompter struct {
@@ -27,6 +32,54 @@ func (t *TerminalPrompter) AskThreadDump() (bool, error) {
 	return t.askBoolean("Include thread dump?")
 }
 
+// AskLogsDateRange tells the first and last days of the logs to include.
+func (t *TerminalPrompter) AskLogsDateRange(defaultStartDate string, defaultEndDate string) (string, string, error) {
+	startDate, err := t.askInput("Include logs from (YYYY-MM-DD):", defaultStartDate, validateDate)
+	if err != nil {
+		return "", "", err
+	}
+	endDate, err := t.askInput("Include logs until (YYYY-MM-DD):", defaultEndDate, validateDate)
+	return startDate, endDate, err
+}
+
+// AskThreadDumpCount tells how many thread dumps must be included.
+func (t *TerminalPrompter) AskThreadDumpCount(defaultCount uint) (uint, error) {
+	return t.askNumber("Number of thread dumps:", defaultCount)
+}
+
+// AskThreadDumpInterval tells the interval between thread dumps, in milliseconds.
+func (t *TerminalPrompter) AskThreadDumpInterval(defaultInterval uint) (uint, error) {
+	return t.askNumber("Interval between thread dumps (ms):", defaultInterval)
+}
+
+// AskDescription tells the description of the Support Bundle.
+func (t *TerminalPrompter) AskDescription(defaultDescription string) (string, error) {
+	return t.askInput("Description:", defaultDescription, nil)
+}
+
+// ConfirmOptions shows the payload sent to Artifactory to create the Support Bundle and asks for a confirmation.
+func (t *TerminalPrompter) ConfirmOptions(payload string) (bool, error) {
+	return t.askBoolean(fmt.Sprintf("%s\nCreate a Support Bundle with these options?", payload))
+}
+
+// AskCaseNumber tells the JFrog Support case number.
+func (t *TerminalPrompter) AskCaseNumber() (CaseNumber, error) {
+	caseNumber, err := t.askInput("JFrog Support case number:", "", func(answer interface{}) error {
+		return validatePathSegment("case number", fmt.Sprint(answer))
+	})
+	return CaseNumber(caseNumber), err
+}
+
+// AskSourceServerID tells which of the given services of JFrog CLI configuration to create the Support Bundle on.
+func (t *TerminalPrompter) AskSourceServerID(serverIDs []string, defaultServerID string) (string, error) {
+	return t.askSelect("Source Artifactory service:", serverIDs, defaultServerID)
+}
+
+// AskTargetServerID tells which of the given services to upload the Support Bundle to.
+func (t *TerminalPrompter) AskTargetServerID(serverIDs []string, defaultServerID string) (string, error) {
+	return t.askSelect("Upload the Support Bundle to:", serverIDs, defaultServerID)
+}
+
 func (t *TerminalPrompter) askBoolean(question string) (bool, error) {
 	answer := false
 	confirm := &survey.Confirm{
@@ -36,3 +89,53 @@ func (t *TerminalPrompter) askBoolean(question string) (bool, error) {
 	err := survey.AskOne(confirm, &answer, t.opts...)
 	return answer, err
 }
+
+func (t *TerminalPrompter) askInput(question string, defaultAnswer string, validator survey.Validator) (string, error) {
+	answer := ""
+	input := &survey.Input{
+		Message: question,
+		Default: defaultAnswer,
+	}
+	opts := t.opts
+	if validator != nil {
+		opts = append(append([]survey.AskOpt(nil), t.opts...), survey.WithValidator(validator))
+	}
+	err := survey.AskOne(input, &answer, opts...)
+	return answer, err
+}
+
+func (t *TerminalPrompter) askNumber(question string, defaultAnswer uint) (uint, error) {
+	answer, err := t.askInput(question, strconv.FormatUint(uint64(defaultAnswer), 10), validateNumber)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseUint(answer, 10, 32)
+	return uint(number), err
+}
+
+func (t *TerminalPrompter) askSelect(question string, options []string, defaultOption string) (string, error) {
+	answer := ""
+	selectOption := &survey.Select{
+		Message: question,
+		Options: options,
+	}
+	if defaultOption != "" {
+		selectOption.Default = defaultOption
+	}
+	err := survey.AskOne(selectOption, &answer, t.opts...)
+	return answer, err
+}
+
+func validateDate(answer interface{}) error {
+	if _, err := time.Parse(optionsDateLayout, fmt.Sprint(answer)); err != nil {
+		return fmt.Errorf("%q is not a date in the YYYY-MM-DD format", answer)
+	}
+	return nil
+}
+
+func validateNumber(answer interface{}) error {
+	if _, err := strconv.ParseUint(fmt.Sprint(answer), 10, 32); err != nil {
+		return fmt.Errorf("%q is not a positive number", answer)
+	}
+	return nil
+}
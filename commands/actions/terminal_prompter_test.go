@@ -10,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 var cursorPositionANSISequence = []byte{0x1B, '[', '0', ';', '0', 'R'}
@@ -95,3 +96,66 @@ func test(t *testing.T, ask func(TerminalPrompter) (bool, error)) {
 		}
 	}
 }
+
+// newTerminalPrompterStub gives a TerminalPrompter reading the given input. As each question reads through its own
+// buffer, the input is given one byte at a time so that a question does not consume the answers to the next ones.
+func newTerminalPrompterStub(input string) TerminalPrompter {
+	readerStub := &fileReaderStub{iotest.OneByteReader(strings.NewReader(input))}
+	return TerminalPrompter{opts: []survey.AskOpt{survey.WithStdio(readerStub, &fileWriterStub{}, os.Stderr)}}
+}
+
+func TestTerminalPrompter_AskThreadDumpCount(t *testing.T) {
+	tests := map[string]uint{
+		"5\n": 5,
+		"\n":  1,
+	}
+	for input, expect := range tests {
+		testInput := input
+		testExpect := expect
+		t.Run(testInput, func(t *testing.T) {
+			prompter := newTerminalPrompterStub(testInput)
+			count, err := prompter.AskThreadDumpCount(1)
+			require.NoError(t, err)
+			assert.Equal(t, testExpect, count)
+		})
+	}
+}
+
+func TestTerminalPrompter_AskLogsDateRange(t *testing.T) {
+	prompter := newTerminalPrompterStub("2020-12-01\n\n")
+	startDate, endDate, err := prompter.AskLogsDateRange("2020-12-02", "2020-12-03")
+	require.NoError(t, err)
+	assert.Equal(t, "2020-12-01", startDate)
+	assert.Equal(t, "2020-12-03", endDate)
+}
+
+func TestTerminalPrompter_AskDescription(t *testing.T) {
+	prompter := newTerminalPrompterStub("Slow startup\n")
+	description, err := prompter.AskDescription("Generated on 2020-12-03T22:10:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, "Slow startup", description)
+}
+
+func TestTerminalPrompter_AskCaseNumber(t *testing.T) {
+	prompter := newTerminalPrompterStub("1234\n")
+	caseNumber, err := prompter.AskCaseNumber()
+	require.NoError(t, err)
+	assert.Equal(t, CaseNumber("1234"), caseNumber)
+}
+
+func TestTerminalPrompter_AskSourceServerID(t *testing.T) {
+	prompter := newTerminalPrompterStub("\n")
+	serverID, err := prompter.AskSourceServerID([]string{"a", "b"}, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", serverID)
+}
+
+func Test_validateDate(t *testing.T) {
+	assert.NoError(t, validateDate("2020-12-01"))
+	assert.EqualError(t, validateDate("12/01/2020"), `"12/01/2020" is not a date in the YYYY-MM-DD format`)
+}
+
+func Test_validateNumber(t *testing.T) {
+	assert.NoError(t, validateNumber("5"))
+	assert.EqualError(t, validateNumber("-1"), `"-1" is not a positive number`)
+}
@@ -0,0 +1,24 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RunWithTimeout runs a phase with a context which is done once timeout elapses, or with ctx itself when timeout is
+// not positive. When the phase fails as its timeout elapsed, the error is wrapped in a TimeoutError. The phase is not
+// given more time than ctx has left.
+func RunWithTimeout(ctx context.Context, phase string, timeout time.Duration,
+	run func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return run(ctx)
+	}
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := run(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Phase: phase, Timeout: timeout, Err: err}
+	}
+	return err
+}
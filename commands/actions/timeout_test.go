@@ -0,0 +1,61 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRunWithTimeout(t *testing.T) {
+	failure := errors.New("failed")
+	waitDone := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		timeout       time.Duration
+		run           func(ctx context.Context) error
+		expectErr     error
+		expectTimeout bool
+	}{
+		{name: "success", ctx: context.Background(), timeout: time.Minute,
+			run: func(context.Context) error { return nil }},
+		{name: "failure", ctx: context.Background(), timeout: time.Minute,
+			run: func(context.Context) error { return failure }, expectErr: failure},
+		{name: "timed out", ctx: context.Background(), timeout: 10 * time.Millisecond, run: waitDone,
+			expectErr: context.DeadlineExceeded, expectTimeout: true},
+		{name: "cancelled", ctx: cancelled, timeout: time.Minute, run: waitDone, expectErr: context.Canceled},
+		{name: "no timeout", ctx: context.Background(),
+			run: func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.False(t, ok)
+				return nil
+			}},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := RunWithTimeout(test.ctx, "Test phase", test.timeout, test.run)
+			if test.expectErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, test.expectErr), err)
+			var timeoutErr *TimeoutError
+			assert.Equal(t, test.expectTimeout, errors.As(err, &timeoutErr))
+		})
+	}
+}
+
+func TestTimeoutError_Error(t *testing.T) {
+	err := &TimeoutError{Phase: "Support Bundle upload", Timeout: 30 * time.Minute, Err: context.DeadlineExceeded}
+	assert.EqualError(t, err, "Support Bundle upload did not complete within 30m0s: context deadline exceeded")
+}
@@ -1,32 +1,96 @@
 package actions
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
 	"net/http"
 )
 
 type uploadHTTPClient interface {
-	UploadSupportBundle(sbFilePath string, repoKey string, supportCaseDirectory string,
-		filename string) (status int, responseBytes []byte, err error)
+	UploadSupportBundle(ctx context.Context, sbFilePath string, repoKey string, supportCaseDirectory string,
+		filename string, checksums flunkyhttp.Checksums, properties *servicesutils.Properties) (status int,
+		responseBytes []byte, err error)
+	DeploySupportBundleChecksum(ctx context.Context, repoKey string, supportCaseDirectory string, filename string,
+		checksums flunkyhttp.Checksums, properties *servicesutils.Properties) (status int, responseBytes []byte,
+		err error)
+	SearchSupportBundlesByChecksum(ctx context.Context, repoKey string, sha256 string) (status int,
+		responseBytes []byte, err error)
+	caseFolderHTTPClient
 	GetURL() string
 }
 
-// UploadSupportBundle uploads a Support Bundle.
-func UploadSupportBundle(client uploadHTTPClient, caseNumber CaseNumber, sbFilePath string,
-	repoKey string, now Clock) (string, error) {
-	filename := now().UTC().Format("SB-20060102-150405Z.zip")
-	url := client.GetURL() + fmt.Sprintf("%s/%s/%s", repoKey, caseNumber, filename)
+// maxNameCollisions limits the uploads retried under the next sequence number of the name template, when the name
+// selected was taken meanwhile by another upload to the same case folder.
+const maxNameCollisions = 3
+
+// UploadSupportBundle uploads a Support Bundle to the case folder of the target, and verifies that the checksums
+// reported by Artifactory match the given ones.
+// To avoid sending the archive again, the URL of an archive with the same SHA-256 already present in the case
+// folder is given instead, and a checksum deploy is attempted before falling back to a full upload.
+// When the upload is refused because another upload took its name meanwhile, it is retried with the next sequence
+// number of the name template.
+// Errors are categorized, as a rejected upload unless the target is invalid.
+func UploadSupportBundle(ctx context.Context, client uploadHTTPClient, target UploadTarget, sbFilePath string,
+	checksums flunkyhttp.Checksums, now Clock) (string, error) {
+	if err := target.Validate(); err != nil {
+		return "", Categorize(CategoryConfiguration, err)
+	}
+	if existingURL := findUploadedSupportBundle(ctx, client, target, checksums); existingURL != "" {
+		log.Info(fmt.Sprintf("Support Bundle already uploaded to %s", existingURL))
+		return existingURL, nil
+	}
+
+	for collisions := 0; ; collisions++ {
+		url, err := uploadNewSupportBundle(ctx, client, target, sbFilePath, checksums, now)
+		var collision *nameCollisionError
+		if !errors.As(err, &collision) || collisions == maxNameCollisions {
+			return url, Categorize(CategoryUploadRejected, err)
+		}
+		log.Info(fmt.Sprintf("%s was taken by another upload meanwhile, trying the next sequence number", url))
+	}
+}
+
+// uploadNewSupportBundle uploads a Support Bundle under a name not used yet in the case folder.
+func uploadNewSupportBundle(ctx context.Context, client uploadHTTPClient, target UploadTarget, sbFilePath string,
+	checksums flunkyhttp.Checksums, now Clock) (string, error) {
+	filename, err := getSupportBundleFilename(ctx, client, target, now)
+	if err != nil {
+		return "", err
+	}
+	url := getUploadURL(client, target, filename)
+	props := target.Properties.toProperties(target.CaseNumber)
+	if deployed, err := deploySupportBundleChecksum(ctx, client, target, filename, checksums, props); deployed ||
+		err != nil {
+		return url, err
+	}
 	log.Debug(fmt.Sprintf("Uploading Support Bundle %s to %s", sbFilePath, url))
 
-	statusCode, respBytes, err := client.UploadSupportBundle(sbFilePath, repoKey, string(caseNumber), filename)
+	statusCode, respBytes, err := client.UploadSupportBundle(ctx, sbFilePath, target.RepoKey, string(target.CaseNumber),
+		filename, checksums, props)
 	if err != nil {
 		return url, err
 	}
 
 	log.Debug(fmt.Sprintf("Got HTTP response status: %d, body: %s", statusCode, respBytes))
 	if statusCode != http.StatusCreated {
-		return url, fmt.Errorf("http request failed with: %d %s", statusCode, http.StatusText(statusCode))
+		return url, checkNameCollision(ctx, client, target, filename, statusCode, newHTTPError(statusCode, respBytes))
+	}
+	return url, verifyUploadResponse(checksums, respBytes)
+}
+
+func verifyUploadResponse(checksums flunkyhttp.Checksums, respBytes []byte) error {
+	var uploadResponse flunkyhttp.UploadResponse
+	if err := json.Unmarshal(respBytes, &uploadResponse); err != nil {
+		return fmt.Errorf("failed to read the checksums of the uploaded Support Bundle: %w", err)
 	}
-	return url, nil
+	return verifyChecksums(checksums, uploadResponse.Checksums)
+}
+
+func getUploadURL(client interface{ GetURL() string }, target UploadTarget, filename string) string {
+	return client.GetURL() + fmt.Sprintf("%s/%s/%s", target.RepoKey, target.CaseNumber, filename)
 }
@@ -1,30 +1,84 @@
 package actions
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
 
+const uploadResponse = `{"repo":"logsRepo","checksums":{"sha1":"%s","md5":"%s","sha256":"%s"}}`
+
+var uploadChecksums = flunkyhttp.Checksums{
+	SHA256: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+	SHA1:   "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed",
+	MD5:    "5eb63bbbe01eeed093cb22bb8f5acdc3",
+}
+
 type uploadClientStub struct {
 	err                error
 	statusCode         int
+	response           string
 	receivedPath       string
 	receivedRepo       string
 	receivedCaseNumber string
 	receivedFilename   string
+	receivedChecksums  flunkyhttp.Checksums
+	receivedProperties *servicesutils.Properties
+	deployStatusCode   int
+	deployResponse     string
+	deployed           bool
+	searchStatusCode   int
+	searchResponse     string
+	searchedSHA256     string
+	folderStatusCode   int
+	folderResponse     string
+	collisions         int
+	taken              []string
+}
+
+func (ucs *uploadClientStub) GetSupportCaseFolder(context.Context, string, string) (status int, responseBytes []byte,
+	err error) {
+	return ucs.folderStatusCode, []byte(ucs.folderResponse), nil
+}
+
+func (ucs *uploadClientStub) DeploySupportBundleChecksum(_ context.Context, _ string, _ string, _ string,
+	_ flunkyhttp.Checksums, _ *servicesutils.Properties) (status int, responseBytes []byte, err error) {
+	ucs.deployed = true
+	return ucs.deployStatusCode, []byte(ucs.deployResponse), nil
+}
+
+func (ucs *uploadClientStub) SearchSupportBundlesByChecksum(_ context.Context, _ string, sha256 string) (status int,
+	responseBytes []byte, err error) {
+	ucs.searchedSHA256 = sha256
+	return ucs.searchStatusCode, []byte(ucs.searchResponse), nil
 }
 
-func (ucs *uploadClientStub) UploadSupportBundle(sbFilePath string, repoKey string, caseNumber string,
-	filename string) (status int, responseBytes []byte, err error) {
+func (ucs *uploadClientStub) UploadSupportBundle(_ context.Context, sbFilePath string, repoKey string,
+	caseNumber string, filename string, checksums flunkyhttp.Checksums, properties *servicesutils.Properties) (status int,
+	responseBytes []byte, err error) {
+	ucs.receivedProperties = properties
 	ucs.receivedPath = sbFilePath
 	ucs.receivedRepo = repoKey
 	ucs.receivedCaseNumber = caseNumber
 	ucs.receivedFilename = filename
-	return ucs.statusCode, []byte("response"), ucs.err
+	ucs.receivedChecksums = checksums
+	if ucs.collisions > 0 {
+		// Another upload takes the name first
+		ucs.collisions--
+		ucs.taken = append(ucs.taken, fmt.Sprintf(`{"uri":"/%s","folder":false}`, filename))
+		ucs.folderStatusCode = http.StatusOK
+		ucs.folderResponse = fmt.Sprintf(`{"children":[%s]}`, strings.Join(ucs.taken, ","))
+		return http.StatusConflict, nil, nil
+	}
+	return ucs.statusCode, []byte(ucs.response), ucs.err
 }
 
 func (ucs *uploadClientStub) GetURL() string {
@@ -41,8 +95,35 @@ func Test_Upload(t *testing.T) {
 			name: "success",
 			clientStub: &uploadClientStub{
 				statusCode: http.StatusCreated,
+				response: fmt.Sprintf(uploadResponse, uploadChecksums.SHA1, uploadChecksums.MD5,
+					strings.ToUpper(uploadChecksums.SHA256)),
 			},
 		},
+		{
+			name: "no checksums reported",
+			clientStub: &uploadClientStub{
+				statusCode: http.StatusCreated,
+				response:   `{"repo":"logsRepo"}`,
+			},
+		},
+		{
+			name: "checksum mismatch",
+			clientStub: &uploadClientStub{
+				statusCode: http.StatusCreated,
+				response:   fmt.Sprintf(uploadResponse, "0000", uploadChecksums.MD5, uploadChecksums.SHA256),
+			},
+			expectedErrorMessage: "SHA-1 checksum mismatch: expected 2aae6c35c94fcfb415dbe95f408b9ce91ee846ed, " +
+				"Artifactory reported 0000",
+		},
+		{
+			name: "invalid response",
+			clientStub: &uploadClientStub{
+				statusCode: http.StatusCreated,
+				response:   "response",
+			},
+			expectedErrorMessage: "failed to read the checksums of the uploaded Support Bundle: " +
+				"invalid character 'r' looking for beginning of value",
+		},
 		{
 			name: "client error",
 			clientStub: &uploadClientStub{
@@ -64,7 +145,13 @@ func Test_Upload(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			caseNumber := CaseNumber("1234")
 			now := func() time.Time { return time.Unix(1, 1) }
-			path, err := UploadSupportBundle(test.clientStub, caseNumber, "/some/file", "logsRepo", now)
+			target := UploadTarget{
+				RepoKey:    "logsRepo",
+				CaseNumber: caseNumber,
+				Hostname:   "host-1",
+				Properties: UploadProperties{FlunkyVersion: "v1"},
+			}
+			path, err := UploadSupportBundle(context.Background(), test.clientStub, target, "/some/file", uploadChecksums, now)
 			if test.expectedErrorMessage != "" {
 				require.Error(t, err)
 				assert.EqualError(t, err, test.expectedErrorMessage)
@@ -74,8 +161,119 @@ func Test_Upload(t *testing.T) {
 			assert.Equal(t, "/some/file", test.clientStub.receivedPath)
 			assert.Equal(t, "logsRepo", test.clientStub.receivedRepo)
 			assert.Equal(t, "1234", test.clientStub.receivedCaseNumber)
-			assert.Equal(t, "SB-19700101-000001Z.zip", test.clientStub.receivedFilename)
-			assert.Equal(t, "http://foo.bar/logsRepo/1234/SB-19700101-000001Z.zip", path)
+			assert.Equal(t, "SB-19700101-000001Z-host-1-1.zip", test.clientStub.receivedFilename)
+			assert.Equal(t, uploadChecksums, test.clientStub.receivedChecksums)
+			assert.Equal(t, "support.caseNumber=1234;support.flunkyVersion=v1",
+				test.clientStub.receivedProperties.ToEncodedString())
+			assert.Equal(t, "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-1.zip", path)
+		})
+	}
+}
+
+func Test_Upload_NameCollision(t *testing.T) {
+	now := func() time.Time { return time.Unix(1, 1) }
+	validResponse := fmt.Sprintf(uploadResponse, uploadChecksums.SHA1, uploadChecksums.MD5, uploadChecksums.SHA256)
+	target := UploadTarget{RepoKey: "logsRepo", CaseNumber: "1234", Hostname: "host-1"}
+
+	client := &uploadClientStub{collisions: 2, statusCode: http.StatusCreated, response: validResponse}
+	url, err := UploadSupportBundle(context.Background(), client, target, "/some/file", uploadChecksums, now)
+	require.NoError(t, err)
+	assert.Equal(t, "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-3.zip", url)
+
+	client = &uploadClientStub{collisions: 10, statusCode: http.StatusCreated, response: validResponse}
+	url, err = UploadSupportBundle(context.Background(), client, target, "/some/file", uploadChecksums, now)
+	assert.EqualError(t, err, "http request failed with: 409 Conflict")
+	assert.Equal(t, CategoryUploadRejected, CategoryOf(err))
+	assert.Equal(t, "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-4.zip", url)
+
+	target.NameTemplate = "SB-{timestamp}.zip"
+	client = &uploadClientStub{collisions: 1, statusCode: http.StatusCreated, response: validResponse}
+	_, err = UploadSupportBundle(context.Background(), client, target, "/some/file", uploadChecksums, now)
+	assert.EqualError(t, err, "http request failed with: 409 Conflict")
+}
+
+func Test_Upload_Deduplication(t *testing.T) {
+	now := func() time.Time { return time.Unix(1, 1) }
+	validResponse := fmt.Sprintf(uploadResponse, uploadChecksums.SHA1, uploadChecksums.MD5, uploadChecksums.SHA256)
+	tests := []struct {
+		name                 string
+		clientStub           *uploadClientStub
+		expectedURL          string
+		expectedErrorMessage string
+		expectDeployed       bool
+		expectUploaded       bool
+	}{
+		{
+			name: "already uploaded in case folder",
+			clientStub: &uploadClientStub{
+				searchStatusCode: http.StatusOK,
+				searchResponse: `{"results":[` +
+					`{"uri":"https://acme.jfrog.io/artifactory/api/storage/logsRepo/9999/SB-1.zip"},` +
+					`{"uri":"https://acme.jfrog.io/artifactory/api/storage/logsRepo/1234/SB-2.zip"}]}`,
+			},
+			expectedURL: "http://foo.bar/logsRepo/1234/SB-2.zip",
+		},
+		{
+			name: "only uploaded in another case folder",
+			clientStub: &uploadClientStub{
+				searchStatusCode: http.StatusOK,
+				searchResponse:   `{"results":[{"uri":"https://acme.jfrog.io/artifactory/api/storage/logsRepo/9999/SB-1.zip"}]}`,
+				deployStatusCode: http.StatusCreated,
+				deployResponse:   validResponse,
+			},
+			expectedURL:    "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-1.zip",
+			expectDeployed: true,
+		},
+		{
+			name: "search forbidden and checksum deploy succeeds",
+			clientStub: &uploadClientStub{
+				searchStatusCode: http.StatusForbidden,
+				deployStatusCode: http.StatusCreated,
+				deployResponse:   validResponse,
+			},
+			expectedURL:    "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-1.zip",
+			expectDeployed: true,
+		},
+		{
+			name: "checksum deploy with mismatching checksums",
+			clientStub: &uploadClientStub{
+				deployStatusCode: http.StatusCreated,
+				deployResponse:   fmt.Sprintf(uploadResponse, uploadChecksums.SHA1, "0000", uploadChecksums.SHA256),
+			},
+			expectedURL: "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-1.zip",
+			expectedErrorMessage: "MD5 checksum mismatch: expected 5eb63bbbe01eeed093cb22bb8f5acdc3, " +
+				"Artifactory reported 0000",
+			expectDeployed: true,
+		},
+		{
+			name: "checksum deploy not found falls back to upload",
+			clientStub: &uploadClientStub{
+				searchStatusCode: http.StatusOK,
+				searchResponse:   `{"results":[]}`,
+				deployStatusCode: http.StatusNotFound,
+				statusCode:       http.StatusCreated,
+				response:         validResponse,
+			},
+			expectedURL:    "http://foo.bar/logsRepo/1234/SB-19700101-000001Z-host-1-1.zip",
+			expectDeployed: true,
+			expectUploaded: true,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			target := UploadTarget{RepoKey: "logsRepo", CaseNumber: "1234", Hostname: "host-1"}
+			url, err := UploadSupportBundle(context.Background(), test.clientStub, target, "/some/file", uploadChecksums, now)
+			if test.expectedErrorMessage != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedErrorMessage)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedURL, url)
+			assert.Equal(t, uploadChecksums.SHA256, test.clientStub.searchedSHA256)
+			assert.Equal(t, test.expectDeployed, test.clientStub.deployed)
+			assert.Equal(t, test.expectUploaded, test.clientStub.receivedPath != "")
 		})
 	}
 }
@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"context"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"net/http"
+)
+
+type versionHTTPClient interface {
+	GetVersion(ctx context.Context) (status int, responseBytes []byte, err error)
+}
+
+// GetVersion gives the version of an Artifactory service.
+func GetVersion(ctx context.Context, client versionHTTPClient) (string, error) {
+	statusCode, body, err := client.GetVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", newHTTPError(statusCode, body)
+	}
+	version, err := flunkyhttp.ParseJSON(body)
+	if err != nil {
+		return "", err
+	}
+	return version.GetString("version")
+}
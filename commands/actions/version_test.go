@@ -0,0 +1,55 @@
+package actions
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type versionClientStub struct {
+	statusCode int
+	response   string
+}
+
+func (s *versionClientStub) GetVersion(context.Context) (int, []byte, error) {
+	return s.statusCode, []byte(s.response), nil
+}
+
+func Test_GetVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		client        *versionClientStub
+		expectVersion string
+		expectErr     string
+	}{
+		{
+			name:          "success",
+			client:        &versionClientStub{statusCode: http.StatusOK, response: `{"version":"7.12.5"}`},
+			expectVersion: "7.12.5",
+		},
+		{
+			name:      "unauthorized",
+			client:    &versionClientStub{statusCode: http.StatusUnauthorized},
+			expectErr: "http request failed with: 401 Unauthorized",
+		},
+		{
+			name:      "no version",
+			client:    &versionClientStub{statusCode: http.StatusOK, response: `{}`},
+			expectErr: "property version not found",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			version, err := GetVersion(context.Background(), test.client)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectVersion, version)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	configCreate = "create"
+	configShow   = "show"
+	configEdit   = "edit"
+	configDelete = "delete"
+)
+
+// GetConfigCommand returns the description of the "config" command.
+func GetConfigCommand() components.Command {
+	return components.Command{
+		Name:        "config",
+		Description: "Creates, shows, edits and deletes profiles of the plugin configuration",
+		Arguments: []components.Argument{
+			{
+				Name:        "action",
+				Description: "One of create, show, edit or delete.",
+			},
+			{
+				Name:        "profile",
+				Description: "Name of the profile, optional with show to show every profile.",
+			},
+		},
+		Flags:   getFlags(append(profileFlagNames(), outputFlag, unsetFlag)...),
+		EnvVars: nil,
+		Action:  configCmd,
+	}
+}
+
+func configCmd(componentContext *components.Context) error {
+	path, err := getPluginConfigPath()
+	if err != nil {
+		return err
+	}
+	// Flags are not taken from environment variables or profiles, only the ones on the command line are stored.
+	output, err := ConfigCmd(&cliAdapter{ctx: componentContext, given: givenFlags(os.Args[1:])}, path)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		log.Output(output)
+	}
+	return nil
+}
+
+// configFacade is a CliFacade telling which flags are given on the command line, as only those are stored.
+type configFacade interface {
+	CliFacade
+	IsFlagSet(flagName string) bool
+}
+
+// ConfigCmd runs the action given as first argument on the plugin configuration file at the given path. Created and
+// edited profiles get the flags given on the command line, but the ones which only apply to a single command.
+func ConfigCmd(cli configFacade, path string) (string, error) {
+	action, name, err := parseConfigArguments(cli)
+	if err != nil {
+		return "", err
+	}
+	config, err := loadPluginConfig(path)
+	if err != nil {
+		return "", err
+	}
+	_, exists := config.Profiles[name]
+	switch {
+	case action == configShow && (name == "" || exists):
+		return showProfiles(cli, config, name)
+	case action == configCreate && exists:
+		return "", fmt.Errorf("profile %s already exists, change it with 'jfrog sb-flunky config edit %s'", name,
+			name)
+	case action != configCreate && !exists:
+		return "", fmt.Errorf("profile %s not found in %s", name, path)
+	case action == configDelete:
+		delete(config.Profiles, name)
+		if err = config.save(path); err != nil {
+			return "", err
+		}
+		log.Info(fmt.Sprintf("Profile %s deleted from %s", name, path))
+		return "", nil
+	default:
+		if err = editProfile(cli, config, name); err != nil {
+			return "", err
+		}
+	}
+	if err = config.save(path); err != nil {
+		return "", err
+	}
+	log.Info(fmt.Sprintf("Profile %s saved to %s", name, path))
+	return "", nil
+}
+
+func parseConfigArguments(cli argumentsProvider) (action string, name string, err error) {
+	arguments := cli.GetArguments()
+	if len(arguments) == 1 && strings.TrimSpace(arguments[0]) == configShow {
+		return configShow, "", nil
+	}
+	trimmed, err := getTrimmedArguments(cli, 2)
+	if err != nil {
+		return "", "", err
+	}
+	action, name = trimmed[0], trimmed[1]
+	switch action {
+	case configCreate, configShow, configEdit, configDelete:
+	default:
+		return "", "", fmt.Errorf("unknown action %s, expected one of: %s, %s, %s, %s", action, configCreate,
+			configShow, configEdit, configDelete)
+	}
+	if name == "" {
+		return "", "", errors.New("empty profile name")
+	}
+	return action, name, nil
+}
+
+func editProfile(cli configFacade, config *pluginConfig, name string) error {
+	edited := profile{}
+	for flagName, value := range config.Profiles[name] {
+		edited[flagName] = value
+	}
+	for _, flagName := range profileFlagNames() {
+		if !cli.IsFlagSet(flagName) {
+			continue
+		}
+		switch flagDefinitions[flagName].(type) {
+		case components.StringFlag:
+			if value := strings.TrimSpace(cli.GetStringFlagValue(flagName)); value != "" {
+				edited[flagName] = value
+			}
+		case components.BoolFlag:
+			edited[flagName] = strconv.FormatBool(cli.GetBoolFlagValue(flagName))
+		}
+	}
+	if unset := strings.TrimSpace(cli.GetStringFlagValue(unsetFlag)); unset != "" {
+		for _, flagName := range strings.Split(unset, ",") {
+			flagName = strings.TrimPrefix(strings.TrimSpace(flagName), "--")
+			if !isProfileFlag(flagName) {
+				return fmt.Errorf("unknown flag %s in --%s", flagName, unsetFlag)
+			}
+			delete(edited, flagName)
+		}
+	}
+	config.Profiles[name] = edited
+	return nil
+}
+
+func showProfiles(cli flagValueProvider, config *pluginConfig, name string) (string, error) {
+	format, err := getOutputFormat(cli)
+	if err != nil {
+		return "", err
+	}
+	profiles := config.Profiles
+	if name != "" {
+		profiles = map[string]profile{name: config.Profiles[name]}
+	}
+	return formatOutput(format, profiles, func(w io.Writer) error {
+		return writeProfilesTable(w, profiles)
+	})
+}
+
+func writeProfilesTable(w io.Writer, profiles map[string]profile) error {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "PROFILE\tFLAG\tVALUE")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		for _, flagName := range profileFlagNames() {
+			if value, ok := profiles[name][flagName]; ok {
+				if _, err = fmt.Fprintf(tw, "%s\t%s\t%s\n", name, flagName, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return tw.Flush()
+}
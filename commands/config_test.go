@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_ConfigCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "sb-flunky", "config.yaml")
+
+	_, err = ConfigCmd(&cliStub{
+		arguments:   []string{"create", "prod"},
+		stringFlags: map[string]string{"server-id": "prod", "download-timeout": "10m", "preset": "full", "output": "json"},
+		boolFlags:   map[string]bool{"cleanup": false, "dry-run": true},
+	}, path)
+	require.NoError(t, err)
+	assertProfile(t, path, "prod", profile{"server-id": "prod", "download-timeout": "10m", "preset": "full",
+		"cleanup": "false"})
+
+	_, err = ConfigCmd(&cliStub{arguments: []string{"create", "prod"}}, path)
+	assert.EqualError(t, err, "profile prod already exists, change it with 'jfrog sb-flunky config edit prod'")
+
+	_, err = ConfigCmd(&cliStub{
+		arguments:   []string{"edit", "prod"},
+		stringFlags: map[string]string{"target-repo": "support", "unset": "preset, --cleanup, download-timeout"},
+		boolFlags:   map[string]bool{"cleanup": true},
+	}, path)
+	require.NoError(t, err)
+	assertProfile(t, path, "prod", profile{"server-id": "prod", "target-repo": "support"})
+
+	output, err := ConfigCmd(&cliStub{arguments: []string{"show"}}, path)
+	require.NoError(t, err)
+	assert.Equal(t, "PROFILE  FLAG         VALUE\nprod     server-id    prod\nprod     target-repo  support", output)
+
+	output, err = ConfigCmd(&cliStub{arguments: []string{"show", "prod"}, stringFlags: map[string]string{
+		"output": "yaml",
+	}}, path)
+	require.NoError(t, err)
+	assert.Equal(t, "prod:\n  server-id: prod\n  target-repo: support", output)
+
+	_, err = ConfigCmd(&cliStub{arguments: []string{"delete", "prod"}}, path)
+	require.NoError(t, err)
+	config, err := loadPluginConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, config.Profiles)
+}
+
+func Test_ConfigCmd_errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "config.yaml")
+
+	tests := []struct {
+		name      string
+		cli       *cliStub
+		expectErr string
+	}{
+		{
+			name:      "missing profile name",
+			cli:       &cliStub{arguments: []string{"create"}},
+			expectErr: "wrong number of arguments. Expected: 2, Received: 1",
+		},
+		{
+			name:      "unknown action",
+			cli:       &cliStub{arguments: []string{"rename", "prod"}},
+			expectErr: "unknown action rename, expected one of: create, show, edit, delete",
+		},
+		{
+			name:      "unknown profile",
+			cli:       &cliStub{arguments: []string{"edit", "prod"}},
+			expectErr: "profile prod not found in " + path,
+		},
+		{
+			name:      "unknown flag to unset",
+			cli:       &cliStub{arguments: []string{"create", "prod"}, stringFlags: map[string]string{"unset": "server"}},
+			expectErr: "unknown flag server in --unset",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ConfigCmd(test.cli, path)
+			assert.EqualError(t, err, test.expectErr)
+		})
+	}
+}
+
+func assertProfile(t *testing.T, path string, name string, expected profile) {
+	config, err := loadPluginConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Diff(expected, config.Profiles[name]))
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+)
+
+// GetCreateCommand returns the description of the "create" command.
+func GetCreateCommand() components.Command {
+	flags := getFlags(serverIDFlag, promptOptionsFlag, answersFileFlag, optionsFileFlag, presetFlag,
+		logsSinceFlag, logsFromFlag, logsToFlag, threadDumpsFlag, threadDumpIntervalFlag, timezoneFlag,
+		createTimeoutFlag, maxAttemptsFlag, retryIntervalFlag, maxRetryIntervalFlag, profileFlag)
+	return components.Command{
+		Name:        "create",
+		Description: "Creates a Support Bundle and prints its ID",
+		Arguments:   []components.Argument{caseArgument()},
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
+		Action:      createCmd,
+	}
+}
+
+func createCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetCreateCommand().Flags)
+	if err != nil {
+		return err
+	}
+	bundleID, err := CreateCmd(context.Background(), cli)
+	if err != nil {
+		return toCliError(err)
+	}
+	log.Output(bundleID)
+	return nil
+}
+
+// CreateCmd creates a Support Bundle on the source Artifactory service. The options are gathered first, so that the
+// time spent answering prompts does not count in the creation timeout.
+func CreateCmd(ctx context.Context, cli CliFacade) (actions.BundleID, error) {
+	caseNumber, err := parseArguments(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Case number is %s", caseNumber))
+
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
+
+	optionsProvider, err := getOptionsProvider(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	options, err := optionsProvider.GetOptions(caseNumber)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	timeout, err := getCreateTimeout(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	var bundleID actions.BundleID
+	err = actions.RunWithTimeout(ctx, createTimeoutPhase, timeout, func(ctx context.Context) (err error) {
+		bundleID, err = actions.CreateSupportBundleWithOptions(ctx, client, options)
+		return err
+	})
+	return bundleID, err
+}
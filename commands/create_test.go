@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GetCreateCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "create",
+		Description: "Creates a Support Bundle and prints its ID",
+		Arguments: []components.Argument{
+			{
+				Name:        "case",
+				Description: "JFrog Support case number.",
+			},
+		},
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "server-id",
+				Description: "Artifactory server ID configured using the config command. " +
+					"If not provided the default configuration will be used.",
+			},
+			components.BoolFlag{
+				Name:        "prompt-options",
+				Description: "Ask for support bundle options or use Artifactory default options.",
+			},
+			components.StringFlag{
+				Name: "answers-file",
+				Description: "Path to a YAML or JSON file answering the questions asked with --prompt-options or without " +
+					"a case number, - to read it from stdin. Answers are read from stdin when it is not a terminal.",
+			},
+			components.StringFlag{
+				Name: "options-file",
+				Description: "Path to a YAML or JSON file describing the content of the support bundle. " +
+					"Cannot be used with --prompt-options.",
+			},
+			components.StringFlag{
+				Name: "preset",
+				Description: "Name of a built-in preset describing the content of the support bundle, " +
+					"see the presets command. Cannot be used with --prompt-options or --options-file.",
+			},
+			components.StringFlag{
+				Name:        "logs-since",
+				Description: "Include the logs from this long ago until today, for example 72h or 3d.",
+			},
+			components.StringFlag{
+				Name:        "logs-from",
+				Description: "Include the logs from this date, in the YYYY-MM-DD format.",
+			},
+			components.StringFlag{
+				Name:        "logs-to",
+				Description: "Include the logs until this date, in the YYYY-MM-DD format.",
+			},
+			components.StringFlag{
+				Name:        "thread-dumps",
+				Description: "The number of thread dumps to include.",
+			},
+			components.StringFlag{
+				Name:        "thread-dump-interval",
+				Description: "The interval between thread dumps, for example 10s.",
+			},
+			components.StringFlag{
+				Name:         "timezone",
+				Description:  "The time zone of the log dates and of the support bundle description, for example Europe/Paris.",
+				DefaultValue: "UTC",
+			},
+			components.StringFlag{
+				Name:         "create-timeout",
+				Description:  "The timeout for the creation request of the support bundle, including its retries. 0 disables it.",
+				DefaultValue: "5m",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetCreateCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
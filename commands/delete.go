@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+)
+
+// GetDeleteCommand returns the description of the "delete" command.
+func GetDeleteCommand() components.Command {
+	flags := getFlags(serverIDFlag, maxAttemptsFlag, retryIntervalFlag, maxRetryIntervalFlag, profileFlag)
+	return components.Command{
+		Name:        "delete",
+		Description: "Deletes a Support Bundle from the source Artifactory service",
+		Aliases:     []string{"rm"},
+		Arguments:   []components.Argument{bundleIDArgument()},
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
+		Action:      deleteCmd,
+	}
+}
+
+func deleteCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetDeleteCommand().Flags)
+	if err != nil {
+		return err
+	}
+	return toCliError(DeleteCmd(context.Background(), cli))
+}
+
+// DeleteCmd deletes a Support Bundle from the source Artifactory service.
+func DeleteCmd(ctx context.Context, cli CliFacade) error {
+	bundleID, err := parseBundleIDArgument(cli)
+	if err != nil {
+		return configurationError(err)
+	}
+
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		return configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
+
+	err = actions.DeleteSupportBundle(ctx, client, bundleID)
+	if err != nil {
+		return requestError(err)
+	}
+	log.Info(fmt.Sprintf("Deleted Support Bundle %s", bundleID))
+	return nil
+}
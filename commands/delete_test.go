@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GetDeleteCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "delete",
+		Description: "Deletes a Support Bundle from the source Artifactory service",
+		Aliases:     []string{"rm"},
+		Arguments: []components.Argument{
+			{
+				Name:        "bundle-id",
+				Description: "ID of the Support Bundle on the source Artifactory service.",
+			},
+		},
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "server-id",
+				Description: "Artifactory server ID configured using the config command. " +
+					"If not provided the default configuration will be used.",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetDeleteCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
+
+func Test_DeleteCmd(t *testing.T) {
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && r.URL.Path == "/api/system/support/bundle/1" {
+			deleted = append(deleted, "1")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.URL.Path == "/api/system/support/bundle/3" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	rtDetails := &config.ArtifactoryDetails{Url: ts.URL + "/"}
+
+	assert.NoError(t, DeleteCmd(context.Background(), &cliStub{arguments: []string{"1"}, rtDetails: rtDetails}))
+	err := DeleteCmd(context.Background(), &cliStub{arguments: []string{"2"}, rtDetails: rtDetails})
+	assert.EqualError(t, err, "http request failed with: 404 Not Found")
+	assert.Empty(t, actions.CategoryOf(err))
+	err = DeleteCmd(context.Background(), &cliStub{arguments: []string{"3"}, rtDetails: rtDetails})
+	assert.EqualError(t, err, "http request failed with: 403 Forbidden")
+	assert.Equal(t, actions.CategoryAuthentication, actions.CategoryOf(err))
+	err = DeleteCmd(context.Background(), &cliStub{rtDetails: rtDetails})
+	assert.EqualError(t, err, "wrong number of arguments. Expected: 1, Received: 0")
+	assert.Equal(t, actions.CategoryConfiguration, actions.CategoryOf(err))
+	err = DeleteCmd(context.Background(), &cliStub{arguments: []string{"1"}, rtDetails: rtDetails,
+		stringFlags: map[string]string{"retry-interval": "0s"}})
+	assert.EqualError(t, err, "invalid retry-interval value 0s, expected a positive duration")
+	assert.Equal(t, actions.CategoryConfiguration, actions.CategoryOf(err))
+	assert.Equal(t, []string{"1"}, deleted)
+}
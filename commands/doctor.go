@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// GetDoctorCommand returns the description of the "doctor" command.
+func GetDoctorCommand() components.Command {
+	flags := getFlags(serverIDFlag, targetServerIDFlag, targetRepoFlag, maxAttemptsFlag, retryIntervalFlag,
+		maxRetryIntervalFlag, outputFlag, profileFlag)
+	return components.Command{
+		Name: "doctor",
+		Description: "Checks that a Support Bundle can be created on the source Artifactory service and uploaded to " +
+			"the target, without creating anything",
+		Arguments: nil,
+		Flags:     flags,
+		EnvVars:   getEnvVars(flags),
+		Action:    doctorCmd,
+	}
+}
+
+func doctorCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetDoctorCommand().Flags)
+	if err != nil {
+		return err
+	}
+	format, err := getOutputFormat(cli)
+	if err != nil {
+		return toCliError(configurationError(err))
+	}
+	results := DoctorCmd(context.Background(), cli)
+	output, err := formatOutput(format, results, func(w io.Writer) error {
+		return writeCheckResultsTable(w, results)
+	})
+	if err != nil {
+		return err
+	}
+	log.Output(output)
+	return toCliError(checksError(results))
+}
+
+// checksError gives the error the doctor command fails with when some checks failed, of the category of the first
+// failed check, which is the error support-case would fail with first.
+func checksError(results []actions.CheckResult) error {
+	failed := 0
+	var category actions.ErrorCategory
+	for _, result := range results {
+		if result.Status != actions.CheckFail {
+			continue
+		}
+		if failed == 0 {
+			category = result.Category
+		}
+		failed++
+	}
+	if failed == 0 {
+		return nil
+	}
+	return actions.Categorize(category, fmt.Errorf("%d of %d checks failed", failed, len(results)))
+}
+
+// DoctorCmd checks the configuration of the source and target services, the credentials used for each of them, and
+// the temp directory Support Bundles are downloaded to. Nothing is created on either service.
+func DoctorCmd(ctx context.Context, cli CliFacade) []actions.CheckResult {
+	var results []actions.CheckResult
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		results = append(results, actions.CheckResult{Name: "source-config", Status: actions.CheckFail,
+			Category: actions.CategoryConfiguration, Message: err.Error(),
+			Hint: "Add the source server with 'jfrog rt config', or choose another one with --" + serverIDFlag + "."})
+	} else {
+		results = append(results, actions.CheckResult{Name: "source-config", Status: actions.CheckPass,
+			Message: fmt.Sprintf("Using %s", client.GetURL())})
+		results = append(results, actions.CheckSource(ctx, client)...)
+	}
+	results = append(results, actions.CheckTempDir(getTempDir()))
+
+	targetClient, err := getRtClient(cli, cli.GetTargetDetails)
+	if err != nil {
+		return append(results, actions.CheckResult{Name: "target-config", Status: actions.CheckFail,
+			Category: actions.CategoryConfiguration, Message: err.Error(),
+			Hint: "Add the target server with 'jfrog rt config', or choose another one with --" + targetServerIDFlag + "."})
+	}
+	results = append(results, actions.CheckResult{Name: "target-config", Status: actions.CheckPass,
+		Message: fmt.Sprintf("Using %s", targetClient.GetURL())})
+	return append(results, actions.CheckTarget(ctx, targetClient, getTargetRepo(cli), time.Now)...)
+}
+
+// getTempDir gives the directory Support Bundles are downloaded to, the same as JFrog CLI.
+func getTempDir() string {
+	if dir := os.Getenv(coreutils.TempDir); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+func writeCheckResultsTable(w io.Writer, results []actions.CheckResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "CHECK\tRESULT\tDETAILS")
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if _, err = fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Name, result.Status, result.Message); err != nil {
+			return err
+		}
+		if result.Hint != "" {
+			if _, err = fmt.Fprintf(tw, "\t\tHint: %s\n", result.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GetDoctorCommand(t *testing.T) {
+	expected := components.Command{
+		Name: "doctor",
+		Description: "Checks that a Support Bundle can be created on the source Artifactory service and uploaded to " +
+			"the target, without creating anything",
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "server-id",
+				Description: "Artifactory server ID configured using the config command. " +
+					"If not provided the default configuration will be used.",
+			},
+			components.StringFlag{
+				Name: "target-server-id",
+				Description: "Artifactory server ID configured using the config command to be used as the target for " +
+					"uploading the generated Support Bundle. If not provided JFrog support logs will be used.",
+			},
+			components.StringFlag{
+				Name:         "target-repo",
+				Description:  "The target repository key where the support bundle will be uploaded to.",
+				DefaultValue: "logs",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name:         "output",
+				Description:  "The output format: text, json or yaml.",
+				DefaultValue: "text",
+			},
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetDoctorCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
+
+func Test_DoctorCmd(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/api/system/ping":
+			_, _ = w.Write([]byte("OK"))
+		case "/api/system/version":
+			_, _ = w.Write([]byte(`{"version":"7.12.5","revision":"71205900"}`))
+		case "/api/system/support/bundles":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"status":404,"message":"Checksum deploy failed."}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	results := DoctorCmd(context.Background(), &cliStub{
+		stringFlags:     map[string]string{"target-repo": "logs"},
+		rtDetails:       &config.ArtifactoryDetails{Url: ts.URL + "/"},
+		targetRtDetails: &config.ArtifactoryDetails{Url: ts.URL + "/"},
+	})
+
+	names := make([]string, 0, len(results))
+	statuses := make(map[string]actions.CheckStatus, len(results))
+	for _, result := range results {
+		names = append(names, result.Name)
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, []string{"source-config", "source-ping", "source-version", "source-list-permission", "temp-space",
+		"target-config", "target-ping", "target-permissions"}, names)
+	assert.Equal(t, actions.CheckPass, statuses["source-version"])
+	assert.Equal(t, actions.CheckFail, statuses["source-list-permission"])
+	assert.Equal(t, actions.CheckPass, statuses["target-permissions"])
+	for _, request := range requests {
+		assert.NotEqual(t, "POST /api/system/support/bundle", request)
+	}
+}
+
+func Test_DoctorCmd_missingConfiguration(t *testing.T) {
+	results := DoctorCmd(context.Background(), &cliStub{})
+	require.Len(t, results, 3)
+	assert.Equal(t, actions.CheckResult{Name: "source-config", Status: actions.CheckFail,
+		Category: actions.CategoryConfiguration, Message: "failed to get RT details",
+		Hint: "Add the source server with 'jfrog rt config', or choose another one with --server-id."}, results[0])
+	assert.Equal(t, "temp-space", results[1].Name)
+	assert.Equal(t, "target-config", results[2].Name)
+	assert.Equal(t, actions.CheckFail, results[2].Status)
+}
+
+func Test_checksError(t *testing.T) {
+	pass := actions.CheckResult{Name: "source-ping", Status: actions.CheckPass}
+	assert.NoError(t, checksError([]actions.CheckResult{pass, {Name: "temp-space", Status: actions.CheckWarn}}))
+
+	err := checksError([]actions.CheckResult{
+		pass,
+		{Name: "source-list-permission", Status: actions.CheckFail, Category: actions.CategoryAuthentication},
+		{Name: "target-config", Status: actions.CheckFail, Category: actions.CategoryConfiguration},
+	})
+	assert.EqualError(t, err, "2 of 3 checks failed")
+	assert.Equal(t, actions.CategoryAuthentication, actions.CategoryOf(err))
+	assert.Equal(t, 11, exitCodeOf(err))
+}
+
+func Test_writeCheckResultsTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeCheckResultsTable(&buf, []actions.CheckResult{
+		{Name: "source-ping", Status: actions.CheckPass, Message: "http://source/ is reachable"},
+		{Name: "target-permissions", Status: actions.CheckFail, Message: "The credentials may not deploy to logs",
+			Hint: "Give the deploy permission."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "CHECK               RESULT  DETAILS\n"+
+		"source-ping         pass    http://source/ is reachable\n"+
+		"target-permissions  fail    The credentials may not deploy to logs\n"+
+		"                            Hint: Give the deploy permission.\n", buf.String())
+}
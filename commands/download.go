@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+)
+
+// GetDownloadCommand returns the description of the "download" command.
+func GetDownloadCommand() components.Command {
+	flags := getFlags(serverIDFlag, downloadTimeoutFlag, transferTimeoutFlag, maxAttemptsFlag, retryIntervalFlag,
+		maxRetryIntervalFlag, profileFlag)
+	return components.Command{
+		Name:        "download",
+		Description: "Downloads an existing Support Bundle to a local temp file and prints its path",
+		Arguments:   []components.Argument{bundleIDArgument()},
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
+		Action:      downloadCmd,
+	}
+}
+
+func downloadCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetDownloadCommand().Flags)
+	if err != nil {
+		return err
+	}
+	path, err := DownloadCmd(context.Background(), cli)
+	if err != nil {
+		return toCliError(err)
+	}
+	log.Output(path)
+	return nil
+}
+
+// DownloadCmd waits for a Support Bundle to be ready and downloads it from the source Artifactory service.
+func DownloadCmd(ctx context.Context, cli CliFacade) (string, error) {
+	bundleID, err := parseBundleIDArgument(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
+	timeouts, err := getDownloadTimeouts(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+
+	path, checksums, err := actions.DownloadSupportBundle(ctx, client, timeouts, client.Retry, bundleID)
+	if err != nil {
+		return "", err
+	}
+	log.Info(fmt.Sprintf("SHA-256: %s", checksums.SHA256))
+	return path, nil
+}
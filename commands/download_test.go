@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GetDownloadCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "download",
+		Description: "Downloads an existing Support Bundle to a local temp file and prints its path",
+		Arguments: []components.Argument{
+			{
+				Name:        "bundle-id",
+				Description: "ID of the Support Bundle on the source Artifactory service.",
+			},
+		},
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "server-id",
+				Description: "Artifactory server ID configured using the config command. " +
+					"If not provided the default configuration will be used.",
+			},
+			components.StringFlag{
+				Name:         "download-timeout",
+				Description:  "The timeout for download.",
+				DefaultValue: "10m",
+			},
+			components.StringFlag{
+				Name: "transfer-timeout",
+				Description: "The timeout for the transfer of the support bundle archive once ready, including resumed " +
+					"attempts, or for the whole transfer with --stream. 0 disables it.",
+				DefaultValue: "1h",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetDownloadCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"strings"
+)
+
+// formatExecutionPlan renders the execution plan of a dry run for a human reader.
+func formatExecutionPlan(plan *actions.ExecutionPlan) (string, error) {
+	payload, err := json.MarshalIndent(plan.Payload, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("Dry run, no request is sent to Artifactory.\n")
+	fmt.Fprintf(&buf, "Source: %s\nTarget: %s\nUpload URL: %s\n", plan.SourceURL, plan.TargetURL, plan.UploadURL)
+	fmt.Fprintf(&buf, "Payload:\n%s\n", payload)
+	buf.WriteString("Properties:\n")
+	for _, property := range plan.Properties {
+		fmt.Fprintf(&buf, "  %s\n", property)
+	}
+	buf.WriteString("Steps:\n")
+	for i, step := range plan.Steps {
+		fmt.Fprintf(&buf, "  %d. %s\n     %s %s\n", i+1, step.Description, step.Method, step.URL)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
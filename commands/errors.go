@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-core/utils/coreutils"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"net/http"
+)
+
+// exitCodes are the exit codes of the commands per category of error. Errors of an unknown category exit with 1, as
+// any JFrog CLI command. An interrupted command exits with 130, as shells report a process killed by SIGINT.
+var exitCodes = map[actions.ErrorCategory]int{
+	actions.CategoryConfiguration:     10,
+	actions.CategoryAuthentication:    11,
+	actions.CategoryCreation:          12,
+	actions.CategoryGenerationFailure: 13,
+	actions.CategoryDownloadTimeout:   14,
+	actions.CategoryDownloadTransfer:  15,
+	actions.CategoryUploadRejected:    16,
+	actions.CategoryInterrupted:       130,
+}
+
+// exitCodeOf gives the exit code of err, or 0 when its category is not known.
+func exitCodeOf(err error) int {
+	return exitCodes[actions.CategoryOf(err)]
+}
+
+// toCliError gives err as the error JFrog CLI exits with the exit code of its category.
+func toCliError(err error) error {
+	code := exitCodeOf(err)
+	if code == 0 {
+		return err
+	}
+	return coreutils.CliError{ExitCode: coreutils.ExitCode{Code: code}, ErrorMsg: err.Error()}
+}
+
+// configurationError gives err as an error of the configuration category, unless the user cancelled the command.
+func configurationError(err error) error {
+	if errors.Is(err, actions.ErrCreationCancelled) {
+		return err
+	}
+	return actions.Categorize(actions.CategoryConfiguration, err)
+}
+
+// requestError gives err as an error of the authentication category when a service rejected the credentials. Other
+// failures of a request are kept as they are, as they have no category of their own outside of support-case.
+func requestError(err error) error {
+	var httpErr *actions.HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusUnauthorized ||
+		httpErr.StatusCode == http.StatusForbidden) {
+		return actions.Categorize(actions.CategoryAuthentication, err)
+	}
+	return err
+}
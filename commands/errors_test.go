@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/utils/coreutils"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func Test_toCliError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		expectCode int
+	}{
+		{name: "configuration", err: configurationError(errors.New("bad flag")), expectCode: 10},
+		{name: "authentication", err: actions.Categorize(actions.CategoryAuthentication, errors.New("no")), expectCode: 11},
+		{name: "creation", err: actions.Categorize(actions.CategoryCreation, errors.New("no")), expectCode: 12},
+		{name: "generation failure", err: actions.Categorize(actions.CategoryGenerationFailure, errors.New("no")),
+			expectCode: 13},
+		{name: "download timeout", err: actions.Categorize(actions.CategoryDownloadTimeout, actions.ErrReadyTimeout),
+			expectCode: 14},
+		{name: "download transfer", err: actions.Categorize(actions.CategoryDownloadTransfer, errors.New("no")),
+			expectCode: 15},
+		{name: "upload rejected", err: fmt.Errorf("upload: %w",
+			actions.Categorize(actions.CategoryUploadRejected, errors.New("no"))), expectCode: 16},
+		{name: "interrupted", err: actions.Categorize(actions.CategoryInterrupted, context.Canceled), expectCode: 130},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := toCliError(test.err)
+			cliErr, ok := err.(coreutils.CliError)
+			if assert.True(t, ok) {
+				assert.Equal(t, test.expectCode, cliErr.ExitCode.Code)
+				assert.Equal(t, test.err.Error(), cliErr.ErrorMsg)
+			}
+		})
+	}
+
+	unknown := errors.New("unknown")
+	assert.Equal(t, unknown, toCliError(unknown))
+}
+
+func Test_configurationError(t *testing.T) {
+	assert.Equal(t, actions.ErrCreationCancelled, configurationError(actions.ErrCreationCancelled))
+	assert.Nil(t, configurationError(nil))
+}
+
+func Test_requestError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectCategory actions.ErrorCategory
+	}{
+		{name: "unauthorized", err: &actions.HTTPError{StatusCode: http.StatusUnauthorized},
+			expectCategory: actions.CategoryAuthentication},
+		{name: "forbidden", err: fmt.Errorf("delete: %w", &actions.HTTPError{StatusCode: http.StatusForbidden}),
+			expectCategory: actions.CategoryAuthentication},
+		{name: "not found", err: &actions.HTTPError{StatusCode: http.StatusNotFound}},
+		{name: "network", err: errors.New("connection refused")},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := requestError(test.err)
+			assert.Equal(t, test.err.Error(), err.Error())
+			assert.Equal(t, test.expectCategory, actions.CategoryOf(err))
+		})
+	}
+	assert.Nil(t, requestError(nil))
+}
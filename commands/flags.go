@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+)
+
+const (
+	serverIDFlag           = "server-id"
+	targetServerIDFlag     = "target-server-id"
+	downloadTimeoutFlag    = "download-timeout"
+	createTimeoutFlag      = "create-timeout"
+	transferTimeoutFlag    = "transfer-timeout"
+	uploadTimeoutFlag      = "upload-timeout"
+	deadlineFlag           = "deadline"
+	retryIntervalFlag      = "retry-interval"
+	maxAttemptsFlag        = "max-attempts"
+	maxRetryIntervalFlag   = "max-retry-interval"
+	promptOptionsFlag      = "prompt-options"
+	answersFileFlag        = "answers-file"
+	cleanupFlag            = "cleanup"
+	cleanupRemoteFlag      = "cleanup-remote"
+	targetRepoFlag         = "target-repo"
+	outputFlag             = "output"
+	keepLastFlag           = "keep-last"
+	olderThanFlag          = "older-than"
+	flunkyOnlyFlag         = "flunky-only"
+	dryRunFlag             = "dry-run"
+	streamFlag             = "stream"
+	propertyFlag           = "property"
+	nameTemplateFlag       = "name-template"
+	optionsFileFlag        = "options-file"
+	presetFlag             = "preset"
+	logsSinceFlag          = "logs-since"
+	logsFromFlag           = "logs-from"
+	logsToFlag             = "logs-to"
+	threadDumpsFlag        = "thread-dumps"
+	threadDumpIntervalFlag = "thread-dump-interval"
+	timezoneFlag           = "timezone"
+	profileFlag            = "profile"
+	unsetFlag              = "unset"
+)
+
+// flagDefinitions holds the definition of every flag supported by the plugin, so that commands sharing a flag also
+// share its description and default value.
+var flagDefinitions = map[string]components.Flag{
+	serverIDFlag: components.StringFlag{
+		Name: serverIDFlag,
+		Description: "Artifactory server ID configured using the config command. " +
+			"If not provided the default configuration will be used.",
+	},
+	targetServerIDFlag: components.StringFlag{
+		Name: targetServerIDFlag,
+		Description: "Artifactory server ID configured using the config command to be used as the target for " +
+			"uploading the generated Support Bundle. If not provided JFrog support logs will be used.",
+	},
+	downloadTimeoutFlag: components.StringFlag{
+		Name:         downloadTimeoutFlag,
+		Description:  "The timeout for download.",
+		DefaultValue: "10m",
+	},
+	createTimeoutFlag: components.StringFlag{
+		Name:         createTimeoutFlag,
+		Description:  "The timeout for the creation request of the support bundle, including its retries. 0 disables it.",
+		DefaultValue: "5m",
+	},
+	transferTimeoutFlag: components.StringFlag{
+		Name: transferTimeoutFlag,
+		Description: "The timeout for the transfer of the support bundle archive once ready, including resumed " +
+			"attempts, or for the whole transfer with --stream. 0 disables it.",
+		DefaultValue: "1h",
+	},
+	uploadTimeoutFlag: components.StringFlag{
+		Name:         uploadTimeoutFlag,
+		Description:  "The timeout for the upload of the support bundle, including its retries. 0 disables it.",
+		DefaultValue: "1h",
+	},
+	deadlineFlag: components.StringFlag{
+		Name: deadlineFlag,
+		Description: "The timeout for the whole command, aborting the requests in flight once elapsed. " +
+			"Not set by default.",
+	},
+	retryIntervalFlag: components.StringFlag{
+		Name: retryIntervalFlag,
+		Description: "The duration to wait before the first retry of a request to Artifactory, or the first check of " +
+			"the support bundle status, doubled before each next one.",
+		DefaultValue: "5s",
+	},
+	maxAttemptsFlag: components.StringFlag{
+		Name: maxAttemptsFlag,
+		Description: "The maximum number of attempts of a request to Artifactory failing due to a network error or " +
+			"a 408, 429, 502, 503 or 504 status, including the first one.",
+		DefaultValue: "5",
+	},
+	maxRetryIntervalFlag: components.StringFlag{
+		Name: maxRetryIntervalFlag,
+		Description: "The maximum duration to wait between two attempts, also when Artifactory asks to wait longer " +
+			"with a Retry-After header.",
+		DefaultValue: "1m",
+	},
+	promptOptionsFlag: components.BoolFlag{
+		Name:        promptOptionsFlag,
+		Description: "Ask for support bundle options or use Artifactory default options.",
+	},
+	answersFileFlag: components.StringFlag{
+		Name: answersFileFlag,
+		Description: "Path to a YAML or JSON file answering the questions asked with --prompt-options or without " +
+			"a case number, - to read it from stdin. Answers are read from stdin when it is not a terminal.",
+	},
+	optionsFileFlag: components.StringFlag{
+		Name: optionsFileFlag,
+		Description: "Path to a YAML or JSON file describing the content of the support bundle. " +
+			"Cannot be used with --prompt-options.",
+	},
+	presetFlag: components.StringFlag{
+		Name: presetFlag,
+		Description: "Name of a built-in preset describing the content of the support bundle, " +
+			"see the presets command. Cannot be used with --prompt-options or --options-file.",
+	},
+	logsSinceFlag: components.StringFlag{
+		Name:        logsSinceFlag,
+		Description: "Include the logs from this long ago until today, for example 72h or 3d.",
+	},
+	logsFromFlag: components.StringFlag{
+		Name:        logsFromFlag,
+		Description: "Include the logs from this date, in the YYYY-MM-DD format.",
+	},
+	logsToFlag: components.StringFlag{
+		Name:        logsToFlag,
+		Description: "Include the logs until this date, in the YYYY-MM-DD format.",
+	},
+	threadDumpsFlag: components.StringFlag{
+		Name:        threadDumpsFlag,
+		Description: "The number of thread dumps to include.",
+	},
+	threadDumpIntervalFlag: components.StringFlag{
+		Name:        threadDumpIntervalFlag,
+		Description: "The interval between thread dumps, for example 10s.",
+	},
+	timezoneFlag: components.StringFlag{
+		Name:         timezoneFlag,
+		Description:  "The time zone of the log dates and of the support bundle description, for example Europe/Paris.",
+		DefaultValue: "UTC",
+	},
+	cleanupFlag: components.BoolFlag{
+		Name:         cleanupFlag,
+		Description:  "Delete the support bundle local temp file after upload.",
+		DefaultValue: true,
+	},
+	cleanupRemoteFlag: components.BoolFlag{
+		Name:        cleanupRemoteFlag,
+		Description: "Delete the support bundle from the source service when the command is interrupted.",
+	},
+	targetRepoFlag: components.StringFlag{
+		Name:         targetRepoFlag,
+		Description:  "The target repository key where the support bundle will be uploaded to.",
+		DefaultValue: "logs",
+	},
+	outputFlag: components.StringFlag{
+		Name:         outputFlag,
+		Description:  "The output format: text, json or yaml.",
+		DefaultValue: textOutput,
+	},
+	keepLastFlag: components.StringFlag{
+		Name:        keepLastFlag,
+		Description: "The number of most recent Support Bundles to keep.",
+	},
+	olderThanFlag: components.StringFlag{
+		Name:        olderThanFlag,
+		Description: "Only delete the Support Bundles older than this duration, for example 36h or 30d.",
+	},
+	flunkyOnlyFlag: components.BoolFlag{
+		Name:        flunkyOnlyFlag,
+		Description: "Only consider the Support Bundles created by this plugin.",
+	},
+	dryRunFlag: components.BoolFlag{
+		Name:        dryRunFlag,
+		Description: "Print what would be done without changing anything.",
+	},
+	nameTemplateFlag: components.StringFlag{
+		Name: nameTemplateFlag,
+		Description: "The name of the uploaded support bundle. Supports the {case}, {serverId}, {bundleId}, " +
+			"{timestamp}, {hostname} and {seq} placeholders.",
+		DefaultValue: actions.DefaultNameTemplate,
+	},
+	streamFlag: components.BoolFlag{
+		Name: streamFlag,
+		Description: "Pipe the support bundle from the source Artifactory straight into the upload, " +
+			"without writing it to a local temp file.",
+	},
+	propertyFlag: components.StringFlag{
+		Name: propertyFlag,
+		Description: "Additional properties to attach to the uploaded support bundle, " +
+			"in the form of key1=value1;key2=value2.",
+	},
+	profileFlag: components.StringFlag{
+		Name: profileFlag,
+		Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+			"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+			"for example JFROG_SB_FLUNKY_SERVER_ID.",
+	},
+	unsetFlag: components.StringFlag{
+		Name:        unsetFlag,
+		Description: "Comma-separated names of the flags to remove from the profile.",
+	},
+}
+
+// getFlags gives the definitions of the named flags, in the given order.
+func getFlags(names ...string) []components.Flag {
+	flags := make([]components.Flag, 0, len(names))
+	for _, name := range names {
+		flags = append(flags, flagDefinitions[name])
+	}
+	return flags
+}
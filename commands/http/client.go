@@ -1,14 +1,23 @@
 package http
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
-	"github.com/jfrog/jfrog-client-go/artifactory"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/httpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os"
 )
 
 const (
@@ -17,13 +26,32 @@ const (
 	// HTTPContentTypeJSON is the header value for JSON Content-Type
 	HTTPContentTypeJSON = "application/json"
 	// HTTPContentTypeXML is the header value for XML Content-Type
-	HTTPContentTypeXML  = "application/xml"
+	HTTPContentTypeXML = "application/xml"
+	// HTTPRange is the HTTP header name for Range
+	HTTPRange = "Range"
+	// HTTPContentRange is the HTTP header name for Content-Range
+	HTTPContentRange = "Content-Range"
+	// HTTPAcceptRanges is the HTTP header name for Accept-Ranges
+	HTTPAcceptRanges = "Accept-Ranges"
+	// HTTPChecksumSha256 is the Artifactory header name for the SHA-256 checksum of a deployed artifact
+	HTTPChecksumSha256 = "X-Checksum-Sha256"
+	// HTTPChecksumSha1 is the Artifactory header name for the SHA-1 checksum of a deployed artifact
+	HTTPChecksumSha1 = "X-Checksum-Sha1"
+	// HTTPChecksumMd5 is the Artifactory header name for the MD5 checksum of a deployed artifact
+	HTTPChecksumMd5 = "X-Checksum"
+	// HTTPChecksumDeploy is the Artifactory header name requesting a deploy by checksum, without sending the content
+	HTTPChecksumDeploy  = "X-Checksum-Deploy"
 	undefinedStatusCode = -1
+	// maxRedirects is the number of redirects a request follows, as many as with the Go client.
+	maxRedirects = 10
 )
 
 // Client is a facade for interacting with a JFrog Artifactory service through REST calls.
 type Client struct {
 	RtDetails *config.ArtifactoryDetails
+	// Retry is the policy retrying the requests which failed due to a transient condition. Requests are attempted once
+	// with the zero policy.
+	Retry RetryPolicy
 }
 
 // GetURL gives the URL of the JFrog Artifactory service
@@ -32,80 +60,413 @@ func (c *Client) GetURL() string {
 }
 
 // CreateSupportBundle creates a Support Bundle.
-// nolint: bodyclose // Body is closed by ArtifactoryHttpClient
-func (c *Client) CreateSupportBundle(options SupportBundleCreationOptions) (status int, responseBytes []byte, err error) {
-	servicesManager, httpClientDetails, err := c.createArtifactoryServicesManager()
-	if err != nil {
-		return undefinedStatusCode, nil, err
-	}
-	httpClientDetails.Headers[HTTPContentType] = HTTPContentTypeJSON
+// nolint: bodyclose // Body is closed by send
+func (c *Client) CreateSupportBundle(ctx context.Context, options SupportBundleCreationOptions) (status int,
+	responseBytes []byte, err error) {
 	payload, err := json.Marshal(options)
 	if err != nil {
 		return undefinedStatusCode, nil, err
 	}
 	log.Debug(fmt.Sprintf("Sending %s", payload))
-	response, bytes, err := servicesManager.Client().SendPost(fmt.Sprintf("%sapi/system/support/bundle", c.GetURL()),
-		payload, &httpClientDetails)
-	if err != nil {
-		return undefinedStatusCode, nil, err
-	}
-	return response.StatusCode, bytes, nil
+	return statusAndBody(c.send(ctx, apiRequest{description: "Support Bundle creation", method: http.MethodPost,
+		url: c.CreateSupportBundleURL(), headers: map[string]string{HTTPContentType: HTTPContentTypeJSON},
+		content: bytesContent(payload), nonIdempotent: true}))
 }
 
 // DownloadSupportBundle downloads a Support Bundle. This returns the support bundle in the response.Body.
+// When offset is positive, only the content starting at offset is requested using a Range header.
 // Closing the body is the caller's responsibility.
-func (c *Client) DownloadSupportBundle(bundleID string) (*http.Response, error) {
-	servicesManager, httpClientDetails, err := c.createArtifactoryServicesManager()
-	if err != nil {
-		return nil, err
+func (c *Client) DownloadSupportBundle(ctx context.Context, bundleID string, offset int64) (*http.Response, error) {
+	headers := map[string]string{}
+	if offset > 0 {
+		headers[HTTPRange] = fmt.Sprintf("bytes=%d-", offset)
 	}
-	downloadSbURL := fmt.Sprintf("%sapi/system/support/bundle/%s/archive", c.GetURL(), bundleID)
-	resp, _, _, err := servicesManager.Client().Send("GET", downloadSbURL, nil, true, false, &httpClientDetails)
+	resp, _, err := c.send(ctx, apiRequest{description: "Support Bundle download", method: http.MethodGet,
+		url: c.SupportBundleArchiveURL(bundleID), headers: headers, keepBody: true})
 	return resp, err
 }
 
 // GetSupportBundleStatus gets the status of a Support Bundle creation process.
-// nolint: bodyclose // Body is closed by ArtifactoryHttpClient
-func (c *Client) GetSupportBundleStatus(bundleID string) (status int, responseBytes []byte, err error) {
-	servicesManager, httpClientDetails, err := c.createArtifactoryServicesManager()
+// nolint: bodyclose // Body is closed by send
+func (c *Client) GetSupportBundleStatus(ctx context.Context, bundleID string) (status int, responseBytes []byte,
+	err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Support Bundle status", method: http.MethodGet,
+		url: c.SupportBundleURL(bundleID)}))
+}
+
+// ListSupportBundles lists the Support Bundles available on the Artifactory service.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) ListSupportBundles(ctx context.Context) (status int, responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Support Bundle list", method: http.MethodGet,
+		url: fmt.Sprintf("%sapi/system/support/bundles", c.GetURL())}))
+}
+
+// DeleteSupportBundle deletes a Support Bundle.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) DeleteSupportBundle(ctx context.Context, bundleID string) (status int, responseBytes []byte,
+	err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Support Bundle deletion", method: http.MethodDelete,
+		url: c.SupportBundleURL(bundleID)}))
+}
+
+// UploadSupportBundle uploads a Support Bundle with the given properties. The given checksums are sent along, so that
+// Artifactory rejects an archive which does not match them.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) UploadSupportBundle(ctx context.Context, sbFilePath string, repoKey string,
+	supportCaseDirectory string, filename string, checksums Checksums, properties *servicesutils.Properties) (
+	status int, responseBytes []byte, err error) {
+	headers := map[string]string{}
+	setChecksumHeaders(headers, checksums)
+	return statusAndBody(c.send(ctx, apiRequest{description: "Support Bundle upload", method: http.MethodPut,
+		url: c.UploadURL(repoKey, supportCaseDirectory, filename, properties), headers: headers,
+		content: fileContent(sbFilePath)}))
+}
+
+// GetVersion gets the version details of the Artifactory service.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) GetVersion(ctx context.Context) (status int, responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Version request", method: http.MethodGet,
+		url: c.VersionURL()}))
+}
+
+// DeploySupportBundleChecksum deploys a Support Bundle by checksum, which only succeeds when Artifactory already stores
+// content with the same checksums. No content is sent.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) DeploySupportBundleChecksum(ctx context.Context, repoKey string, supportCaseDirectory string,
+	filename string, checksums Checksums, properties *servicesutils.Properties) (status int, responseBytes []byte,
+	err error) {
+	headers := map[string]string{HTTPChecksumDeploy: "true"}
+	setChecksumHeaders(headers, checksums)
+	return statusAndBody(c.send(ctx, apiRequest{description: "Checksum deploy", method: http.MethodPut,
+		url: c.UploadURL(repoKey, supportCaseDirectory, filename, properties), headers: headers}))
+}
+
+// SearchSupportBundlesByChecksum searches the artifacts of a repository having the given SHA-256 checksum.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) SearchSupportBundlesByChecksum(ctx context.Context, repoKey string, sha256 string) (status int,
+	responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Checksum search", method: http.MethodGet,
+		url: c.ChecksumSearchURL(repoKey, sha256)}))
+}
+
+// GetSupportCaseFolder gets the folder info of a case folder, listing the Support Bundles uploaded for a case.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) GetSupportCaseFolder(ctx context.Context, repoKey string, supportCaseDirectory string) (status int,
+	responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Case folder request", method: http.MethodGet,
+		url: c.SupportCaseFolderURL(repoKey, supportCaseDirectory)}))
+}
+
+// UploadSupportBundleStream uploads a Support Bundle read from content, without going through a local file.
+// The size is the number of bytes content will provide, or -1 when unknown. As content can only be read once, the
+// upload is not retried, whatever the retry policy of the client.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) UploadSupportBundleStream(ctx context.Context, content io.Reader, size int64, repoKey string,
+	supportCaseDirectory string, filename string, properties *servicesutils.Properties) (status int,
+	responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Support Bundle upload", method: http.MethodPut,
+		url: c.UploadURL(repoKey, supportCaseDirectory, filename, properties), once: true,
+		content: func() (io.Reader, int64, error) { return content, size, nil }}))
+}
+
+// DeleteUploadedSupportBundle deletes a Support Bundle uploaded to the case folder of a repository.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) DeleteUploadedSupportBundle(ctx context.Context, repoKey string, supportCaseDirectory string,
+	filename string) (status int, responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "uploaded Support Bundle deletion",
+		method: http.MethodDelete, url: fmt.Sprintf("%s%s/%s/%s", c.GetURL(), repoKey, supportCaseDirectory, filename)}))
+}
+
+// Ping checks that the Artifactory service is up and reachable.
+// nolint: bodyclose // Body is closed by send
+func (c *Client) Ping(ctx context.Context) (status int, responseBytes []byte, err error) {
+	return statusAndBody(c.send(ctx, apiRequest{description: "Ping", method: http.MethodGet, url: c.PingURL()}))
+}
+
+// UploadURL gives the URL where a Support Bundle is deployed, with its properties given as matrix parameters.
+func (c *Client) UploadURL(repoKey string, supportCaseDirectory string, filename string,
+	properties *servicesutils.Properties) string {
+	uploadURL := fmt.Sprintf("%s%s/%s/%s;uploadedBy=support-bundle-flunky", c.RtDetails.Url, repoKey,
+		supportCaseDirectory, filename)
+	if properties != nil && len(properties.Properties) > 0 {
+		uploadURL += ";" + properties.ToEncodedString()
+	}
+	return uploadURL
+}
+
+// CreateSupportBundleURL gives the URL of the Support Bundle creation endpoint.
+func (c *Client) CreateSupportBundleURL() string {
+	return fmt.Sprintf("%sapi/system/support/bundle", c.GetURL())
+}
+
+// SupportBundleURL gives the URL of a Support Bundle, to get its status or to delete it.
+func (c *Client) SupportBundleURL(bundleID string) string {
+	return fmt.Sprintf("%sapi/system/support/bundle/%s", c.GetURL(), bundleID)
+}
+
+// SupportBundleArchiveURL gives the URL of the archive of a Support Bundle.
+func (c *Client) SupportBundleArchiveURL(bundleID string) string {
+	return fmt.Sprintf("%sapi/system/support/bundle/%s/archive", c.GetURL(), bundleID)
+}
+
+// VersionURL gives the URL of the version endpoint, called by the JFrog client to get the version of the service.
+func (c *Client) VersionURL() string {
+	return fmt.Sprintf("%sapi/system/version", c.GetURL())
+}
+
+// PingURL gives the URL of the ping endpoint.
+func (c *Client) PingURL() string {
+	return fmt.Sprintf("%sapi/system/ping", c.GetURL())
+}
+
+// ChecksumSearchURL gives the URL searching the artifacts of a repository having the given SHA-256 checksum.
+func (c *Client) ChecksumSearchURL(repoKey string, sha256 string) string {
+	return fmt.Sprintf("%sapi/search/checksum?sha256=%s&repos=%s", c.GetURL(), url.QueryEscape(sha256),
+		url.QueryEscape(repoKey))
+}
+
+// SupportCaseFolderURL gives the URL of the folder info of a case folder.
+func (c *Client) SupportCaseFolderURL(repoKey string, supportCaseDirectory string) string {
+	return fmt.Sprintf("%sapi/storage/%s/%s", c.GetURL(), repoKey, supportCaseDirectory)
+}
+
+func setChecksumHeaders(headers map[string]string, checksums Checksums) {
+	if checksums.SHA256 != "" {
+		headers[HTTPChecksumSha256] = checksums.SHA256
+	}
+	if checksums.SHA1 != "" {
+		headers[HTTPChecksumSha1] = checksums.SHA1
+	}
+	if checksums.MD5 != "" {
+		headers[HTTPChecksumMd5] = checksums.MD5
+	}
+}
+
+// setAuthentication sets the credentials of httpClientDetails on req, the same way the JFrog HTTP client does.
+func setAuthentication(req *http.Request, httpClientDetails httputils.HttpClientDetails) {
+	switch {
+	case httpClientDetails.ApiKey != "" && httpClientDetails.User != "":
+		req.SetBasicAuth(httpClientDetails.User, httpClientDetails.ApiKey)
+	case httpClientDetails.ApiKey != "":
+		req.Header.Set("X-JFrog-Art-Api", httpClientDetails.ApiKey)
+	case httpClientDetails.AccessToken != "" && httpClientDetails.User != "":
+		req.SetBasicAuth(httpClientDetails.User, httpClientDetails.AccessToken)
+	case httpClientDetails.AccessToken != "":
+		req.Header.Set("Authorization", "Bearer "+httpClientDetails.AccessToken)
+	case httpClientDetails.Password != "":
+		req.SetBasicAuth(httpClientDetails.User, httpClientDetails.Password)
+	}
+}
+
+// apiRequest is a request to the REST API or to the repositories of an Artifactory service.
+type apiRequest struct {
+	// description names the request in the logs, such as "Support Bundle upload".
+	description string
+	method      string
+	url         string
+	headers     map[string]string
+	// content gives the body of the request and its size, or -1 when unknown. It is called again for each attempt and
+	// each redirect. The request has no body when it is nil.
+	content func() (io.Reader, int64, error)
+	// keepBody leaves the body of the response open, to be read and closed by the caller.
+	keepBody bool
+	// once is set when the request cannot be attempted again, such as when its content can only be read once.
+	once bool
+	// nonIdempotent is set when the request must not be processed twice, such as a creation. It is only attempted
+	// again when it was not processed.
+	nonIdempotent bool
+	// redirects is the number of redirects followed so far by sendOnce.
+	redirects int
+}
+
+// send sends a request with the credentials and the TLS settings of the JFrog CLI configuration, and retries it
+// according to the retry policy of the client. Unlike the requests of the JFrog client, it is aborted as soon as ctx
+// is done. The body of the response is read and closed, unless the request keeps it for the caller.
+func (c *Client) send(ctx context.Context, r apiRequest) (*http.Response, []byte, error) {
+	serviceDetails, httpClient, err := c.createHTTPClient()
 	if err != nil {
-		return undefinedStatusCode, nil, err
+		return nil, nil, err
+	}
+	policy := c.Retry
+	if r.once {
+		policy = RetryPolicy{}
 	}
-	sbStatusURL := fmt.Sprintf("%sapi/system/support/bundle/%s", c.GetURL(), bundleID)
-	resp, responseBytes, _, err := servicesManager.Client().SendGet(sbStatusURL, true, &httpClientDetails)
+	do := policy.Do
+	if r.nonIdempotent {
+		do = policy.DoNonIdempotent
+	}
+	return do(ctx, r.description, func() (*http.Response, []byte, error) {
+		return sendOnce(ctx, httpClient, serviceDetails, r)
+	})
+}
+
+// sendOnce makes a single attempt of a request.
+func sendOnce(ctx context.Context, httpClient *http.Client, serviceDetails auth.ServiceDetails, r apiRequest) (
+	*http.Response, []byte, error) {
+	httpClientDetails := serviceDetails.CreateHttpClientDetails()
+	// The access token is refreshed when needed, as the JFrog client does before each request
+	if err := serviceDetails.RunPreRequestInterceptors(&httpClientDetails); err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, nil)
 	if err != nil {
-		return undefinedStatusCode, nil, err
+		return nil, nil, err
+	}
+	if err = setContent(req, r); err != nil {
+		return nil, nil, err
 	}
-	return resp.StatusCode, responseBytes, nil
+	for name, value := range httpClientDetails.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range r.headers {
+		req.Header.Set(name, value)
+	}
+	setAuthentication(req, httpClientDetails)
+	req.Header.Set("User-Agent", clientutils.GetUserAgent())
+
+	resp, err := httpClient.Do(req)
+	if err == nil && isMethodChangingRedirect(resp, r.method) {
+		return redirect(ctx, httpClient, serviceDetails, r, resp)
+	}
+	if err != nil || r.keepBody {
+		return resp, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
 }
 
-// UploadSupportBundle uploads a Support Bundle.
-// nolint: bodyclose // Body is closed by ArtifactoryHttpClient
-func (c *Client) UploadSupportBundle(sbFilePath string, repoKey string, supportCaseDirectory string,
-	filename string) (status int, responseBytes []byte, err error) {
-	// TODO add flag for number of retries
-	const retries = 5
-	servicesManager, httpClientDetails, err := c.createArtifactoryServicesManager()
+// isMethodChangingRedirect tells whether a response is a redirect which the Go client does not follow with the method
+// of the request.
+func isMethodChangingRedirect(resp *http.Response, method string) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// redirect sends a request again to the location of a redirect, with the same method and content.
+func redirect(ctx context.Context, httpClient *http.Client, serviceDetails auth.ServiceDetails, r apiRequest,
+	resp *http.Response) (*http.Response, []byte, error) {
+	defer func() { _ = resp.Body.Close() }()
+	if r.once {
+		return nil, nil, fmt.Errorf("%s cannot follow the redirect to %s, as its content can only be sent once",
+			r.description, resp.Header.Get("Location"))
+	}
+	if r.redirects >= maxRedirects {
+		return nil, nil, fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	location, err := resp.Location()
 	if err != nil {
-		return undefinedStatusCode, nil, err
+		return nil, nil, err
 	}
+	log.Debug(fmt.Sprintf("HTTP redirecting %s to %s", r.method, location))
+	r.url = location.String()
+	r.redirects++
+	return sendOnce(ctx, httpClient, serviceDetails, r)
+}
 
-	url := fmt.Sprintf("%s%s/%s/%s;uploadedBy=support-bundle-flunky", c.RtDetails.Url, repoKey, supportCaseDirectory,
-		filename)
-	resp, body, err := servicesManager.Client().UploadFile(sbFilePath, url, "",
-		&httpClientDetails, retries, nil)
+// setContent sets the body of a request. Unless the request is sent once, the body can be given again to follow a
+// redirect.
+func setContent(req *http.Request, r apiRequest) error {
+	if r.content == nil {
+		return nil
+	}
+	content, size, err := r.content()
+	if err != nil {
+		return err
+	}
+	req.Body, req.ContentLength = toReadCloser(content, size), size
+	if !r.once {
+		req.GetBody = func() (io.ReadCloser, error) {
+			again, againSize, againErr := r.content()
+			return toReadCloser(again, againSize), againErr
+		}
+	}
+	return nil
+}
+
+// toReadCloser gives the body of a request with the given content, closing content when it is a file.
+func toReadCloser(content io.Reader, size int64) io.ReadCloser {
+	if size == 0 {
+		return http.NoBody
+	}
+	if readCloser, ok := content.(io.ReadCloser); ok {
+		return readCloser
+	}
+	return ioutil.NopCloser(content)
+}
+
+// bytesContent gives the content of a request sending payload.
+func bytesContent(payload []byte) func() (io.Reader, int64, error) {
+	return func() (io.Reader, int64, error) {
+		return bytes.NewReader(payload), int64(len(payload)), nil
+	}
+}
+
+// fileContent gives the content of a request sending a file, opened again for each attempt.
+func fileContent(path string) func() (io.Reader, int64, error) {
+	return func() (io.Reader, int64, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	}
+}
+
+// statusAndBody gives the status and the body of the response of a request which has been read, or the error of the
+// request.
+func statusAndBody(resp *http.Response, body []byte, err error) (int, []byte, error) {
 	if err != nil {
 		return undefinedStatusCode, nil, err
 	}
-	return resp.StatusCode, body, err
+	return resp.StatusCode, body, nil
 }
 
-func (c *Client) createArtifactoryServicesManager() (artifactory.ArtifactoryServicesManager,
-	httputils.HttpClientDetails, error) {
+// createHTTPClient gives the details of the service as configured in JFrog CLI, and a client trusting the same
+// certificates as the JFrog client.
+func (c *Client) createHTTPClient() (auth.ServiceDetails, *http.Client, error) {
 	servicesManager, err := utils.CreateServiceManager(c.RtDetails, false)
 	if err != nil {
-		return nil, httputils.HttpClientDetails{}, err
+		return nil, nil, err
+	}
+	serviceConfig := servicesManager.GetConfig()
+	serviceDetails := serviceConfig.GetServiceDetails()
+	jfrogHTTPClient, err := httpclient.ClientBuilder().
+		SetCertificatesPath(serviceConfig.GetCertificatesPath()).
+		SetInsecureTls(serviceConfig.IsInsecureTls()).
+		SetClientCertPath(serviceDetails.GetClientCertPath()).
+		SetClientCertKeyPath(serviceDetails.GetClientCertKeyPath()).
+		Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	httpClient := jfrogHTTPClient.Client
+	httpClient.CheckRedirect = keepMethodOnRedirect
+	return serviceDetails, httpClient, nil
+}
+
+// keepMethodOnRedirect stops following a redirect which would change the method of a request, such as a 302
+// answering a POST, so that sendOnce sends the request again with its method as the JFrog client does.
+func keepMethodOnRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.Method != via[0].Method {
+		return http.ErrUseLastResponse
 	}
-	httpClientDetails := servicesManager.GetConfig().GetServiceDetails().CreateHttpClientDetails()
-	return servicesManager, httpClientDetails, nil
+	return nil
 }
@@ -7,30 +7,46 @@ import (
 
 // SupportBundleCreationOptions defines options for the creation of a Support Bundle.
 type SupportBundleCreationOptions struct {
-	Name        string                   `json:"name"`
-	Description string                   `json:"description"`
-	Parameters  *SupportBundleParameters `json:"parameters"`
+	Name        string                   `json:"name" yaml:"name"`
+	Description string                   `json:"description" yaml:"description"`
+	Parameters  *SupportBundleParameters `json:"parameters" yaml:"parameters"`
 }
 
 // SupportBundleParameters defines the content of a Support Bundle.
 type SupportBundleParameters struct {
-	Configuration bool                               `json:"configuration"`
-	Logs          *SupportBundleParametersLogs       `json:"logs"`
-	System        bool                               `json:"system"`
-	ThreadDump    *SupportBundleParametersThreadDump `json:"thread_dump"`
+	Configuration bool                               `json:"configuration" yaml:"configuration"`
+	Logs          *SupportBundleParametersLogs       `json:"logs" yaml:"logs"`
+	System        bool                               `json:"system" yaml:"system"`
+	ThreadDump    *SupportBundleParametersThreadDump `json:"thread_dump" yaml:"thread_dump"`
 }
 
 // SupportBundleParametersLogs defines which logs are included in a Support Bundle.
 type SupportBundleParametersLogs struct {
-	Include   bool   `json:"include"`
-	StartDate string `json:"start_date"`
-	EndDate   string `json:"end_date"`
+	Include   bool   `json:"include" yaml:"include"`
+	StartDate string `json:"start_date" yaml:"start_date"`
+	EndDate   string `json:"end_date" yaml:"end_date"`
 }
 
 // SupportBundleParametersThreadDump defines which thread dumps are included in a Support Bundle.
 type SupportBundleParametersThreadDump struct {
-	Count    uint `json:"count"`
-	Interval uint `json:"interval"`
+	Count    uint `json:"count" yaml:"count"`
+	Interval uint `json:"interval" yaml:"interval"`
+}
+
+// SupportBundleList is the list of Support Bundles available on an Artifactory service.
+type SupportBundleList struct {
+	Count   int                    `json:"count"`
+	Bundles []SupportBundleDetails `json:"bundles"`
+}
+
+// SupportBundleDetails describes a Support Bundle available on an Artifactory service.
+type SupportBundleDetails struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Created     string `json:"created" yaml:"created"`
+	Status      string `json:"status,omitempty" yaml:"status,omitempty"`
+	Size        int64  `json:"size,omitempty" yaml:"size,omitempty"`
 }
 
 // MarshalJSON serializes a SupportBundleCreationOptions to JSON.
@@ -43,6 +59,52 @@ func (p SupportBundleCreationOptions) MarshalJSON() ([]byte, error) {
 		}
 		params = string(paramsAsBytes)
 	}
-	asJSON := fmt.Sprintf(`{"name":"%s","description":"%s","parameters":%s}`, p.Name, p.Description, params)
+	name, err := json.Marshal(p.Name)
+	if err != nil {
+		return nil, err
+	}
+	description, err := json.Marshal(p.Description)
+	if err != nil {
+		return nil, err
+	}
+	asJSON := fmt.Sprintf(`{"name":%s,"description":%s,"parameters":%s}`, name, description, params)
 	return []byte(asJSON), nil
 }
+
+// Checksums holds the hex encoded checksums of a Support Bundle archive.
+type Checksums struct {
+	SHA256 string `json:"sha256" yaml:"sha256"`
+	SHA1   string `json:"sha1" yaml:"sha1"`
+	MD5    string `json:"md5" yaml:"md5"`
+}
+
+// UploadResponse is the part of the Artifactory deploy response used to verify an upload.
+type UploadResponse struct {
+	Repo        string    `json:"repo"`
+	Path        string    `json:"path"`
+	DownloadURI string    `json:"downloadUri"`
+	Checksums   Checksums `json:"checksums"`
+}
+
+// ChecksumSearchResult is the result of an Artifactory checksum search.
+type ChecksumSearchResult struct {
+	Results []ChecksumSearchResultItem `json:"results"`
+}
+
+// ChecksumSearchResultItem is an artifact found by an Artifactory checksum search.
+type ChecksumSearchResultItem struct {
+	// URI is the storage API URI of the artifact, for example https://acme.jfrog.io/artifactory/api/storage/logs/1234/SB.zip
+	URI string `json:"uri"`
+}
+
+// FolderInfo is the part of the Artifactory folder info used to list the files of a case folder.
+type FolderInfo struct {
+	Children []FolderInfoChild `json:"children"`
+}
+
+// FolderInfoChild is an item of a folder.
+type FolderInfoChild struct {
+	// URI is the path of the item relative to the folder, for example /SB-20201201-100000Z.zip
+	URI    string `json:"uri"`
+	Folder bool   `json:"folder"`
+}
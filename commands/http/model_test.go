@@ -43,6 +43,14 @@ func Test_MarshallJSON(t *testing.T) {
 			expect: `{"name":"n","description":"d","parameters":{"configuration":true,"logs":` +
 				`{"include":false,"start_date":"s","end_date":"e"},"system":false,"thread_dump":{"count":1,"interval":2}}}`,
 		},
+		{
+			name: "Escaped description",
+			input: SupportBundleCreationOptions{
+				Name:        "n",
+				Description: `Startup "failure"`,
+			},
+			expect: `{"name":"n","description":"Startup \"failure\"","parameters":{}}`,
+		},
 		{
 			name: "Nil logs and threaddump",
 			input: SupportBundleCreationOptions{
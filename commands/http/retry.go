@@ -0,0 +1,224 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts of a request when not configured otherwise.
+	DefaultMaxAttempts = 5
+	// DefaultRetryInterval is the wait before the first retry when not configured otherwise.
+	DefaultRetryInterval = 5 * time.Second
+	// DefaultMaxRetryInterval is the cap of the wait between two attempts when not configured otherwise.
+	DefaultMaxRetryInterval = time.Minute
+	// DefaultRetryJitter is the fraction of the wait between two attempts which is randomly removed.
+	DefaultRetryJitter = 0.2
+	// HTTPRetryAfter is the HTTP header name for Retry-After
+	HTTPRetryAfter = "Retry-After"
+)
+
+// RetryPolicy tells how many times and how long apart the requests to Artifactory are attempted.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of a request, including the first one. A request is attempted once when
+	// it is not positive.
+	MaxAttempts int
+	// Interval is the wait before the first retry, doubled before each next one.
+	Interval time.Duration
+	// MaxInterval caps the wait between two attempts, including the one asked by Artifactory with Retry-After. The wait
+	// is not capped when it is not positive.
+	MaxInterval time.Duration
+	// Jitter is the fraction of the wait which is randomly removed, between 0 and 1, so that the clients failing at the
+	// same time do not retry all at once.
+	Jitter float64
+}
+
+// DefaultRetryPolicy gives the policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		Interval:    DefaultRetryInterval,
+		MaxInterval: DefaultMaxRetryInterval,
+		Jitter:      DefaultRetryJitter,
+	}
+}
+
+// Attempts gives the number of attempts of a request, at least one.
+func (p RetryPolicy) Attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff gives the wait before the next attempt once the given attempt, starting at 1, failed. A positive retryAfter
+// is the wait asked by Artifactory, which is honoured up to MaxInterval.
+func (p RetryPolicy) Backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return p.capped(retryAfter)
+	}
+	wait := p.Interval
+	for i := 1; i < attempt && wait > 0 && (p.MaxInterval <= 0 || wait < p.MaxInterval); i++ {
+		wait *= 2
+	}
+	wait = p.capped(wait)
+	// nolint: gosec // The jitter does not need a cryptographically secure random number
+	return wait - time.Duration(float64(wait)*p.Jitter*rand.Float64())
+}
+
+func (p RetryPolicy) capped(wait time.Duration) time.Duration {
+	if p.MaxInterval > 0 && wait > p.MaxInterval {
+		return p.MaxInterval
+	}
+	return wait
+}
+
+// Do calls send until it succeeds, fails with an error which cannot be retried, or the attempts are exhausted. It gives
+// the result of the last call. A response whose status may be different later is retried, after the wait asked with
+// Retry-After if any, and its body is closed first. The wait is aborted with the error of ctx once it is done.
+func (p RetryPolicy) Do(ctx context.Context, description string, send func() (*http.Response, []byte, error)) (
+	*http.Response, []byte, error) {
+	return p.do(ctx, description, shouldRetry, send)
+}
+
+// DoNonIdempotent is Do for a request which must not be processed twice, such as a creation. As a gateway error, a
+// reset connection or a timeout may happen once Artifactory processed the request, it is only retried when it was
+// not processed: rejected with a 429 or a 503, or never sent as the connection could not be established.
+func (p RetryPolicy) DoNonIdempotent(ctx context.Context, description string,
+	send func() (*http.Response, []byte, error)) (*http.Response, []byte, error) {
+	return p.do(ctx, description, shouldRetryNonIdempotent, send)
+}
+
+func (p RetryPolicy) do(ctx context.Context, description string,
+	shouldRetry func(resp *http.Response, err error) (bool, string), send func() (*http.Response, []byte, error)) (
+	*http.Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		resp, body, err := send()
+		retry, reason := shouldRetry(resp, err)
+		if !retry || attempt >= p.Attempts() {
+			return resp, body, err
+		}
+		wait := p.Backoff(attempt, getRetryAfter(resp, time.Now()))
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		log.Warn(fmt.Sprintf("%s failed (attempt %d of %d), retrying in %s: %s", description, attempt, p.Attempts(),
+			wait.Round(time.Millisecond), reason))
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("%s aborted while waiting to retry: %w", description, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// shouldRetry tells whether a request may succeed when attempted again, and why it failed.
+func shouldRetry(resp *http.Response, err error) (bool, string) {
+	if err != nil {
+		return IsRetryableError(err), err.Error()
+	}
+	if resp == nil {
+		return false, ""
+	}
+	return IsRetryableStatus(resp.StatusCode), statusReason(resp)
+}
+
+// shouldRetryNonIdempotent tells whether a request which must not be processed twice may be attempted again, as it was
+// not processed, and why it failed.
+func shouldRetryNonIdempotent(resp *http.Response, err error) (bool, string) {
+	if err != nil {
+		return IsUnsentError(err), err.Error()
+	}
+	if resp == nil {
+		return false, ""
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable,
+		statusReason(resp)
+}
+
+func statusReason(resp *http.Response) string {
+	return fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+}
+
+// IsRetryableStatus tells whether a response status is due to a transient condition, such as a node failing over
+// behind a load balancer or a rate limit.
+func IsRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryableError tells whether a request failing with err may succeed when attempted again: network failures and
+// timeouts may, invalid URLs, untrusted certificates, unknown hosts and cancelled requests may not.
+func IsRetryableError(err error) bool {
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certificateErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, &unknownAuthorityErr), errors.As(err, &certificateErr), errors.As(err, &hostnameErr):
+		return false
+	case errors.As(err, &dnsErr):
+		return !dnsErr.IsNotFound
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF), errors.As(err, &opErr):
+		return true
+	default:
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+}
+
+// IsUnsentError tells whether a request failing with err was never sent, as the connection to Artifactory could not
+// be established: a refused connection, a connection timeout or a temporary DNS failure. A request failing once the
+// connection was established, such as with a reset connection, may have been processed.
+func IsUnsentError(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, &dnsErr):
+		return !dnsErr.IsNotFound
+	case errors.As(err, &opErr):
+		return opErr.Op == "dial" || opErr.Op == "proxyconnect"
+	default:
+		return false
+	}
+}
+
+// getRetryAfter gives the wait asked with the Retry-After header of a 429 or 503 response, in seconds or as an HTTP
+// date, or 0 when there is none.
+func getRetryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests &&
+		resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0
+	}
+	value := strings.TrimSpace(resp.Header.Get(HTTPRetryAfter))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,368 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{Interval: time.Second, MaxInterval: 10 * time.Second}
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		attempt    int
+		retryAfter time.Duration
+		expected   time.Duration
+	}{
+		{name: "first retry", policy: policy, attempt: 1, expected: time.Second},
+		{name: "doubled", policy: policy, attempt: 3, expected: 4 * time.Second},
+		{name: "capped", policy: policy, attempt: 5, expected: 10 * time.Second},
+		{name: "not capped", policy: RetryPolicy{Interval: time.Second}, attempt: 5, expected: 16 * time.Second},
+		{name: "retry after", policy: policy, attempt: 1, retryAfter: 3 * time.Second, expected: 3 * time.Second},
+		{name: "retry after capped", policy: policy, attempt: 1, retryAfter: time.Hour, expected: 10 * time.Second},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.policy.Backoff(test.attempt, test.retryAfter))
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff_Jitter(t *testing.T) {
+	policy := RetryPolicy{Interval: time.Second, MaxInterval: 10 * time.Second, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		backoff := policy.Backoff(5, 0)
+		assert.True(t, backoff > 8*time.Second && backoff <= 10*time.Second, backoff)
+	}
+	assert.Equal(t, 3*time.Second, policy.Backoff(1, 3*time.Second), "Retry-After is not randomized")
+}
+
+type attempt struct {
+	status     int
+	retryAfter string
+	err        error
+}
+
+type bodyCloseRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (b *bodyCloseRecorder) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	policy := RetryPolicy{MaxAttempts: 3, Interval: time.Millisecond}
+	tests := []struct {
+		name           string
+		policy         RetryPolicy
+		attempts       []attempt
+		expectAttempts int
+		expectStatus   int
+		expectErr      error
+	}{
+		{
+			name:           "success",
+			policy:         policy,
+			attempts:       []attempt{{status: http.StatusOK}},
+			expectAttempts: 1,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:   "unavailable during a failover",
+			policy: policy,
+			attempts: []attempt{{status: http.StatusServiceUnavailable}, {status: http.StatusBadGateway},
+				{status: http.StatusOK}},
+			expectAttempts: 3,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "rate limited",
+			policy:         policy,
+			attempts:       []attempt{{status: http.StatusTooManyRequests, retryAfter: "0"}, {status: http.StatusOK}},
+			expectAttempts: 2,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "attempts exhausted",
+			policy:         policy,
+			attempts:       []attempt{{status: http.StatusServiceUnavailable}},
+			expectAttempts: 3,
+			expectStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:           "not retried",
+			policy:         policy,
+			attempts:       []attempt{{status: http.StatusNotFound}},
+			expectAttempts: 1,
+			expectStatus:   http.StatusNotFound,
+		},
+		{
+			name:           "connection refused",
+			policy:         policy,
+			attempts:       []attempt{{err: refused}, {status: http.StatusOK}},
+			expectAttempts: 2,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "connection refused until exhausted",
+			policy:         policy,
+			attempts:       []attempt{{err: refused}},
+			expectAttempts: 3,
+			expectErr:      refused,
+		},
+		{
+			name:           "zero policy",
+			attempts:       []attempt{{status: http.StatusServiceUnavailable}},
+			expectAttempts: 1,
+			expectStatus:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			var bodies []*bodyCloseRecorder
+			resp, _, err := test.policy.Do(context.Background(), "Test request", func() (*http.Response, []byte, error) {
+				a := test.attempts[0]
+				if len(test.attempts) > 1 {
+					test.attempts = test.attempts[1:]
+				}
+				calls++
+				if a.err != nil {
+					return nil, nil, a.err
+				}
+				bodies = append(bodies, &bodyCloseRecorder{Reader: strings.NewReader("")})
+				header := http.Header{}
+				if a.retryAfter != "" {
+					header.Set(HTTPRetryAfter, a.retryAfter)
+				}
+				return &http.Response{StatusCode: a.status, Header: header, Body: bodies[len(bodies)-1]}, nil, nil
+			})
+			assert.Equal(t, test.expectAttempts, calls)
+			if test.expectErr != nil {
+				assert.Equal(t, test.expectErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+			for _, body := range bodies[:len(bodies)-1] {
+				assert.True(t, body.closed, "the body of a retried response is closed")
+			}
+			assert.False(t, bodies[len(bodies)-1].closed, "the body of the last response is left to the caller")
+		})
+	}
+}
+
+func TestRetryPolicy_Do_Cancelled(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Interval: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	calls := 0
+	start := time.Now()
+
+	_, _, err := policy.Do(ctx, "Test request", func() (*http.Response, []byte, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader(""))},
+			nil, nil
+	})
+
+	assert.EqualError(t, err, "Test request aborted while waiting to retry: context deadline exceeded")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, calls)
+	assert.Less(t, int64(time.Since(start)), int64(time.Minute), "the wait is aborted")
+}
+
+func TestRetryPolicy_DoNonIdempotent(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	policy := RetryPolicy{MaxAttempts: 3, Interval: time.Millisecond}
+	tests := []struct {
+		name           string
+		attempts       []attempt
+		expectAttempts int
+		expectStatus   int
+		expectErr      error
+	}{
+		{
+			name:           "unavailable",
+			attempts:       []attempt{{status: http.StatusServiceUnavailable}, {status: http.StatusOK}},
+			expectAttempts: 2,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "rate limited",
+			attempts:       []attempt{{status: http.StatusTooManyRequests, retryAfter: "0"}, {status: http.StatusOK}},
+			expectAttempts: 2,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "bad gateway",
+			attempts:       []attempt{{status: http.StatusBadGateway}, {status: http.StatusOK}},
+			expectAttempts: 1,
+			expectStatus:   http.StatusBadGateway,
+		},
+		{
+			name:           "gateway timeout",
+			attempts:       []attempt{{status: http.StatusGatewayTimeout}, {status: http.StatusOK}},
+			expectAttempts: 1,
+			expectStatus:   http.StatusGatewayTimeout,
+		},
+		{
+			name:           "connection refused",
+			attempts:       []attempt{{err: refused}, {status: http.StatusOK}},
+			expectAttempts: 2,
+			expectStatus:   http.StatusOK,
+		},
+		{
+			name:           "connection reset",
+			attempts:       []attempt{{err: reset}, {status: http.StatusOK}},
+			expectAttempts: 1,
+			expectErr:      reset,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			resp, _, err := policy.DoNonIdempotent(context.Background(), "Test creation",
+				func() (*http.Response, []byte, error) {
+					a := test.attempts[calls]
+					calls++
+					if a.err != nil {
+						return nil, nil, a.err
+					}
+					header := http.Header{}
+					if a.retryAfter != "" {
+						header.Set(HTTPRetryAfter, a.retryAfter)
+					}
+					return &http.Response{StatusCode: a.status, Header: header,
+						Body: ioutil.NopCloser(strings.NewReader(""))}, nil, nil
+				})
+			assert.Equal(t, test.expectAttempts, calls)
+			if test.expectErr != nil {
+				assert.Equal(t, test.expectErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, expected: true},
+		{name: "connection reset", err: &url.Error{Op: "Get", URL: "http://rt",
+			Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, expected: true},
+		{name: "unexpected EOF", err: fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), expected: true},
+		{name: "closed connection", err: &url.Error{Op: "Get", URL: "http://rt", Err: io.EOF}, expected: true},
+		{name: "timeout", err: &url.Error{Op: "Get", URL: "http://rt", Err: timeoutError{}}, expected: true},
+		{name: "temporary DNS failure", err: &net.DNSError{Name: "rt", IsTemporary: true}, expected: true},
+		{name: "unknown host", err: &net.DNSError{Name: "rt", IsNotFound: true}, expected: false},
+		{name: "untrusted certificate", err: &url.Error{Op: "Get", URL: "https://rt",
+			Err: x509.UnknownAuthorityError{}}, expected: false},
+		{name: "wrong hostname", err: &url.Error{Op: "Get", URL: "https://rt", Err: x509.HostnameError{Host: "rt"}},
+			expected: false},
+		{name: "cancelled", err: &url.Error{Op: "Get", URL: "http://rt", Err: context.Canceled}, expected: false},
+		{name: "invalid URL", err: &url.Error{Op: "parse", URL: ":rt", Err: errors.New("missing protocol scheme")},
+			expected: false},
+		{name: "other", err: errors.New("file not found"), expected: false},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsRetryableError(test.err))
+		})
+	}
+}
+
+func TestIsUnsentError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "connection refused", err: &url.Error{Op: "Post", URL: "http://rt",
+			Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, expected: true},
+		{name: "connection timeout", err: &net.OpError{Op: "dial", Err: timeoutError{}}, expected: true},
+		{name: "proxy refused", err: &net.OpError{Op: "proxyconnect", Err: syscall.ECONNREFUSED}, expected: true},
+		{name: "temporary DNS failure", err: &net.OpError{Op: "dial",
+			Err: &net.DNSError{Name: "rt", IsTemporary: true}}, expected: true},
+		{name: "unknown host", err: &net.OpError{Op: "dial", Err: &net.DNSError{Name: "rt", IsNotFound: true}},
+			expected: false},
+		{name: "connection reset", err: &url.Error{Op: "Post", URL: "http://rt",
+			Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, expected: false},
+		{name: "closed connection", err: &url.Error{Op: "Post", URL: "http://rt", Err: io.EOF}, expected: false},
+		{name: "response timeout", err: &url.Error{Op: "Post", URL: "http://rt", Err: timeoutError{}}, expected: false},
+		{name: "cancelled", err: &url.Error{Op: "Post", URL: "http://rt", Err: context.Canceled}, expected: false},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsUnsentError(test.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func Test_getRetryAfter(t *testing.T) {
+	now := time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{name: "seconds", status: http.StatusServiceUnavailable, retryAfter: "120", expected: 2 * time.Minute},
+		{name: "date", status: http.StatusTooManyRequests, retryAfter: "Wed, 21 Oct 2015 07:29:00 GMT",
+			expected: time.Minute},
+		{name: "past date", status: http.StatusTooManyRequests, retryAfter: "Wed, 21 Oct 2015 07:27:00 GMT",
+			expected: -time.Minute},
+		{name: "none", status: http.StatusServiceUnavailable},
+		{name: "invalid", status: http.StatusServiceUnavailable, retryAfter: "soon"},
+		{name: "other status", status: http.StatusBadGateway, retryAfter: "120"},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: test.status, Header: http.Header{},
+				Body: ioutil.NopCloser(strings.NewReader(""))}
+			if test.retryAfter != "" {
+				resp.Header.Set(HTTPRetryAfter, test.retryAfter)
+			}
+			assert.Equal(t, test.expected, getRetryAfter(resp, now))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// cleanupRemoteTimeout limits the deletion of an interrupted Support Bundle from the source service.
+const cleanupRemoteTimeout = 30 * time.Second
+
+// notifyInterrupt gives a context cancelled once the process is interrupted, and a function to call once done, which
+// tells the signal that interrupted it if any. Only the first signal is handled, so that a second Ctrl-C terminates
+// the process at once.
+func notifyInterrupt(parent context.Context) (context.Context, func() os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, os.Interrupt, syscall.SIGTERM)
+	return cancelOnSignal(parent, received, func() { signal.Stop(received) })
+}
+
+// cancelOnSignal gives a context cancelled once a signal is received, and a function to call once done, which tells
+// the signal received if any. release stops the delivery of the signals, it is called once one is received.
+func cancelOnSignal(parent context.Context, received <-chan os.Signal, release func()) (context.Context,
+	func() os.Signal) {
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	var caught os.Signal
+	go func() {
+		defer close(done)
+		select {
+		case caught = <-received:
+			release()
+			log.Warn(fmt.Sprintf("Interrupted (signal: %s), aborting the requests in flight. Interrupt again to exit "+
+				"at once.", caught))
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() os.Signal {
+		cancel()
+		<-done
+		release()
+		return caught
+	}
+}
+
+// runInterruptible runs support-case until it completes or the process is interrupted.
+func runInterruptible(cli CliFacade) (*SupportBundleCmdResult, error) {
+	ctx, stop := notifyInterrupt(context.Background())
+	r, err := SupportBundleCmd(ctx, cli)
+	if sig := stop(); sig != nil && err != nil {
+		return r, handleInterruption(cli, r, sig, err)
+	}
+	return r, err
+}
+
+// handleInterruption tells which phase of support-case was interrupted and how to resume, after deleting the Support
+// Bundle from the source service if asked to. It gives the error support-case fails with, categorized as interrupted
+// whatever the category of the error the phase failed with, as the interruption caused it.
+func handleInterruption(cli CliFacade, r *SupportBundleCmdResult, sig os.Signal, err error) error {
+	phase := interruptedPhase(r)
+	log.Warn(fmt.Sprintf("The %s phase was interrupted", phase))
+	removed := cleanupRemote(cli, r)
+	log.Info(resumeHint(cli, r, phase, removed))
+	return &actions.CategorizedError{Category: actions.CategoryInterrupted,
+		Err: fmt.Errorf("%s phase interrupted (signal: %s): %w", phase, sig, err)}
+}
+
+// interruptedPhase gives the name of the last phase run, the one which was interrupted. The creation is interrupted
+// when no phase ran yet, as it comes first.
+func interruptedPhase(r *SupportBundleCmdResult) string {
+	if r == nil || len(r.Phases) == 0 {
+		return phaseCreate
+	}
+	return r.Phases[len(r.Phases)-1].Name
+}
+
+// cleanupRemote deletes the interrupted Support Bundle from the source service if asked to, and tells whether it did.
+func cleanupRemote(cli CliFacade, r *SupportBundleCmdResult) bool {
+	if !shouldCleanupRemote(cli) || r == nil || r.BundleID == "" {
+		return false
+	}
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Failed to delete Support Bundle %s: %+v", r.BundleID, err))
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupRemoteTimeout)
+	defer cancel()
+	if err = actions.DeleteSupportBundle(ctx, client, r.BundleID); err != nil {
+		log.Warn(fmt.Sprintf("Failed to delete Support Bundle %s from %s: %+v", r.BundleID, client.GetURL(), err))
+		return false
+	}
+	log.Info(fmt.Sprintf("Deleted Support Bundle %s from %s", r.BundleID, client.GetURL()))
+	return true
+}
+
+// resumeHint tells how to resume an interrupted support-case with the step by step commands, without creating a new
+// Support Bundle unless it does not exist anymore.
+func resumeHint(cli CliFacade, r *SupportBundleCmdResult, phase string, removed bool) string {
+	switch {
+	case r == nil || r.BundleID == "":
+		return "To resume, run the command again. A Support Bundle may have been created anyway, see " +
+			"`jfrog sb-flunky list`."
+	case removed:
+		return "To resume, run the command again."
+	case phase == phaseUpload && fileExists(r.LocalFilePath):
+		return fmt.Sprintf("To resume, upload the downloaded Support Bundle:\n  %s",
+			uploadCommand(cli, r.CaseNumber, r.LocalFilePath))
+	default:
+		return fmt.Sprintf("To resume, download the Support Bundle, then upload it with the path printed by the "+
+			"download:\n  %s\n  %s", downloadCommand(cli, r.BundleID), uploadCommand(cli, r.CaseNumber, "<path>"))
+	}
+}
+
+func downloadCommand(cli CliFacade, bundleID actions.BundleID) string {
+	return withFlag(fmt.Sprintf("jfrog sb-flunky download %s", bundleID), serverIDFlag, cli)
+}
+
+func uploadCommand(cli CliFacade, caseNumber actions.CaseNumber, path string) string {
+	command := fmt.Sprintf("jfrog sb-flunky upload %s %s", caseNumber, path)
+	for _, flagName := range []string{targetServerIDFlag, targetRepoFlag} {
+		command = withFlag(command, flagName, cli)
+	}
+	return command
+}
+
+// withFlag appends a flag to a command when it is set.
+func withFlag(command string, flagName string, flagProvider flagValueProvider) string {
+	value := strings.TrimSpace(flagProvider.GetStringFlagValue(flagName))
+	if value == "" {
+		return command
+	}
+	return fmt.Sprintf("%s --%s=%s", command, flagName, value)
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_cancelOnSignal(t *testing.T) {
+	received := make(chan os.Signal, 1)
+	released := 0
+	ctx, stop := cancelOnSignal(context.Background(), received, func() { released++ })
+
+	received <- os.Interrupt
+	<-ctx.Done()
+
+	assert.Equal(t, os.Interrupt, stop())
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Equal(t, 2, released, "the signals are released once received and once done")
+}
+
+func Test_cancelOnSignal_notInterrupted(t *testing.T) {
+	received := make(chan os.Signal, 1)
+	released := 0
+	ctx, stop := cancelOnSignal(context.Background(), received, func() { released++ })
+	assert.NoError(t, ctx.Err())
+
+	assert.Nil(t, stop())
+	assert.Equal(t, context.Canceled, ctx.Err(), "the context is released once done")
+	assert.Equal(t, 1, released)
+}
+
+func Test_resumeHint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "interrupted")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	archive := filepath.Join(dir, "1.zip")
+	require.NoError(t, ioutil.WriteFile(archive, []byte("content"), 0600))
+	cli := &cliStub{stringFlags: map[string]string{"server-id": "prod", "target-server-id": "dropbox"}}
+	downloaded := &SupportBundleCmdResult{CaseNumber: "1234", BundleID: "1", LocalFilePath: archive,
+		Phases: []PhaseResult{{Name: phaseCreate}, {Name: phaseDownload}, {Name: phaseUpload}}}
+	download := "To resume, download the Support Bundle, then upload it with the path printed by the download:\n" +
+		"  jfrog sb-flunky download 1 --server-id=prod\n" +
+		"  jfrog sb-flunky upload 1234 <path> --target-server-id=dropbox"
+	tests := []struct {
+		name        string
+		result      *SupportBundleCmdResult
+		removed     bool
+		expectPhase string
+		expectHint  string
+	}{
+		{
+			name:        "before the creation",
+			expectPhase: phaseCreate,
+			expectHint: "To resume, run the command again. A Support Bundle may have been created anyway, see " +
+				"`jfrog sb-flunky list`.",
+		},
+		{
+			name:        "download",
+			result:      &SupportBundleCmdResult{CaseNumber: "1234", BundleID: "1", Phases: downloaded.Phases[:2]},
+			expectPhase: phaseDownload,
+			expectHint:  download,
+		},
+		{
+			name: "stream",
+			result: &SupportBundleCmdResult{CaseNumber: "1234", BundleID: "1",
+				Phases: []PhaseResult{{Name: phaseStream}}},
+			expectPhase: phaseStream,
+			expectHint:  download,
+		},
+		{
+			name:        "upload",
+			result:      downloaded,
+			expectPhase: phaseUpload,
+			expectHint: "To resume, upload the downloaded Support Bundle:\n  jfrog sb-flunky upload 1234 " + archive +
+				" --target-server-id=dropbox",
+		},
+		{
+			name: "upload of a deleted archive",
+			result: &SupportBundleCmdResult{CaseNumber: "1234", BundleID: "1", LocalFilePath: "missing.zip",
+				Phases: downloaded.Phases},
+			expectPhase: phaseUpload,
+			expectHint:  download,
+		},
+		{
+			name:        "deleted from the source service",
+			result:      downloaded,
+			removed:     true,
+			expectPhase: phaseUpload,
+			expectHint:  "To resume, run the command again.",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			phase := interruptedPhase(test.result)
+			assert.Equal(t, test.expectPhase, phase)
+			assert.Equal(t, test.expectHint, resumeHint(cli, test.result, phase, test.removed))
+		})
+	}
+}
+
+func Test_handleInterruption(t *testing.T) {
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	result := &SupportBundleCmdResult{CaseNumber: "1234", BundleID: "1", Phases: []PhaseResult{{Name: phaseDownload}}}
+	tests := []struct {
+		name          string
+		cleanupRemote bool
+		expectDeleted []string
+	}{
+		{name: "kept", expectDeleted: nil},
+		{name: "deleted", cleanupRemote: true, expectDeleted: []string{"/api/system/support/bundle/1"}},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			deleted = nil
+			cli := &cliStub{boolFlags: map[string]bool{"cleanup-remote": test.cleanupRemote},
+				rtDetails: &config.ArtifactoryDetails{Url: ts.URL + "/"}}
+
+			err := handleInterruption(cli, result, os.Interrupt, context.Canceled)
+
+			assert.EqualError(t, err, "download phase interrupted (signal: interrupt): context canceled")
+			assert.True(t, errors.Is(err, context.Canceled))
+			assert.Equal(t, actions.CategoryInterrupted, actions.CategoryOf(err))
+			assert.Equal(t, 130, exitCodeOf(err))
+			assert.Equal(t, test.expectDeleted, deleted)
+		})
+	}
+}
+
+func Test_handleInterruption_Categorized(t *testing.T) {
+	result := &SupportBundleCmdResult{CaseNumber: "1234", BundleID: "1", Phases: []PhaseResult{{Name: phaseStream}}}
+
+	err := handleInterruption(&cliStub{}, result, os.Interrupt,
+		actions.Categorize(actions.CategoryUploadRejected, context.Canceled))
+
+	assert.EqualError(t, err, "stream phase interrupted (signal: interrupt): context canceled")
+	assert.Equal(t, actions.CategoryInterrupted, actions.CategoryOf(err))
+	assert.Equal(t, 130, exitCodeOf(err))
+}
@@ -1,11 +1,21 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/mattn/go-isatty"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -23,6 +33,10 @@ type artifactoryDetailsProvider interface {
 	GetTargetDetails() (*config.ArtifactoryDetails, error)
 }
 
+type prompterProvider interface {
+	GetPrompter() (actions.FullPrompter, error)
+}
+
 type serviceHelper interface {
 	GetConfig(serverID string, excludeRefreshableTokens bool) (*config.ArtifactoryDetails, error)
 	CreateInitialRefreshableTokensIfNeeded(artifactoryDetails *config.ArtifactoryDetails) error
@@ -60,40 +74,337 @@ func buildRtDetailsFromServerID(serverID string, configHelper serviceHelper) (*c
 	return details, nil
 }
 
-func getTimeout(flagProvider flagValueProvider) time.Duration {
+func getTimeout(flagProvider flagValueProvider) (time.Duration, error) {
 	defaultTimeout := 10 * time.Minute
-	return getDurationOrDefault(flagProvider.GetStringFlagValue(downloadTimeoutFlag), defaultTimeout)
+	return getDuration(flagProvider, downloadTimeoutFlag, defaultTimeout)
+}
+
+// Phases limited by a timeout flag, as named in the error when the timeout elapses.
+const (
+	createTimeoutPhase = "Support Bundle creation"
+	uploadTimeoutPhase = "Support Bundle upload"
+)
+
+const (
+	defaultCreateTimeout   = 5 * time.Minute
+	defaultTransferTimeout = time.Hour
+	defaultUploadTimeout   = time.Hour
+)
+
+// getDownloadTimeouts gives the timeouts of the download of a Support Bundle, from --download-timeout for the wait
+// until it is ready and from --transfer-timeout for the transfer of its archive.
+func getDownloadTimeouts(flagProvider flagValueProvider) (actions.DownloadTimeouts, error) {
+	ready, err := getTimeout(flagProvider)
+	if err != nil {
+		return actions.DownloadTimeouts{}, err
+	}
+	transfer, err := getDuration(flagProvider, transferTimeoutFlag, defaultTransferTimeout)
+	if err != nil {
+		return actions.DownloadTimeouts{}, err
+	}
+	return actions.DownloadTimeouts{Ready: ready, Transfer: transfer}, nil
+}
+
+func getCreateTimeout(flagProvider flagValueProvider) (time.Duration, error) {
+	return getDuration(flagProvider, createTimeoutFlag, defaultCreateTimeout)
+}
+
+func getUploadTimeout(flagProvider flagValueProvider) (time.Duration, error) {
+	return getDuration(flagProvider, uploadTimeoutFlag, defaultUploadTimeout)
+}
+
+// getDeadline gives the timeout of a whole command, 0 when there is none.
+func getDeadline(flagProvider flagValueProvider) (time.Duration, error) {
+	return getDuration(flagProvider, deadlineFlag, 0)
+}
+
+// phaseTimeouts are the timeouts of the phases of support-case, read before the first phase starts so that an invalid
+// one does not fail the command halfway through.
+type phaseTimeouts struct {
+	create   time.Duration
+	download actions.DownloadTimeouts
+	upload   time.Duration
+}
+
+func getPhaseTimeouts(flagProvider flagValueProvider) (timeouts phaseTimeouts, err error) {
+	if timeouts.create, err = getCreateTimeout(flagProvider); err != nil {
+		return timeouts, err
+	}
+	if timeouts.download, err = getDownloadTimeouts(flagProvider); err != nil {
+		return timeouts, err
+	}
+	timeouts.upload, err = getUploadTimeout(flagProvider)
+	return timeouts, err
 }
 
 func shouldCleanup(flagProvider flagValueProvider) bool {
 	return flagProvider.GetBoolFlagValue(cleanupFlag)
 }
 
+func shouldCleanupRemote(flagProvider flagValueProvider) bool {
+	return flagProvider.GetBoolFlagValue(cleanupRemoteFlag)
+}
+
+func shouldStream(flagProvider flagValueProvider) bool {
+	return flagProvider.GetBoolFlagValue(streamFlag)
+}
+
+func getCustomProperties(flagProvider flagValueProvider) ([]servicesutils.Property, error) {
+	properties := strings.TrimSpace(flagProvider.GetStringFlagValue(propertyFlag))
+	if properties == "" {
+		return nil, nil
+	}
+	return actions.ParseCustomProperties(properties)
+}
+
+func getNameTemplate(flagProvider flagValueProvider) actions.NameTemplate {
+	return actions.NameTemplate(strings.TrimSpace(flagProvider.GetStringFlagValue(nameTemplateFlag)))
+}
+
+// getHostname gives the name of this host, used to name uploaded Support Bundles.
+func getHostname() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Warn(fmt.Sprintf("Failed to get the hostname: %+v", err))
+		return ""
+	}
+	return hostname
+}
+
 func getTargetRepo(flagProvider flagValueProvider) string {
 	return flagProvider.GetStringFlagValue(targetRepoFlag)
 }
 
-func getPromptOptions(flagProvider flagValueProvider) actions.OptionsProvider {
-	if flagProvider.GetBoolFlagValue(promptOptionsFlag) {
-		return actions.NewPromptOptionsProvider()
+func getOptionsProvider(cli CliFacade) (actions.OptionsProvider, error) {
+	getDate, err := getClock(cli)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := getBaseOptionsProvider(cli, getDate)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := getOptionsOverrides(cli, getDate)
+	if err != nil {
+		return nil, err
+	}
+	if !overrides.IsEmpty() {
+		provider = &actions.OverridingOptionsProvider{GetDate: getDate, Provider: provider, Overrides: overrides}
+	}
+	if cli.GetBoolFlagValue(promptOptionsFlag) {
+		prompter, err := cli.GetPrompter()
+		if err != nil {
+			return nil, err
+		}
+		// Show the final options, including the overridden ones.
+		provider = &actions.ConfirmingOptionsProvider{Provider: provider, Confirmer: prompter}
+	}
+	return provider, nil
+}
+
+func getBaseOptionsProvider(cli CliFacade, getDate actions.Clock) (actions.OptionsProvider, error) {
+	optionsFile := strings.TrimSpace(cli.GetStringFlagValue(optionsFileFlag))
+	preset := strings.TrimSpace(cli.GetStringFlagValue(presetFlag))
+	prompt := cli.GetBoolFlagValue(promptOptionsFlag)
+	if countTrue(optionsFile != "", preset != "", prompt) > 1 {
+		return nil, fmt.Errorf("only one of --%s, --%s and --%s can be used", optionsFileFlag, presetFlag,
+			promptOptionsFlag)
 	}
-	return actions.NewDefaultOptionsProvider()
+	switch {
+	case optionsFile != "":
+		return actions.NewFileOptionsProvider(optionsFile, getDate), nil
+	case preset != "":
+		return actions.NewPresetOptionsProvider(preset, getDate)
+	case prompt:
+		prompter, err := cli.GetPrompter()
+		if err != nil {
+			return nil, err
+		}
+		return actions.NewPromptOptionsProvider(getDate, prompter), nil
+	default:
+		return actions.NewDefaultOptionsProvider(getDate), nil
+	}
+}
+
+// getPrompter gives the Prompter answering the questions of this plugin: the answers file given with --answers-file,
+// the user when stdin is a terminal, or else answers read from stdin, so that a run without a terminal fails instead of
+// waiting for answers that cannot be given.
+func getPrompter(flagProvider flagValueProvider, stdin *os.File) (actions.FullPrompter, error) {
+	answersFile := strings.TrimSpace(flagProvider.GetStringFlagValue(answersFileFlag))
+	switch {
+	case answersFile == "-":
+		return readAnswers("stdin", stdin)
+	case answersFile != "":
+		content, err := ioutil.ReadFile(answersFile)
+		if err != nil {
+			return nil, err
+		}
+		return readAnswers(answersFile, bytes.NewReader(content))
+	case isTerminal(stdin):
+		return &actions.TerminalPrompter{}, nil
+	default:
+		return readAnswers("stdin", stdin)
+	}
+}
+
+func readAnswers(source string, r io.Reader) (actions.FullPrompter, error) {
+	prompter, err := actions.ReadAnswers(source, r)
+	if errors.Is(err, actions.ErrNoAnswers) {
+		return nil, fmt.Errorf("%w: questions can only be asked in a terminal, give the answers with --%s or on stdin",
+			err, answersFileFlag)
+	}
+	return prompter, err
+}
+
+func isTerminal(file *os.File) bool {
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
+
+// getClock gives the current time in the time zone selected with --timezone, UTC by default.
+func getClock(flagProvider flagValueProvider) (actions.Clock, error) {
+	timezone := strings.TrimSpace(flagProvider.GetStringFlagValue(timezoneFlag))
+	if timezone == "" {
+		return actions.ClockIn(time.UTC), nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %s, expected a time zone such as UTC or Europe/Paris", timezoneFlag,
+			timezone)
+	}
+	return actions.ClockIn(location), nil
+}
+
+func getOptionsOverrides(flagProvider flagValueProvider, now actions.Clock) (actions.OptionsOverrides, error) {
+	overrides, err := getLogsOverrides(flagProvider, now)
+	if err != nil {
+		return overrides, err
+	}
+	if value := strings.TrimSpace(flagProvider.GetStringFlagValue(threadDumpsFlag)); value != "" {
+		count, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return overrides, fmt.Errorf("invalid %s value %s, expected a positive number", threadDumpsFlag, value)
+		}
+		threadDumps := uint(count)
+		overrides.ThreadDumps = &threadDumps
+	}
+	if value := strings.TrimSpace(flagProvider.GetStringFlagValue(threadDumpIntervalFlag)); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < time.Millisecond {
+			return overrides, fmt.Errorf("invalid %s value %s, expected a duration such as 500ms or 10s",
+				threadDumpIntervalFlag, value)
+		}
+		overrides.ThreadDumpInterval = interval
+	}
+	return overrides, nil
+}
+
+// getLogsOverrides gives the overrides of the logs to include. The thread dump overrides are left empty.
+func getLogsOverrides(flagProvider flagValueProvider, now actions.Clock) (actions.OptionsOverrides, error) {
+	overrides := actions.OptionsOverrides{
+		LogsFrom: strings.TrimSpace(flagProvider.GetStringFlagValue(logsFromFlag)),
+		LogsTo:   strings.TrimSpace(flagProvider.GetStringFlagValue(logsToFlag)),
+	}
+	if value := strings.TrimSpace(flagProvider.GetStringFlagValue(logsSinceFlag)); value != "" {
+		since, err := parseDurationWithDays(value)
+		if err != nil || since <= 0 {
+			return overrides, fmt.Errorf("invalid %s value %s, expected a duration such as 72h or 3d", logsSinceFlag,
+				value)
+		}
+		if overrides.LogsFrom != "" || overrides.LogsTo != "" {
+			return overrides, fmt.Errorf("--%s cannot be used with --%s or --%s", logsSinceFlag, logsFromFlag,
+				logsToFlag)
+		}
+		overrides.LogsSince = since
+	}
+	return overrides, checkLogsDates(overrides, now)
+}
+
+// checkLogsDates checks that the logs dates form a valid range, starting today at the latest as there are no logs of
+// the future to include.
+func checkLogsDates(overrides actions.OptionsOverrides, now actions.Clock) error {
+	from, err := parseDateFlag(logsFromFlag, overrides.LogsFrom)
+	if err != nil {
+		return err
+	}
+	to, err := parseDateFlag(logsToFlag, overrides.LogsTo)
+	if err != nil {
+		return err
+	}
+	if !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("--%s %s is before --%s %s", logsToFlag, overrides.LogsTo, logsFromFlag, overrides.LogsFrom)
+	}
+	year, month, day := now().Date()
+	if today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC); today.Before(from) {
+		return fmt.Errorf("--%s %s is after today, %s", logsFromFlag, overrides.LogsFrom, today.Format("2006-01-02"))
+	}
+	return nil
 }
 
-func getRetryInterval(flagProvider flagValueProvider) time.Duration {
-	defaultRetryInterval := 5 * time.Second
-	return getDurationOrDefault(flagProvider.GetStringFlagValue(retryIntervalFlag), defaultRetryInterval)
+func parseDateFlag(flagName string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return date, fmt.Errorf("invalid %s value %s, expected a date in the YYYY-MM-DD format", flagName, value)
+	}
+	return date, nil
 }
 
-func getDurationOrDefault(value string, defaultValue time.Duration) time.Duration {
+func countTrue(values ...bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
+
+func getRetryInterval(flagProvider flagValueProvider) (time.Duration, error) {
+	return getDuration(flagProvider, retryIntervalFlag, http.DefaultRetryInterval)
+}
+
+// getRetryPolicy gives the policy retrying the requests to Artifactory, from --max-attempts, --retry-interval and
+// --max-retry-interval.
+func getRetryPolicy(flagProvider flagValueProvider) (http.RetryPolicy, error) {
+	policy := http.DefaultRetryPolicy()
+	var err error
+	if policy.Interval, err = getRetryInterval(flagProvider); err != nil {
+		return policy, err
+	}
+	policy.MaxInterval, err = getDuration(flagProvider, maxRetryIntervalFlag, http.DefaultMaxRetryInterval)
+	if err != nil {
+		return policy, err
+	}
+	if value := strings.TrimSpace(flagProvider.GetStringFlagValue(maxAttemptsFlag)); value != "" {
+		attempts, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || attempts == 0 {
+			return policy, fmt.Errorf("invalid %s value %s, expected a positive number", maxAttemptsFlag, value)
+		}
+		policy.MaxAttempts = int(attempts)
+	}
+	if policy.Interval <= 0 {
+		return policy, fmt.Errorf("invalid %s value %s, expected a positive duration", retryIntervalFlag,
+			policy.Interval)
+	}
+	if policy.MaxInterval < policy.Interval {
+		return policy, fmt.Errorf("--%s %s is shorter than --%s %s", maxRetryIntervalFlag, policy.MaxInterval,
+			retryIntervalFlag, policy.Interval)
+	}
+	return policy, nil
+}
+
+// getDuration gives the value of a duration flag, defaultValue when it is not set. It fails when the value is not a
+// duration or is negative, rather than silently using defaultValue instead.
+func getDuration(flagProvider flagValueProvider, flagName string, defaultValue time.Duration) (time.Duration, error) {
+	value := strings.TrimSpace(flagProvider.GetStringFlagValue(flagName))
 	if value == "" {
-		return defaultValue
+		return defaultValue, nil
 	}
 	duration, err := time.ParseDuration(value)
-	if err != nil {
-		log.Debug(fmt.Sprintf("Error parsing duration: %+v", err))
-		log.Warn(fmt.Sprintf("Error parsing duration %s, using default %s", value, defaultValue))
-		return defaultValue
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid %s value %s, expected a duration such as 30s or 1h", flagName, value)
 	}
-	return duration
+	return duration, nil
 }
@@ -3,9 +3,14 @@ package commands
 import (
 	"errors"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -48,6 +53,7 @@ func Test_getTimeoutAndRetryInterval(t *testing.T) {
 		flagProvider          *flagProviderStub
 		expectedTimeout       time.Duration
 		expectedRetryInterval time.Duration
+		expectedErr           string
 	}{
 		{
 			name: "empty string uses default",
@@ -58,12 +64,18 @@ func Test_getTimeoutAndRetryInterval(t *testing.T) {
 			expectedRetryInterval: defaultRetry,
 		},
 		{
-			name: "parse error uses default",
+			name: "parse error",
 			flagProvider: &flagProviderStub{
 				value: "30 seconds",
 			},
-			expectedTimeout:       defaultTimeout,
-			expectedRetryInterval: defaultRetry,
+			expectedErr: "value 30 seconds, expected a duration such as 30s or 1h",
+		},
+		{
+			name: "negative duration",
+			flagProvider: &flagProviderStub{
+				value: "-25s",
+			},
+			expectedErr: "value -25s, expected a duration such as 30s or 1h",
 		},
 		{
 			name: "valid duration",
@@ -78,52 +90,333 @@ func Test_getTimeoutAndRetryInterval(t *testing.T) {
 	for i := range tests {
 		test := tests[i]
 		t.Run(test.name, func(t *testing.T) {
-			assert.Equal(t, test.expectedTimeout, getTimeout(test.flagProvider))
+			timeout, err := getTimeout(test.flagProvider)
 			assert.Equal(t, "download-timeout", test.flagProvider.receivedFlagName)
-			assert.Equal(t, test.expectedRetryInterval, getRetryInterval(test.flagProvider))
+			retryInterval, retryErr := getRetryInterval(test.flagProvider)
 			assert.Equal(t, "retry-interval", test.flagProvider.receivedFlagName)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, "invalid download-timeout "+test.expectedErr)
+				assert.EqualError(t, retryErr, "invalid retry-interval "+test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, retryErr)
+			assert.Equal(t, test.expectedTimeout, timeout)
+			assert.Equal(t, test.expectedRetryInterval, retryInterval)
 		})
 	}
 }
 
-func Test_getPromptOptions(t *testing.T) {
+func Test_getPhaseTimeouts(t *testing.T) {
 	tests := []struct {
-		name          string
-		flagProvider  *flagProviderStub
-		expectDefault bool
+		name        string
+		flags       map[string]string
+		expected    phaseTimeouts
+		expectedErr string
 	}{
 		{
-			name:          "no prompt options specified uses default",
-			flagProvider:  &flagProviderStub{},
-			expectDefault: true,
+			name: "defaults",
+			expected: phaseTimeouts{create: 5 * time.Minute, upload: time.Hour,
+				download: actions.DownloadTimeouts{Ready: 10 * time.Minute, Transfer: time.Hour}},
 		},
 		{
-			name: "false prompt options specified uses default",
-			flagProvider: &flagProviderStub{
-				boolVal: false,
+			name: "configured",
+			flags: map[string]string{"create-timeout": "1m", "download-timeout": "20m", "transfer-timeout": "0",
+				"upload-timeout": "2h"},
+			expected: phaseTimeouts{create: time.Minute, upload: 2 * time.Hour,
+				download: actions.DownloadTimeouts{Ready: 20 * time.Minute}},
+		},
+		{
+			name:        "invalid create timeout",
+			flags:       map[string]string{"create-timeout": "5"},
+			expectedErr: "invalid create-timeout value 5, expected a duration such as 30s or 1h",
+		},
+		{
+			name:        "invalid transfer timeout",
+			flags:       map[string]string{"transfer-timeout": "1 hour"},
+			expectedErr: "invalid transfer-timeout value 1 hour, expected a duration such as 30s or 1h",
+		},
+		{
+			name:        "negative upload timeout",
+			flags:       map[string]string{"upload-timeout": "-1h"},
+			expectedErr: "invalid upload-timeout value -1h, expected a duration such as 30s or 1h",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			timeouts, err := getPhaseTimeouts(&cliStub{stringFlags: test.flags})
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, timeouts)
+		})
+	}
+}
+
+func Test_getRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expected    flunkyhttp.RetryPolicy
+		expectedErr string
+	}{
+		{
+			name:     "defaults",
+			expected: flunkyhttp.DefaultRetryPolicy(),
+		},
+		{
+			name:  "configured",
+			flags: map[string]string{"max-attempts": "3", "retry-interval": "100ms", "max-retry-interval": "2s"},
+			expected: flunkyhttp.RetryPolicy{MaxAttempts: 3, Interval: 100 * time.Millisecond, MaxInterval: 2 * time.Second,
+				Jitter: flunkyhttp.DefaultRetryJitter},
+		},
+		{
+			name:  "single attempt",
+			flags: map[string]string{"max-attempts": "1"},
+			expected: flunkyhttp.RetryPolicy{MaxAttempts: 1, Interval: 5 * time.Second, MaxInterval: time.Minute,
+				Jitter: flunkyhttp.DefaultRetryJitter},
+		},
+		{
+			name:        "no attempt",
+			flags:       map[string]string{"max-attempts": "0"},
+			expectedErr: "invalid max-attempts value 0, expected a positive number",
+		},
+		{
+			name:        "invalid attempts",
+			flags:       map[string]string{"max-attempts": "many"},
+			expectedErr: "invalid max-attempts value many, expected a positive number",
+		},
+		{
+			name:        "no interval",
+			flags:       map[string]string{"retry-interval": "0s"},
+			expectedErr: "invalid retry-interval value 0s, expected a positive duration",
+		},
+		{
+			name:        "negative interval",
+			flags:       map[string]string{"retry-interval": "-5s"},
+			expectedErr: "invalid retry-interval value -5s, expected a duration such as 30s or 1h",
+		},
+		{
+			name:        "invalid cap",
+			flags:       map[string]string{"max-retry-interval": "1x"},
+			expectedErr: "invalid max-retry-interval value 1x, expected a duration such as 30s or 1h",
+		},
+		{
+			name:        "cap shorter than interval",
+			flags:       map[string]string{"retry-interval": "10s", "max-retry-interval": "1s"},
+			expectedErr: "--max-retry-interval 1s is shorter than --retry-interval 10s",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := getRetryPolicy(&cliStub{stringFlags: test.flags})
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, policy)
+		})
+	}
+}
+
+func Test_getOptionsProvider(t *testing.T) {
+	tests := []struct {
+		name       string
+		cli        *cliStub
+		expectType actions.OptionsProvider
+		expectErr  string
+	}{
+		{
+			name:       "no flag uses default",
+			cli:        &cliStub{},
+			expectType: &actions.DefaultOptionsProvider{},
+		},
+		{
+			name: "prompt options",
+			cli: &cliStub{
+				boolFlags: map[string]bool{"prompt-options": true},
+				prompter:  &actions.PrompterStub{},
 			},
-			expectDefault: true,
+			expectType: &actions.ConfirmingOptionsProvider{},
 		},
 		{
-			name: "true prompt options specified uses custom",
-			flagProvider: &flagProviderStub{
-				boolVal: true,
+			name:      "prompt options without prompter",
+			cli:       &cliStub{boolFlags: map[string]bool{"prompt-options": true}},
+			expectErr: "no prompter",
+		},
+		{
+			name:       "options file",
+			cli:        &cliStub{stringFlags: map[string]string{"options-file": " sb.yaml "}},
+			expectType: &actions.FileOptionsProvider{},
+		},
+		{
+			name: "prompt options and options file",
+			cli: &cliStub{
+				stringFlags: map[string]string{"options-file": "sb.yaml"},
+				boolFlags:   map[string]bool{"prompt-options": true},
+			},
+			expectErr: "only one of --options-file, --preset and --prompt-options can be used",
+		},
+		{
+			name:       "overridden thread dumps",
+			cli:        &cliStub{stringFlags: map[string]string{"preset": "full", "thread-dumps": "3"}},
+			expectType: &actions.OverridingOptionsProvider{},
+		},
+		{
+			name:      "invalid timezone",
+			cli:       &cliStub{stringFlags: map[string]string{"timezone": "Mars/Olympus"}},
+			expectErr: "invalid timezone value Mars/Olympus, expected a time zone such as UTC or Europe/Paris",
+		},
+		{
+			name:       "preset",
+			cli:        &cliStub{stringFlags: map[string]string{"preset": "minimal"}},
+			expectType: &actions.PresetOptionsProvider{},
+		},
+		{
+			name:      "unknown preset",
+			cli:       &cliStub{stringFlags: map[string]string{"preset": "everything"}},
+			expectErr: `unknown preset "everything", expected one of: minimal, full, performance, startup-failure`,
+		},
+		{
+			name: "preset and options file",
+			cli: &cliStub{
+				stringFlags: map[string]string{"options-file": "sb.yaml", "preset": "minimal"},
 			},
-			expectDefault: false,
+			expectErr: "only one of --options-file, --preset and --prompt-options can be used",
 		},
 	}
 
 	for i := range tests {
 		test := tests[i]
 		t.Run(test.name, func(t *testing.T) {
-			provider := getPromptOptions(test.flagProvider)
-			_, isDefault := provider.(*actions.DefaultOptionsProvider)
-			assert.Equal(t, test.expectDefault, isDefault)
-			assert.Equal(t, "prompt-options", test.flagProvider.receivedFlagName)
+			provider, err := getOptionsProvider(test.cli)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, test.expectType, provider)
+			if fileProvider, ok := provider.(*actions.FileOptionsProvider); ok {
+				assert.Equal(t, "sb.yaml", fileProvider.Path)
+			}
 		})
 	}
 }
 
+func Test_getOptionsOverrides(t *testing.T) {
+	three := uint(3)
+	tests := []struct {
+		name            string
+		flags           map[string]string
+		expectOverrides actions.OptionsOverrides
+		expectErr       string
+	}{
+		{
+			name: "no flag",
+		},
+		{
+			name:            "logs since days",
+			flags:           map[string]string{"logs-since": "3d"},
+			expectOverrides: actions.OptionsOverrides{LogsSince: 72 * time.Hour},
+		},
+		{
+			name: "logs range and thread dumps",
+			flags: map[string]string{
+				"logs-from":            "2020-12-01",
+				"logs-to":              "2020-12-03",
+				"thread-dumps":         "3",
+				"thread-dump-interval": "10s",
+			},
+			expectOverrides: actions.OptionsOverrides{
+				LogsFrom:           "2020-12-01",
+				LogsTo:             "2020-12-03",
+				ThreadDumps:        &three,
+				ThreadDumpInterval: 10 * time.Second,
+			},
+		},
+		{
+			name:      "invalid logs since",
+			flags:     map[string]string{"logs-since": "3 days"},
+			expectErr: "invalid logs-since value 3 days, expected a duration such as 72h or 3d",
+		},
+		{
+			name:      "logs since with logs from",
+			flags:     map[string]string{"logs-since": "3d", "logs-from": "2020-12-01"},
+			expectErr: "--logs-since cannot be used with --logs-from or --logs-to",
+		},
+		{
+			name:      "invalid logs to",
+			flags:     map[string]string{"logs-to": "12/03/2020"},
+			expectErr: "invalid logs-to value 12/03/2020, expected a date in the YYYY-MM-DD format",
+		},
+		{
+			name:      "logs to before logs from",
+			flags:     map[string]string{"logs-from": "2020-12-03", "logs-to": "2020-12-01"},
+			expectErr: "--logs-to 2020-12-01 is before --logs-from 2020-12-03",
+		},
+		{
+			name:            "logs from today",
+			flags:           map[string]string{"logs-from": "2020-12-02"},
+			expectOverrides: actions.OptionsOverrides{LogsFrom: "2020-12-02"},
+		},
+		{
+			name:      "logs from in the future",
+			flags:     map[string]string{"logs-from": "2020-12-03"},
+			expectErr: "--logs-from 2020-12-03 is after today, 2020-12-02",
+		},
+		{
+			name:      "logs range in the future",
+			flags:     map[string]string{"logs-from": "2020-12-03", "logs-to": "2020-12-05"},
+			expectErr: "--logs-from 2020-12-03 is after today, 2020-12-02",
+		},
+		{
+			name:      "invalid thread dumps",
+			flags:     map[string]string{"thread-dumps": "-1"},
+			expectErr: "invalid thread-dumps value -1, expected a positive number",
+		},
+		{
+			name:      "invalid thread dump interval",
+			flags:     map[string]string{"thread-dump-interval": "10"},
+			expectErr: "invalid thread-dump-interval value 10, expected a duration such as 500ms or 10s",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			overrides, err := getOptionsOverrides(&cliStub{stringFlags: test.flags}, func() time.Time {
+				return time.Date(2020, 12, 2, 23, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+			})
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectOverrides, overrides)
+		})
+	}
+}
+
+func Test_getClock(t *testing.T) {
+	clock, err := getClock(&cliStub{})
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, clock().Location())
+
+	clock, err = getClock(&cliStub{stringFlags: map[string]string{"timezone": "America/New_York"}})
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", clock().Location().String())
+
+	_, err = getClock(&cliStub{stringFlags: map[string]string{"timezone": "Mars/Olympus"}})
+	assert.EqualError(t, err, "invalid timezone value Mars/Olympus, expected a time zone such as UTC or Europe/Paris")
+}
+
 func Test_shouldCleanup(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -156,6 +449,25 @@ func Test_shouldCleanup(t *testing.T) {
 	}
 }
 
+func Test_shouldStream(t *testing.T) {
+	flagProvider := &flagProviderStub{boolVal: true}
+	assert.True(t, shouldStream(flagProvider))
+	assert.Equal(t, "stream", flagProvider.receivedFlagName)
+}
+
+func Test_getCustomProperties(t *testing.T) {
+	properties, err := getCustomProperties(&cliStub{stringFlags: map[string]string{"property": "team=core; env=prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, []servicesutils.Property{{Key: "team", Value: "core"}, {Key: "env", Value: "prod"}}, properties)
+
+	properties, err = getCustomProperties(&cliStub{})
+	require.NoError(t, err)
+	assert.Empty(t, properties)
+
+	_, err = getCustomProperties(&cliStub{stringFlags: map[string]string{"property": "team"}})
+	assert.EqualError(t, err, "Invalid property: team")
+}
+
 func Test_getTargetRepo(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -302,3 +614,112 @@ func Test_getRtTargetDetails(t *testing.T) {
 		})
 	}
 }
+
+type cliStub struct {
+	arguments       []string
+	stringFlags     map[string]string
+	boolFlags       map[string]bool
+	rtDetails       *config.ArtifactoryDetails
+	targetRtDetails *config.ArtifactoryDetails
+	prompter        actions.FullPrompter
+}
+
+func (s *cliStub) GetRtDetails() (*config.ArtifactoryDetails, error) {
+	if s.rtDetails == nil {
+		return nil, errors.New("failed to get RT details")
+	}
+	return s.rtDetails, nil
+}
+func (s *cliStub) GetTargetDetails() (*config.ArtifactoryDetails, error) {
+	if s.targetRtDetails == nil {
+		return nil, errors.New("failed to get Target RT details")
+	}
+	return s.targetRtDetails, nil
+}
+func (s *cliStub) GetArguments() []string {
+	return s.arguments
+}
+func (s *cliStub) GetStringFlagValue(flagName string) string {
+	return s.stringFlags[flagName]
+}
+func (s *cliStub) IsFlagSet(flagName string) bool {
+	_, isString := s.stringFlags[flagName]
+	_, isBool := s.boolFlags[flagName]
+	return isString || isBool
+}
+func (s *cliStub) GetBoolFlagValue(flagName string) bool {
+	return s.boolFlags[flagName]
+}
+func (s *cliStub) GetPrompter() (actions.FullPrompter, error) {
+	if s.prompter == nil {
+		return nil, errors.New("no prompter")
+	}
+	return s.prompter, nil
+}
+
+func Test_getPrompter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "answers")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	answersFile := filepath.Join(dir, "answers.yaml")
+	require.NoError(t, ioutil.WriteFile(answersFile, []byte("caseNumber: \"1234\"\n"), 0600))
+	emptyFile := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, ioutil.WriteFile(emptyFile, nil, 0600))
+
+	tests := []struct {
+		name             string
+		answersFile      string
+		stdin            string
+		expectCaseNumber actions.CaseNumber
+		expectErr        string
+	}{
+		{
+			name:             "answers file",
+			answersFile:      answersFile,
+			stdin:            emptyFile,
+			expectCaseNumber: "1234",
+		},
+		{
+			name:             "answers on stdin",
+			stdin:            answersFile,
+			expectCaseNumber: "1234",
+		},
+		{
+			name:             "explicit stdin",
+			answersFile:      "-",
+			stdin:            answersFile,
+			expectCaseNumber: "1234",
+		},
+		{
+			name:  "no terminal and no answers",
+			stdin: emptyFile,
+			expectErr: "no answers given in stdin: questions can only be asked in a terminal, " +
+				"give the answers with --answers-file or on stdin",
+		},
+		{
+			name:        "missing answers file",
+			answersFile: filepath.Join(dir, "missing.yaml"),
+			stdin:       emptyFile,
+			expectErr:   "open " + filepath.Join(dir, "missing.yaml") + ": no such file or directory",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			stdin, err := os.Open(test.stdin)
+			require.NoError(t, err)
+			defer func() { _ = stdin.Close() }()
+			prompter, err := getPrompter(&cliStub{stringFlags: map[string]string{"answers-file": test.answersFile}},
+				stdin)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			caseNumber, err := prompter.AskCaseNumber()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectCaseNumber, caseNumber)
+		})
+	}
+}
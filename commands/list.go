@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"io"
+	"text/tabwriter"
+)
+
+// GetListCommand returns the description of the "list" command.
+func GetListCommand() components.Command {
+	flags := getFlags(serverIDFlag, maxAttemptsFlag, retryIntervalFlag, maxRetryIntervalFlag, outputFlag, profileFlag)
+	return components.Command{
+		Name:        "list",
+		Description: "Lists the Support Bundles available on the source Artifactory service",
+		Aliases:     []string{"ls"},
+		Arguments: []components.Argument{
+			{
+				Name:        "bundle-id",
+				Description: "Optional ID of a Support Bundle to inspect. If not provided all Support Bundles are listed.",
+			},
+		},
+		Flags:   flags,
+		EnvVars: getEnvVars(flags),
+		Action:  listCmd,
+	}
+}
+
+func listCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetListCommand().Flags)
+	if err != nil {
+		return err
+	}
+	format, err := getOutputFormat(cli)
+	if err != nil {
+		return toCliError(configurationError(err))
+	}
+	bundles, err := ListCmd(context.Background(), cli)
+	if err != nil {
+		return toCliError(err)
+	}
+	output, err := formatOutput(format, bundles, func(w io.Writer) error {
+		return writeSupportBundlesTable(w, bundles)
+	})
+	if err != nil {
+		return err
+	}
+	log.Output(output)
+	return nil
+}
+
+// ListCmd lists the Support Bundles available on the source Artifactory service, or inspects a single one.
+func ListCmd(ctx context.Context, cli CliFacade) ([]flunkyhttp.SupportBundleDetails, error) {
+	arguments := cli.GetArguments()
+	if len(arguments) > 1 {
+		return nil, configurationError(fmt.Errorf("wrong number of arguments. Expected: 0 or 1, Received: %d",
+			len(arguments)))
+	}
+
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		return nil, configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
+
+	if len(arguments) == 0 {
+		bundles, err := actions.ListSupportBundles(ctx, client)
+		return bundles, requestError(err)
+	}
+	bundleID, err := parseBundleIDArgument(cli)
+	if err != nil {
+		return nil, configurationError(err)
+	}
+	details, err := actions.GetSupportBundleDetails(ctx, client, bundleID)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if details.ID == "" {
+		details.ID = string(bundleID)
+	}
+	return []flunkyhttp.SupportBundleDetails{details}, nil
+}
+
+func writeSupportBundlesTable(w io.Writer, bundles []flunkyhttp.SupportBundleDetails) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "ID\tNAME\tDESCRIPTION\tCREATED\tSTATUS\tSIZE")
+	if err != nil {
+		return err
+	}
+	for i := range bundles {
+		b := bundles[i]
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", b.ID, b.Name, b.Description, b.Created, b.Status,
+			formatSize(b.Size))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size <= 0 {
+		return "-"
+	}
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GetListCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "list",
+		Description: "Lists the Support Bundles available on the source Artifactory service",
+		Aliases:     []string{"ls"},
+		Arguments: []components.Argument{
+			{
+				Name:        "bundle-id",
+				Description: "Optional ID of a Support Bundle to inspect. If not provided all Support Bundles are listed.",
+			},
+		},
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "server-id",
+				Description: "Artifactory server ID configured using the config command. " +
+					"If not provided the default configuration will be used.",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name:         "output",
+				Description:  "The output format: text, json or yaml.",
+				DefaultValue: "text",
+			},
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetListCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
+
+func Test_ListCmd(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/system/support/bundles":
+			_, _ = w.Write([]byte(`{"count":1,"bundles":[{"id":"1","name":"n","description":"d","created":"c"}]}`))
+		case "/api/system/support/bundle/1":
+			_, _ = w.Write([]byte(`{"status":"success","size":2048}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	rtDetails := &config.ArtifactoryDetails{Url: ts.URL + "/"}
+	expected := []flunkyhttp.SupportBundleDetails{
+		{ID: "1", Name: "n", Description: "d", Created: "c", Status: "success", Size: 2048},
+	}
+
+	bundles, err := ListCmd(context.Background(), &cliStub{rtDetails: rtDetails})
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Diff(expected, bundles))
+
+	bundles, err = ListCmd(context.Background(), &cliStub{arguments: []string{"1"}, rtDetails: rtDetails})
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Diff([]flunkyhttp.SupportBundleDetails{{ID: "1", Status: "success", Size: 2048}}, bundles))
+
+	_, err = ListCmd(context.Background(), &cliStub{arguments: []string{"2"}, rtDetails: rtDetails})
+	assert.EqualError(t, err, "http request failed with: 404 Not Found")
+
+	_, err = ListCmd(context.Background(), &cliStub{arguments: []string{"1", "2"}, rtDetails: rtDetails})
+	assert.EqualError(t, err, "wrong number of arguments. Expected: 0 or 1, Received: 2")
+	assert.Equal(t, actions.CategoryConfiguration, actions.CategoryOf(err))
+}
+
+func Test_writeSupportBundlesTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeSupportBundlesTable(&buf, []flunkyhttp.SupportBundleDetails{
+		{ID: "1", Name: "n", Description: "d", Created: "c", Status: "success", Size: 3 * 1024 * 1024},
+		{ID: "22", Name: "name", Description: "desc", Created: "created", Status: "in progress"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ID  NAME  DESCRIPTION  CREATED  STATUS       SIZE\n"+
+		"1   n     d            c        success      3.0 MiB\n"+
+		"22  name  desc         created  in progress  -\n", buf.String())
+}
+
+func Test_formatSize(t *testing.T) {
+	assert.Equal(t, "-", formatSize(0))
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KiB", formatSize(1536))
+	assert.Equal(t, "2.0 GiB", formatSize(2*1024*1024*1024))
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"strings"
+)
+
+const (
+	textOutput = "text"
+	jsonOutput = "json"
+	yamlOutput = "yaml"
+)
+
+func getOutputFormat(flagProvider flagValueProvider) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(flagProvider.GetStringFlagValue(outputFlag)))
+	switch format {
+	case "":
+		return textOutput, nil
+	case textOutput, jsonOutput, yamlOutput:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %s, expected one of: %s, %s, %s", format, textOutput,
+			jsonOutput, yamlOutput)
+	}
+}
+
+// formatOutput renders a value in the given output format. The human-readable text rendering is delegated to
+// writeText.
+func formatOutput(format string, value interface{}, writeText func(w io.Writer) error) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case jsonOutput:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(value); err != nil {
+			return "", err
+		}
+	case yamlOutput:
+		if err := yaml.NewEncoder(&buf).Encode(value); err != nil {
+			return "", err
+		}
+	default:
+		if err := writeText(&buf); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
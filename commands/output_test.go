@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+func Test_getOutputFormat(t *testing.T) {
+	tests := []struct {
+		value     string
+		expect    string
+		expectErr string
+	}{
+		{value: "", expect: "text"},
+		{value: "text", expect: "text"},
+		{value: "JSON", expect: "json"},
+		{value: " yaml ", expect: "yaml"},
+		{value: "xml", expectErr: "unsupported output format xml, expected one of: text, json, yaml"},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.value, func(t *testing.T) {
+			flagProvider := &flagProviderStub{value: test.value}
+			format, err := getOutputFormat(flagProvider)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expect, format)
+			}
+			assert.Equal(t, "output", flagProvider.receivedFlagName)
+		})
+	}
+}
+
+func Test_formatOutput(t *testing.T) {
+	value := struct {
+		Foo string `json:"foo" yaml:"foo"`
+	}{Foo: "bar"}
+	writeText := func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "foo is bar")
+		return err
+	}
+	tests := []struct {
+		format string
+		expect string
+	}{
+		{format: "text", expect: "foo is bar"},
+		{format: "json", expect: "{\n  \"foo\": \"bar\"\n}"},
+		{format: "yaml", expect: "foo: bar"},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.format, func(t *testing.T) {
+			output, err := formatOutput(test.format, value, writeText)
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, output)
+		})
+	}
+
+	_, err := formatOutput(textOutput, value, func(io.Writer) error { return errors.New("oops") })
+	assert.EqualError(t, err, "oops")
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// GetPresetsCommand returns the description of the "presets" command.
+func GetPresetsCommand() components.Command {
+	flags := getFlags(outputFlag, profileFlag)
+	return components.Command{
+		Name:        "presets",
+		Description: "Lists the built-in presets usable with --preset and what they include",
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
+		Action:      presetsCmd,
+	}
+}
+
+func presetsCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetPresetsCommand().Flags)
+	if err != nil {
+		return err
+	}
+	output, err := PresetsCmd(cli)
+	if err != nil {
+		return err
+	}
+	log.Output(output)
+	return nil
+}
+
+// PresetsCmd renders the built-in presets in the requested output format.
+func PresetsCmd(cli flagValueProvider) (string, error) {
+	format, err := getOutputFormat(cli)
+	if err != nil {
+		return "", err
+	}
+	presets := actions.Presets()
+	return formatOutput(format, presets, func(w io.Writer) error {
+		return writePresetsTable(w, presets)
+	})
+}
+
+func writePresetsTable(w io.Writer, presets []actions.Preset) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "NAME\tINCLUDES\tDESCRIPTION")
+	if err != nil {
+		return err
+	}
+	for i := range presets {
+		p := presets[i]
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, describeOptions(p.Options), p.Description)
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// describeOptions summarizes what a Support Bundle created with the given options includes.
+func describeOptions(options actions.OptionsFile) string {
+	var included []string
+	if options.Configuration {
+		included = append(included, "configuration")
+	}
+	if options.System {
+		included = append(included, "system")
+	}
+	if options.Logs.Include {
+		if options.Logs.LastDays > 1 {
+			included = append(included, fmt.Sprintf("logs (%d days)", options.Logs.LastDays))
+		} else {
+			included = append(included, "logs (1 day)")
+		}
+	}
+	switch {
+	case options.ThreadDump.Count > 1:
+		included = append(included, fmt.Sprintf("%d thread dumps (every %d ms)", options.ThreadDump.Count,
+			options.ThreadDump.Interval))
+	case options.ThreadDump.Count == 1:
+		included = append(included, "thread dump")
+	}
+	return strings.Join(included, ", ")
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_GetPresetsCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "presets",
+		Description: "Lists the built-in presets usable with --preset and what they include",
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name:         "output",
+				Description:  "The output format: text, json or yaml.",
+				DefaultValue: "text",
+			},
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetPresetsCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
+
+func Test_PresetsCmd(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       string
+		expectPrefix string
+		expectLine   string
+	}{
+		{
+			name:         "text",
+			format:       "text",
+			expectPrefix: "NAME ",
+			expectLine:   "5 thread dumps (every 2000 ms)",
+		},
+		{
+			name:         "json",
+			format:       "json",
+			expectPrefix: "[",
+			expectLine:   `"name": "startup-failure"`,
+		},
+		{
+			name:         "yaml",
+			format:       "yaml",
+			expectPrefix: "- name: minimal",
+			expectLine:   "    lastDays: 7",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			output, err := PresetsCmd(&cliStub{stringFlags: map[string]string{"output": test.format}})
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(output, test.expectPrefix), output)
+			assert.Contains(t, output, test.expectLine)
+		})
+	}
+}
+
+func Test_describeOptions(t *testing.T) {
+	presets := map[string]string{}
+	output, err := PresetsCmd(&cliStub{})
+	require.NoError(t, err)
+	for _, line := range strings.Split(output, "\n")[1:] {
+		fields := strings.SplitN(line, " ", 2)
+		presets[fields[0]] = line
+	}
+	assert.Contains(t, presets["minimal"], " configuration ")
+	assert.Contains(t, presets["full"], "configuration, system, logs (7 days), thread dump ")
+	assert.Contains(t, presets["performance"], "configuration, system, logs (1 day), 5 thread dumps (every 2000 ms)")
+	assert.Contains(t, presets["startup-failure"], "configuration, system, logs (3 days) ")
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/coreutils"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// pluginConfigVersion is the version of the plugin configuration file schema.
+	pluginConfigVersion = 1
+	// envVarPrefix prefixes the environment variables setting flags, JFROG_SB_FLUNKY_SERVER_ID sets --server-id.
+	envVarPrefix = "JFROG_SB_FLUNKY_"
+)
+
+// pluginConfig is the plugin configuration file, holding named profiles.
+type pluginConfig struct {
+	Version  int                `json:"version" yaml:"version"`
+	Profiles map[string]profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// profile gives flag values by flag name, used when neither the flag nor its environment variable is set.
+type profile map[string]string
+
+// getPluginConfigPath gives the path of the plugin configuration file, in JFrog CLI home but outside of the plugins
+// directory, so that the profiles are kept when the plugin is uninstalled or reinstalled.
+func getPluginConfigPath() (string, error) {
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "sb-flunky", "config.yaml"), nil
+}
+
+// loadPluginConfig reads the plugin configuration file. A missing file is an empty configuration.
+func loadPluginConfig(path string) (*pluginConfig, error) {
+	config := &pluginConfig{Version: pluginConfigVersion, Profiles: map[string]profile{}}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.UnmarshalStrict(content, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if err = config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]profile{}
+	}
+	return config, nil
+}
+
+func (c *pluginConfig) validate() error {
+	if c.Version != pluginConfigVersion {
+		return fmt.Errorf("unsupported version %d, expected %d", c.Version, pluginConfigVersion)
+	}
+	for _, name := range c.profileNames() {
+		for flagName, value := range c.Profiles[name] {
+			if !isProfileFlag(flagName) {
+				return fmt.Errorf("profile %s: unknown flag %s", name, flagName)
+			}
+			if err := validateFlagValue(flagName, value); err != nil {
+				return fmt.Errorf("profile %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// save writes the plugin configuration file, readable by its owner only.
+func (c *pluginConfig) save(path string) error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0600)
+}
+
+func (c *pluginConfig) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// invocationFlags are not stored in profiles, so that a dry run or an output format chosen once does not stick.
+var invocationFlags = map[string]bool{profileFlag: true, unsetFlag: true, outputFlag: true, dryRunFlag: true}
+
+// isProfileFlag tells if a flag can be set in a profile.
+func isProfileFlag(flagName string) bool {
+	_, ok := flagDefinitions[flagName]
+	return ok && !invocationFlags[flagName]
+}
+
+// isEnvFlag tells if a flag can be set with an environment variable: every flag but the one editing a profile.
+func isEnvFlag(flagName string) bool {
+	_, ok := flagDefinitions[flagName]
+	return ok && flagName != unsetFlag
+}
+
+// profileFlagNames gives the sorted names of the flags that can be set in a profile.
+func profileFlagNames() []string {
+	var names []string
+	for name := range flagDefinitions {
+		if isProfileFlag(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateFlagValue(flagName string, value string) error {
+	if _, ok := flagDefinitions[flagName].(components.BoolFlag); ok {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid %s value %s, expected true or false", flagName, value)
+		}
+	}
+	return nil
+}
+
+// envVarName gives the name of the environment variable setting a flag.
+func envVarName(flagName string) string {
+	return envVarPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// flagSources gives the value of the flags of a command not set on the command line, from JFROG_SB_FLUNKY_*
+// environment variables and then from the selected profile.
+type flagSources struct {
+	lookupEnv func(key string) (string, bool)
+	profile   profile
+	// envFlags and profileFlags are the names of the flags of the command which can be set with an environment
+	// variable and in a profile. The other flags are ignored, as they do not apply to the command.
+	envFlags     map[string]bool
+	profileFlags map[string]bool
+}
+
+// newFlagSources selects the profile given with --profile or JFROG_SB_FLUNKY_PROFILE, and checks the environment
+// variables setting boolean flags of the command.
+func newFlagSources(flagProvider flagValueProvider, commandFlags []components.Flag,
+	lookupEnv func(key string) (string, bool), configPath string) (*flagSources, error) {
+	sources := &flagSources{lookupEnv: lookupEnv, envFlags: map[string]bool{}, profileFlags: map[string]bool{}}
+	for _, flag := range commandFlags {
+		flagName := flag.GetName()
+		sources.envFlags[flagName] = isEnvFlag(flagName)
+		sources.profileFlags[flagName] = isProfileFlag(flagName)
+		if !sources.envFlags[flagName] {
+			continue
+		}
+		if value, ok := lookupEnv(envVarName(flagName)); ok {
+			if err := validateFlagValue(flagName, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", envVarName(flagName), err)
+			}
+		}
+	}
+	profileName := strings.TrimSpace(flagProvider.GetStringFlagValue(profileFlag))
+	if profileName == "" {
+		profileName, _ = lookupEnv(envVarName(profileFlag))
+	}
+	if profileName == "" {
+		return sources, nil
+	}
+	config, err := loadPluginConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	selected, ok := config.Profiles[profileName]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found in %s, create it with 'jfrog sb-flunky config create %s'",
+			profileName, configPath, profileName)
+	}
+	sources.profile = selected
+	return sources, nil
+}
+
+// lookup gives the value of a flag of the command from its environment variable or the profile, if any.
+func (s *flagSources) lookup(flagName string) (string, bool) {
+	if s.envFlags[flagName] {
+		if value, ok := s.lookupEnv(envVarName(flagName)); ok {
+			return value, true
+		}
+	}
+	if !s.profileFlags[flagName] {
+		return "", false
+	}
+	value, ok := s.profile[flagName]
+	return value, ok
+}
+
+// stringValue gives the value of a string flag: value when the flag is set on the command line, its environment
+// variable or the profile otherwise. As JFrog CLI gives a flag not set on the command line its default value, set
+// tells whether the flag was actually given.
+func (s *flagSources) stringValue(flagName string, value string, set bool) string {
+	if set {
+		return value
+	}
+	if sourced, ok := s.lookup(flagName); ok {
+		return sourced
+	}
+	return value
+}
+
+// boolValue gives the value of a boolean flag, the same way as stringValue.
+func (s *flagSources) boolValue(flagName string, value bool, set bool) bool {
+	if set {
+		return value
+	}
+	if sourced, ok := s.lookup(flagName); ok {
+		// Already validated.
+		if parsed, err := strconv.ParseBool(sourced); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// givenFlags gives the names of the flags given in the arguments of the plugin, the way JFrog CLI parses them: with one
+// or two dashes, the value of a string flag being after an equal sign or the next argument. Arguments after "--" are
+// not flags.
+func givenFlags(args []string) map[string]bool {
+	given := map[string]bool{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if index := strings.Index(name, "="); index >= 0 {
+			given[name[:index]] = true
+			continue
+		}
+		given[name] = true
+		if _, ok := flagDefinitions[name].(components.StringFlag); ok {
+			// The next argument is the value of the flag.
+			i++
+		}
+	}
+	return given
+}
+
+// getEnvVars gives the environment variables setting the given flags, listed in the help of a command.
+func getEnvVars(flags []components.Flag) []components.EnvVar {
+	var envVars []components.EnvVar
+	for _, flag := range flags {
+		if isEnvFlag(flag.GetName()) {
+			envVars = append(envVars, components.EnvVar{Name: envVarName(flag.GetName()),
+				Description: fmt.Sprintf("Sets --%s when not given on the command line.", flag.GetName())})
+		}
+	}
+	return envVars
+}
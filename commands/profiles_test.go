@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_loadPluginConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectProfile profile
+		expectErr     string
+	}{
+		{
+			name:          "missing file",
+			expectProfile: nil,
+		},
+		{
+			name:          "profile",
+			content:       "version: 1\nprofiles:\n  prod:\n    server-id: prod\n    cleanup: false\n",
+			expectProfile: profile{"server-id": "prod", "cleanup": "false"},
+		},
+		{
+			name:      "unsupported version",
+			content:   "version: 2\n",
+			expectErr: "unsupported version 2, expected 1",
+		},
+		{
+			name:      "unknown flag",
+			content:   "version: 1\nprofiles:\n  prod:\n    server: prod\n",
+			expectErr: "profile prod: unknown flag server",
+		},
+		{
+			name:      "invalid boolean",
+			content:   "version: 1\nprofiles:\n  prod:\n    stream: maybe\n",
+			expectErr: "profile prod: invalid stream value maybe, expected true or false",
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			require.NoError(t, os.RemoveAll(path))
+			if test.content != "" {
+				require.NoError(t, ioutil.WriteFile(path, []byte(test.content), 0600))
+			}
+			config, err := loadPluginConfig(path)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, "invalid configuration file "+path+": "+test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, pluginConfigVersion, config.Version)
+			assert.Empty(t, cmp.Diff(test.expectProfile, config.Profiles["prod"]))
+		})
+	}
+}
+
+func Test_newFlagSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "config.yaml")
+	config := &pluginConfig{Version: pluginConfigVersion, Profiles: map[string]profile{
+		"prod": {"server-id": "prod", "target-repo": "support", "download-timeout": "30m", "cleanup": "false"},
+	}}
+	require.NoError(t, config.save(path))
+
+	tests := []struct {
+		name          string
+		command       components.Command
+		cli           *cliStub
+		env           map[string]string
+		expectServer  string
+		expectRepo    string
+		expectTimeout string
+		expectCleanup bool
+		expectErr     string
+	}{
+		{
+			name:          "no profile",
+			cli:           &cliStub{},
+			expectTimeout: "10m",
+			expectCleanup: true,
+		},
+		{
+			name:          "profile flag",
+			cli:           &cliStub{stringFlags: map[string]string{"profile": "prod"}},
+			expectServer:  "prod",
+			expectRepo:    "support",
+			expectTimeout: "30m",
+		},
+		{
+			name:          "profile environment variable",
+			cli:           &cliStub{},
+			env:           map[string]string{"JFROG_SB_FLUNKY_PROFILE": "prod"},
+			expectServer:  "prod",
+			expectRepo:    "support",
+			expectTimeout: "30m",
+		},
+		{
+			name: "flags and environment variables take precedence",
+			cli: &cliStub{
+				stringFlags: map[string]string{"profile": "prod", "server-id": "dev"},
+				boolFlags:   map[string]bool{"cleanup": true},
+			},
+			env: map[string]string{
+				"JFROG_SB_FLUNKY_TARGET_REPO":      "logs-dev",
+				"JFROG_SB_FLUNKY_DOWNLOAD_TIMEOUT": "1h",
+				"JFROG_SB_FLUNKY_CLEANUP":          "false",
+			},
+			expectServer:  "dev",
+			expectRepo:    "logs-dev",
+			expectTimeout: "1h",
+			expectCleanup: true,
+		},
+		{
+			name: "flags set to their default value take precedence",
+			cli: &cliStub{
+				stringFlags: map[string]string{"profile": "prod", "download-timeout": "10m"},
+				boolFlags:   map[string]bool{"cleanup": true},
+			},
+			expectServer:  "prod",
+			expectRepo:    "support",
+			expectTimeout: "10m",
+			expectCleanup: true,
+		},
+		{
+			name:          "flags of other commands are ignored",
+			command:       GetListCommand(),
+			cli:           &cliStub{stringFlags: map[string]string{"profile": "prod"}},
+			env:           map[string]string{"JFROG_SB_FLUNKY_TARGET_REPO": "logs-dev", "JFROG_SB_FLUNKY_STREAM": "maybe"},
+			expectServer:  "prod",
+			expectTimeout: "10m",
+			expectCleanup: true,
+		},
+		{
+			name:      "unknown profile",
+			cli:       &cliStub{stringFlags: map[string]string{"profile": "staging"}},
+			expectErr: "profile staging not found in " + path + ", create it with 'jfrog sb-flunky config create staging'",
+		},
+		{
+			name:      "invalid boolean environment variable",
+			cli:       &cliStub{},
+			env:       map[string]string{"JFROG_SB_FLUNKY_STREAM": "yes please"},
+			expectErr: "JFROG_SB_FLUNKY_STREAM: invalid stream value yes please, expected true or false",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := test.env[key]
+				return value, ok
+			}
+			command := test.command
+			if command.Name == "" {
+				command = GetSupportBundleCommand()
+			}
+			sources, err := newFlagSources(test.cli, command.Flags, lookupEnv, path)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			// JFrog CLI gives the flags not set on the command line their default value.
+			stringValue := func(flagName string, defaultValue string) string {
+				value := test.cli.GetStringFlagValue(flagName)
+				if value == "" {
+					value = defaultValue
+				}
+				return sources.stringValue(flagName, value, test.cli.IsFlagSet(flagName))
+			}
+			assert.Equal(t, test.expectServer, stringValue("server-id", ""))
+			assert.Equal(t, test.expectRepo, stringValue("target-repo", ""))
+			assert.Equal(t, test.expectTimeout, stringValue("download-timeout", "10m"))
+			assert.Equal(t, test.expectCleanup, sources.boolValue("cleanup", true, test.cli.IsFlagSet("cleanup")))
+		})
+	}
+}
+
+func Test_newFlagSources_invocationFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("version: 1\nprofiles:\n  prod:\n    server-id: prod\n"), 0600))
+	env := map[string]string{"JFROG_SB_FLUNKY_PROFILE": "prod", "JFROG_SB_FLUNKY_DRY_RUN": "true",
+		"JFROG_SB_FLUNKY_OUTPUT": "json"}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	sources, err := newFlagSources(&cliStub{}, GetPruneCommand().Flags, lookupEnv, path)
+	require.NoError(t, err)
+	assert.True(t, sources.boolValue("dry-run", false, false))
+	assert.Equal(t, "json", sources.stringValue("output", "text", false))
+	assert.Equal(t, "yaml", sources.stringValue("output", "yaml", true))
+	assert.Equal(t, "prod", sources.stringValue("server-id", "", false))
+	assert.Equal(t, "prod", sources.stringValue("profile", "", false))
+
+	env["JFROG_SB_FLUNKY_DRY_RUN"] = "maybe"
+	_, err = newFlagSources(&cliStub{}, GetPruneCommand().Flags, lookupEnv, path)
+	assert.EqualError(t, err, "JFROG_SB_FLUNKY_DRY_RUN: invalid dry-run value maybe, expected true or false")
+}
+
+func Test_givenFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		expect map[string]bool
+	}{
+		{name: "none", args: []string{"support-case", "1234"}, expect: map[string]bool{}},
+		{
+			name:   "with values",
+			args:   []string{"support-case", "1234", "--target-repo=logs", "-cleanup=true", "--dry-run"},
+			expect: map[string]bool{"target-repo": true, "cleanup": true, "dry-run": true},
+		},
+		{
+			name:   "value as next argument",
+			args:   []string{"upload", "--target-repo", "--stream", "1234", "--stream", "file.zip"},
+			expect: map[string]bool{"target-repo": true, "stream": true},
+		},
+		{
+			name:   "arguments after the terminator",
+			args:   []string{"upload", "1234", "--", "--cleanup"},
+			expect: map[string]bool{},
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, givenFlags(test.args))
+		})
+	}
+}
+
+func Test_getPluginConfigPath(t *testing.T) {
+	previous, wasSet := os.LookupEnv(coreutils.HomeDir)
+	defer func() {
+		if wasSet {
+			require.NoError(t, os.Setenv(coreutils.HomeDir, previous))
+		} else {
+			require.NoError(t, os.Unsetenv(coreutils.HomeDir))
+		}
+	}()
+	require.NoError(t, os.Setenv(coreutils.HomeDir, filepath.Join("home", ".jfrog")))
+
+	path, err := getPluginConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("home", ".jfrog", "sb-flunky", "config.yaml"), path)
+}
+
+func Test_getEnvVars(t *testing.T) {
+	assert.Equal(t, []components.EnvVar{
+		{Name: "JFROG_SB_FLUNKY_TARGET_REPO", Description: "Sets --target-repo when not given on the command line."},
+		{Name: "JFROG_SB_FLUNKY_OUTPUT", Description: "Sets --output when not given on the command line."},
+		{Name: "JFROG_SB_FLUNKY_PROFILE", Description: "Sets --profile when not given on the command line."},
+	}, getEnvVars(getFlags(targetRepoFlag, outputFlag, unsetFlag, profileFlag)))
+}
+
+func Test_envVarName(t *testing.T) {
+	assert.Equal(t, "JFROG_SB_FLUNKY_TARGET_SERVER_ID", envVarName("target-server-id"))
+}
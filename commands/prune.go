@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetPruneCommand returns the description of the "prune" command.
+func GetPruneCommand() components.Command {
+	flags := getFlags(serverIDFlag, keepLastFlag, olderThanFlag, flunkyOnlyFlag, maxAttemptsFlag,
+		retryIntervalFlag, maxRetryIntervalFlag, dryRunFlag, outputFlag, profileFlag)
+	return components.Command{
+		Name:        "prune",
+		Description: "Deletes the Support Bundles of the source Artifactory service that are not retained",
+		Arguments:   nil,
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
+		Action:      pruneCmd,
+	}
+}
+
+func pruneCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetPruneCommand().Flags)
+	if err != nil {
+		return err
+	}
+	format, err := getOutputFormat(cli)
+	if err != nil {
+		return toCliError(configurationError(err))
+	}
+	pruned, pruneErr := PruneCmd(context.Background(), cli)
+	if pruneErr != nil && len(pruned) == 0 {
+		// Nothing was selected before the failure, an empty plan or table would be misleading.
+		return toCliError(pruneErr)
+	}
+	output, err := formatOutput(format, pruned, func(w io.Writer) error {
+		if isDryRun(cli) {
+			_, err := fmt.Fprintln(w, "Dry run, the following Support Bundles would be deleted:")
+			if err != nil {
+				return err
+			}
+		}
+		return writeSupportBundlesTable(w, pruned)
+	})
+	if err != nil {
+		return err
+	}
+	log.Output(output)
+	return toCliError(pruneErr)
+}
+
+// PruneCmd deletes the Support Bundles of the source Artifactory service that are not retained by the retention
+// policy, and returns them.
+func PruneCmd(ctx context.Context, cli CliFacade) ([]flunkyhttp.SupportBundleDetails, error) {
+	policy, err := getRetentionPolicy(cli)
+	if err != nil {
+		return nil, configurationError(err)
+	}
+
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		return nil, configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
+
+	pruned, err := actions.PruneSupportBundles(ctx, client, policy, isDryRun(cli), time.Now)
+	return pruned, requestError(err)
+}
+
+func getRetentionPolicy(flagProvider flagValueProvider) (actions.RetentionPolicy, error) {
+	policy := actions.RetentionPolicy{FlunkyOnly: flagProvider.GetBoolFlagValue(flunkyOnlyFlag)}
+	if value := flagProvider.GetStringFlagValue(keepLastFlag); value != "" {
+		keepLast, err := strconv.Atoi(value)
+		if err != nil || keepLast < 0 {
+			return policy, fmt.Errorf("invalid %s value %s, expected a positive number", keepLastFlag, value)
+		}
+		policy.KeepLast = keepLast
+	}
+	if value := flagProvider.GetStringFlagValue(olderThanFlag); value != "" {
+		olderThan, err := parseDurationWithDays(value)
+		if err != nil || olderThan < 0 {
+			return policy, fmt.Errorf("invalid %s value %s, expected a duration such as 36h or 30d", olderThanFlag,
+				value)
+		}
+		policy.OlderThan = olderThan
+	}
+	if policy.KeepLast <= 0 && policy.OlderThan <= 0 {
+		return policy, fmt.Errorf("at least one of the %s or %s retention rules is required", keepLastFlag,
+			olderThanFlag)
+	}
+	return policy, nil
+}
+
+// parseDurationWithDays parses a duration, also accepting a number of days such as "30d".
+func parseDurationWithDays(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func isDryRun(flagProvider flagValueProvider) bool {
+	return flagProvider.GetBoolFlagValue(dryRunFlag)
+}
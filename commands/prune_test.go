@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_getRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		stringFlags  map[string]string
+		boolFlags    map[string]bool
+		expectPolicy actions.RetentionPolicy
+		expectErr    string
+	}{
+		{
+			name:      "no rule",
+			boolFlags: map[string]bool{"flunky-only": true},
+			expectErr: "at least one of the keep-last or older-than retention rules is required",
+		},
+		{
+			name:         "all rules",
+			stringFlags:  map[string]string{"keep-last": "3", "older-than": "30d"},
+			boolFlags:    map[string]bool{"flunky-only": true},
+			expectPolicy: actions.RetentionPolicy{KeepLast: 3, OlderThan: 30 * 24 * time.Hour, FlunkyOnly: true},
+		},
+		{
+			name:         "older than hours",
+			stringFlags:  map[string]string{"older-than": "36h"},
+			expectPolicy: actions.RetentionPolicy{OlderThan: 36 * time.Hour},
+		},
+		{
+			name:        "invalid keep last",
+			stringFlags: map[string]string{"keep-last": "-1"},
+			expectErr:   "invalid keep-last value -1, expected a positive number",
+		},
+		{
+			name:        "invalid older than",
+			stringFlags: map[string]string{"older-than": "a month"},
+			expectErr:   "invalid older-than value a month, expected a duration such as 36h or 30d",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := getRetentionPolicy(&cliStub{stringFlags: test.stringFlags, boolFlags: test.boolFlags})
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectPolicy, policy)
+			}
+		})
+	}
+}
+
+func Test_PruneCmd_NoRule(t *testing.T) {
+	_, err := PruneCmd(context.Background(), &cliStub{rtDetails: &config.ArtifactoryDetails{Url: "http://localhost/"}})
+
+	assert.EqualError(t, err, "at least one of the keep-last or older-than retention rules is required")
+	assert.Equal(t, actions.CategoryConfiguration, actions.CategoryOf(err))
+	assert.Equal(t, 10, exitCodeOf(err))
+}
+
+func Test_PruneCmd_DryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/system/support/bundles":
+			_, _ = w.Write([]byte(`{"count":2,"bundles":[` +
+				`{"id":"1","name":"n","created":"2020-12-01T10:00:00Z"},` +
+				`{"id":"2","name":"n","created":"2020-12-02T10:00:00Z"}]}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"status":"success"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	pruned, err := PruneCmd(context.Background(), &cliStub{
+		stringFlags: map[string]string{"keep-last": "1"},
+		boolFlags:   map[string]bool{"dry-run": true},
+		rtDetails:   &config.ArtifactoryDetails{Url: ts.URL + "/"},
+	})
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "1", pruned[0].ID)
+}
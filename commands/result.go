@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"strings"
+	"time"
+)
+
+// supportCaseResultVersion is the version of the result document of support-case. It changes whenever a field is
+// renamed, removed or changes meaning, not when one is added.
+const supportCaseResultVersion = 1
+
+// Phases of support-case, as named in the result document.
+const (
+	phaseCreate   = "create"
+	phaseDownload = "download"
+	phaseUpload   = "upload"
+	phaseStream   = "stream"
+)
+
+// PhaseResult tells when a phase of support-case ran, how long it took and why it failed if it did.
+type PhaseResult struct {
+	Name       string        `json:"name" yaml:"name"`
+	Start      time.Time     `json:"start" yaml:"start"`
+	DurationMs int64         `json:"durationMs" yaml:"durationMs"`
+	Error      *ErrorDetails `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ErrorDetails describes an error in the result document.
+type ErrorDetails struct {
+	Message string `json:"message" yaml:"message"`
+	// Category is the category of the error, if known, see actions.ErrorCategory.
+	Category actions.ErrorCategory `json:"category,omitempty" yaml:"category,omitempty"`
+	// ExitCode is the exit code of the command for this error.
+	ExitCode int `json:"exitCode" yaml:"exitCode"`
+	// HTTPStatus is the status Artifactory answered with, if the error is due to one.
+	HTTPStatus int `json:"httpStatus,omitempty" yaml:"httpStatus,omitempty"`
+}
+
+// supportCaseResult is the result document printed by support-case with --output=json or yaml. Its fields are always
+// given, even when empty, so that scripts parsing it do not have to guess.
+type supportCaseResult struct {
+	Version       int                                `json:"version" yaml:"version"`
+	DryRun        bool                               `json:"dryRun" yaml:"dryRun"`
+	CaseNumber    string                             `json:"caseNumber" yaml:"caseNumber"`
+	BundleID      string                             `json:"bundleId" yaml:"bundleId"`
+	SourceURL     string                             `json:"sourceUrl" yaml:"sourceUrl"`
+	TargetURL     string                             `json:"targetUrl" yaml:"targetUrl"`
+	UploadURL     string                             `json:"uploadUrl" yaml:"uploadUrl"`
+	LocalFilePath string                             `json:"localFilePath" yaml:"localFilePath"`
+	Size          int64                              `json:"size" yaml:"size"`
+	Checksums     http.Checksums                     `json:"checksums" yaml:"checksums"`
+	Options       *http.SupportBundleCreationOptions `json:"options" yaml:"options"`
+	Phases        []PhaseResult                      `json:"phases" yaml:"phases"`
+	Plan          *actions.ExecutionPlan             `json:"plan,omitempty" yaml:"plan,omitempty"`
+	// Error is why support-case failed, whether in a phase or before any.
+	Error *ErrorDetails `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// runPhase runs a phase of support-case, recording how long it took and its error if any.
+func (r *SupportBundleCmdResult) runPhase(name string, run func() error) error {
+	start := time.Now()
+	err := run()
+	phase := PhaseResult{Name: name, Start: start.UTC(), DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		phase.Error = newErrorDetails(err)
+	}
+	r.Phases = append(r.Phases, phase)
+	return err
+}
+
+func newErrorDetails(err error) *ErrorDetails {
+	details := &ErrorDetails{Message: err.Error(), Category: actions.CategoryOf(err), ExitCode: exitCodeOf(err)}
+	if details.ExitCode == 0 {
+		details.ExitCode = 1
+	}
+	var httpErr *actions.HTTPError
+	if errors.As(err, &httpErr) {
+		details.HTTPStatus = httpErr.StatusCode
+	}
+	return details
+}
+
+// newSupportCaseResult gives the result document of a run of support-case, which may have failed before any result
+// was known.
+func newSupportCaseResult(cli CliFacade, r *SupportBundleCmdResult, err error) *supportCaseResult {
+	document := &supportCaseResult{Version: supportCaseResultVersion, DryRun: isDryRun(cli), Phases: []PhaseResult{}}
+	if arguments := cli.GetArguments(); len(arguments) == 1 {
+		document.CaseNumber = strings.TrimSpace(arguments[0])
+	}
+	if r != nil {
+		document.CaseNumber = string(r.CaseNumber)
+		document.BundleID = string(r.BundleID)
+		document.SourceURL = r.SourceURL
+		document.TargetURL = r.TargetURL
+		document.UploadURL = r.UploadURL
+		document.LocalFilePath = r.LocalFilePath
+		document.Size = r.Size
+		document.Checksums = r.Checksums
+		document.Options = r.Options
+		document.Plan = r.Plan
+		if r.Phases != nil {
+			document.Phases = r.Phases
+		}
+	}
+	if err != nil {
+		document.Error = newErrorDetails(err)
+	}
+	return document
+}
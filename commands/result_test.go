@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_runPhase(t *testing.T) {
+	result := &SupportBundleCmdResult{}
+	require.NoError(t, result.runPhase(phaseCreate, func() error {
+		return nil
+	}))
+	err := result.runPhase(phaseDownload, func() error {
+		return errors.New("connection reset")
+	})
+	assert.EqualError(t, err, "connection reset")
+
+	require.Len(t, result.Phases, 2)
+	assert.Equal(t, phaseCreate, result.Phases[0].Name)
+	assert.Nil(t, result.Phases[0].Error)
+	assert.Equal(t, time.UTC, result.Phases[0].Start.Location())
+	assert.Equal(t, phaseDownload, result.Phases[1].Name)
+	assert.Equal(t, &ErrorDetails{Message: "connection reset", ExitCode: 1}, result.Phases[1].Error)
+}
+
+func Test_newSupportCaseResult(t *testing.T) {
+	start := time.Date(2020, 12, 3, 22, 10, 0, 0, time.UTC)
+	result := &SupportBundleCmdResult{
+		CaseNumber: "1234",
+		SourceURL:  "http://source/",
+		TargetURL:  "http://target/",
+		Options: &http.SupportBundleCreationOptions{Name: "n", Description: "d", Parameters: &http.SupportBundleParameters{
+			Configuration: true,
+		}},
+		BundleID:      "20201203-2210-0001",
+		LocalFilePath: "/tmp/sb.zip",
+		Size:          2048,
+		Checksums:     http.Checksums{SHA256: "a", SHA1: "b", MD5: "c"},
+		Phases: []PhaseResult{
+			{Name: phaseCreate, Start: start, DurationMs: 1500},
+			{Name: phaseDownload, Start: start.Add(2 * time.Second), DurationMs: 600000,
+				Error: &ErrorDetails{Message: "timeout", Category: actions.CategoryDownloadTimeout, ExitCode: 14}},
+		},
+	}
+
+	output, err := formatOutput(jsonOutput, newSupportCaseResult(&cliStub{arguments: []string{"1234"}}, result,
+		actions.Categorize(actions.CategoryDownloadTimeout, errors.New("timeout"))), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"dryRun": false,
+		"caseNumber": "1234",
+		"bundleId": "20201203-2210-0001",
+		"sourceUrl": "http://source/",
+		"targetUrl": "http://target/",
+		"uploadUrl": "",
+		"localFilePath": "/tmp/sb.zip",
+		"size": 2048,
+		"checksums": {"sha256": "a", "sha1": "b", "md5": "c"},
+		"options": {"name": "n", "description": "d",
+			"parameters": {"configuration": true, "logs": null, "system": false, "thread_dump": null}},
+		"phases": [
+			{"name": "create", "start": "2020-12-03T22:10:00Z", "durationMs": 1500},
+			{"name": "download", "start": "2020-12-03T22:10:02Z", "durationMs": 600000,
+				"error": {"message": "timeout", "category": "download-timeout", "exitCode": 14}}
+		],
+		"error": {"message": "timeout", "category": "download-timeout", "exitCode": 14}
+	}`, output)
+}
+
+func Test_newSupportCaseResult_failedEarly(t *testing.T) {
+	cli := &cliStub{arguments: []string{" 1234 "}, boolFlags: map[string]bool{"dry-run": true}}
+	document := newSupportCaseResult(cli, nil, errors.New("failed to get RT details"))
+	assert.Equal(t, &supportCaseResult{
+		Version:    supportCaseResultVersion,
+		DryRun:     true,
+		CaseNumber: "1234",
+		Phases:     []PhaseResult{},
+		Error:      &ErrorDetails{Message: "failed to get RT details", ExitCode: 1},
+	}, document)
+
+	output, err := formatOutput(yamlOutput, newSupportCaseResult(&cliStub{}, &SupportBundleCmdResult{
+		CaseNumber: "1234",
+		Plan:       &actions.ExecutionPlan{UploadURL: "http://target/logs/1234/sb.zip"},
+	}, nil), nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "caseNumber: \"1234\"\n")
+	assert.Contains(t, output, "plan:\n  sourceUrl: \"\"\n  targetUrl: \"\"\n  payload:\n")
+	assert.NotContains(t, output, "error:")
+}
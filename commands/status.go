@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+)
+
+// GetStatusCommand returns the description of the "status" command.
+func GetStatusCommand() components.Command {
+	flags := getFlags(serverIDFlag, maxAttemptsFlag, retryIntervalFlag, maxRetryIntervalFlag, profileFlag)
+	return components.Command{
+		Name:        "status",
+		Description: "Prints the status of the creation of a Support Bundle",
+		Arguments:   []components.Argument{bundleIDArgument()},
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
+		Action:      statusCmd,
+	}
+}
+
+func statusCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetStatusCommand().Flags)
+	if err != nil {
+		return err
+	}
+	status, err := StatusCmd(context.Background(), cli)
+	if err != nil {
+		return toCliError(err)
+	}
+	if status.Message != "" {
+		log.Output(fmt.Sprintf("%s: %s", status.Reported, status.Message))
+	} else {
+		log.Output(status.Reported)
+	}
+	return nil
+}
+
+// StatusCmd gets the status of a Support Bundle from the source Artifactory service.
+func StatusCmd(ctx context.Context, cli CliFacade) (actions.SupportBundleStatus, error) {
+	bundleID, err := parseBundleIDArgument(cli)
+	if err != nil {
+		return actions.SupportBundleStatus{}, configurationError(err)
+	}
+
+	client, err := getRtClient(cli, cli.GetRtDetails)
+	if err != nil {
+		return actions.SupportBundleStatus{}, configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
+
+	status, err := actions.GetSupportBundleStatus(ctx, client, bundleID)
+	return status, requestError(err)
+}
+
+func bundleIDArgument() components.Argument {
+	return components.Argument{
+		Name:        "bundle-id",
+		Description: "ID of the Support Bundle on the source Artifactory service.",
+	}
+}
+
+func parseBundleIDArgument(ctx argumentsProvider) (actions.BundleID, error) {
+	arguments, err := getTrimmedArguments(ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	return actions.BundleID(arguments[0]), nil
+}
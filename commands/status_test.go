@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_GetStatusCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "status",
+		Description: "Prints the status of the creation of a Support Bundle",
+		Arguments: []components.Argument{
+			{
+				Name:        "bundle-id",
+				Description: "ID of the Support Bundle on the source Artifactory service.",
+			},
+		},
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "server-id",
+				Description: "Artifactory server ID configured using the config command. " +
+					"If not provided the default configuration will be used.",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetStatusCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
+
+func Test_parseBundleIDArgument(t *testing.T) {
+	tests := []struct {
+		name          string
+		argsProvider  args
+		expected      actions.BundleID
+		expectedError string
+	}{
+		{
+			name:         "parse valid argument with whitespace",
+			argsProvider: []string{" 20201201-1234 "},
+			expected:     "20201201-1234",
+		},
+		{
+			name:          "not enough arguments",
+			argsProvider:  nil,
+			expectedError: "wrong number of arguments. Expected: 1, Received: 0",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			bundleID, err := parseBundleIDArgument(test.argsProvider)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, bundleID)
+			}
+		})
+	}
+}
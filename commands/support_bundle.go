@@ -6,103 +6,130 @@ import (
 	"github.com/jfrog/jfrog-cli-core/artifactory/commands"
 	"github.com/jfrog/jfrog-cli-core/plugins/components"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
-const (
-	serverIDFlag        = "server-id"
-	targetServerIDFlag  = "target-server-id"
-	downloadTimeoutFlag = "download-timeout"
-	retryIntervalFlag   = "retry-interval"
-	promptOptionsFlag   = "prompt-options"
-	cleanupFlag         = "cleanup"
-	targetRepoFlag      = "target-repo"
-)
-
 // GetSupportBundleCommand returns the description of the "support-bundle" command.
 func GetSupportBundleCommand() components.Command {
+	flags := getFlags(serverIDFlag, targetServerIDFlag, downloadTimeoutFlag, createTimeoutFlag, transferTimeoutFlag,
+		uploadTimeoutFlag, deadlineFlag, maxAttemptsFlag, retryIntervalFlag, maxRetryIntervalFlag,
+		promptOptionsFlag, answersFileFlag, optionsFileFlag, presetFlag, logsSinceFlag, logsFromFlag, logsToFlag,
+		threadDumpsFlag, threadDumpIntervalFlag, timezoneFlag, cleanupFlag, cleanupRemoteFlag, targetRepoFlag,
+		nameTemplateFlag, streamFlag, propertyFlag, dryRunFlag, outputFlag, profileFlag)
 	return components.Command{
 		Name:        "support-case",
 		Description: `Creates a Support Bundle and uploads it to JFrog Support "dropbox" service`,
 		Aliases:     []string{"c", "case"},
 		Arguments:   getArguments(),
-		Flags:       getFlags(),
-		EnvVars:     nil,
+		Flags:       flags,
+		EnvVars:     getEnvVars(flags),
 		Action:      supportBundleCmd,
 	}
 }
 
 func getArguments() []components.Argument {
-	return []components.Argument{
-		{
-			Name:        "case",
-			Description: "JFrog Support case number.",
-		},
-	}
-}
-
-func getFlags() []components.Flag {
-	return []components.Flag{
-		components.StringFlag{
-			Name: serverIDFlag,
-			Description: "Artifactory server ID configured using the config command. " +
-				"If not provided the default configuration will be used.",
-		},
-		components.StringFlag{
-			Name: targetServerIDFlag,
-			Description: "Artifactory server ID configured using the config command to be used as the target for " +
-				"uploading the generated Support Bundle. If not provided JFrog support logs will be used.",
-		},
-		components.StringFlag{
-			Name:         downloadTimeoutFlag,
-			Description:  "The timeout for download.",
-			DefaultValue: "10m",
-		},
-		components.StringFlag{
-			Name:         retryIntervalFlag,
-			Description:  "The duration to wait between retries.",
-			DefaultValue: "5s",
-		},
-		components.BoolFlag{
-			Name:        promptOptionsFlag,
-			Description: "Ask for support bundle options or use Artifactory default options.",
-		},
-		components.BoolFlag{
-			Name:         cleanupFlag,
-			Description:  "Delete the support bundle local temp file after upload.",
-			DefaultValue: true,
-		},
-		components.StringFlag{
-			Name:         targetRepoFlag,
-			Description:  "The target repository key where the support bundle will be uploaded to.",
-			DefaultValue: "logs",
-		},
+	return []components.Argument{caseArgument()}
+}
+
+func caseArgument() components.Argument {
+	return components.Argument{
+		Name:        "case",
+		Description: "JFrog Support case number.",
 	}
 }
 
 func supportBundleCmd(componentContext *components.Context) error {
-	r, err := SupportBundleCmd(context.Background(), &cliAdapter{ctx: componentContext})
+	adapter, err := newCliAdapter(componentContext, GetSupportBundleCommand().Flags)
 	if err != nil {
 		return err
 	}
+	var cli CliFacade = adapter
+	format, err := getOutputFormat(cli)
+	if err != nil {
+		return toCliError(configurationError(err))
+	}
+	if len(componentContext.Arguments) == 0 {
+		servers, err := config.GetAllArtifactoryConfigs()
+		if err != nil {
+			return err
+		}
+		prompter, err := adapter.GetPrompter()
+		if err != nil {
+			return err
+		}
+		cli, err = runWizard(adapter, adapter, prompter, servers)
+		if err != nil {
+			return err
+		}
+	}
+	r, err := runInterruptible(cli)
+	if format != textOutput {
+		output, formatErr := formatOutput(format, newSupportCaseResult(cli, r, err), nil)
+		if formatErr != nil {
+			return formatErr
+		}
+		log.Output(output)
+		return toCliError(err)
+	}
+	if err != nil {
+		return toCliError(err)
+	}
+	if r.Plan != nil {
+		plan, err := formatExecutionPlan(r.Plan)
+		if err != nil {
+			return err
+		}
+		log.Output(plan)
+		return nil
+	}
 	log.Output(r.UploadURL)
 	return err
 }
 
 type cliAdapter struct {
-	ctx *components.Context
+	ctx      *components.Context
+	sources  *flagSources
+	prompter actions.FullPrompter
+	// given are the names of the flags given on the command line.
+	given map[string]bool
+}
+
+// newCliAdapter creates a cliAdapter giving the flags of the command not set on the command line their value from
+// environment variables or from the selected profile.
+func newCliAdapter(ctx *components.Context, commandFlags []components.Flag) (*cliAdapter, error) {
+	adapter := &cliAdapter{ctx: ctx, given: givenFlags(os.Args[1:])}
+	configPath, err := getPluginConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	adapter.sources, err = newFlagSources(adapter, commandFlags, os.LookupEnv, configPath)
+	if err != nil {
+		return nil, err
+	}
+	return adapter, nil
 }
 
 func (p *cliAdapter) GetStringFlagValue(flagName string) string {
-	return p.ctx.GetStringFlagValue(flagName)
+	if p.sources == nil {
+		return p.ctx.GetStringFlagValue(flagName)
+	}
+	return p.sources.stringValue(flagName, p.ctx.GetStringFlagValue(flagName), p.IsFlagSet(flagName))
 }
 func (p *cliAdapter) GetBoolFlagValue(flagName string) bool {
-	return p.ctx.GetBoolFlagValue(flagName)
+	if p.sources == nil {
+		return p.ctx.GetBoolFlagValue(flagName)
+	}
+	return p.sources.boolValue(flagName, p.ctx.GetBoolFlagValue(flagName), p.IsFlagSet(flagName))
+}
+func (p *cliAdapter) IsFlagSet(flagName string) bool {
+	return p.given[flagName]
 }
 func (p *cliAdapter) GetArguments() []string {
 	return p.ctx.Arguments
@@ -113,10 +140,25 @@ func (p *cliAdapter) GetRtDetails() (*config.ArtifactoryDetails, error) {
 func (p *cliAdapter) GetTargetDetails() (*config.ArtifactoryDetails, error) {
 	return getTargetDetails(p, p)
 }
+func (p *cliAdapter) GetPrompter() (actions.FullPrompter, error) {
+	if p.prompter == nil {
+		// Answers can only be read once from stdin, the same prompter is used for every question.
+		prompter, err := getPrompter(p, os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		p.prompter = prompter
+	}
+	return p.prompter, nil
+}
 func (p *cliAdapter) GetConfig(serverID string, excludeRefreshableTokens bool) (*config.ArtifactoryDetails, error) {
 	return commands.GetConfig(serverID, excludeRefreshableTokens)
 }
 func (p *cliAdapter) CreateInitialRefreshableTokensIfNeeded(artifactoryDetails *config.ArtifactoryDetails) error {
+	if isDryRun(p) {
+		// Creating tokens would send a request to the service.
+		return nil
+	}
 	return config.CreateInitialRefreshableTokensIfNeeded(artifactoryDetails)
 }
 
@@ -125,77 +167,223 @@ type CliFacade interface {
 	flagValueProvider
 	argumentsProvider
 	artifactoryDetailsProvider
+	prompterProvider
 }
 
 // SupportBundleCmdResult gives details on what the command has done
 type SupportBundleCmdResult struct {
+	CaseNumber    actions.CaseNumber
+	SourceURL     string
+	TargetURL     string
+	Options       *http.SupportBundleCreationOptions
 	BundleID      actions.BundleID
 	LocalFilePath string
 	UploadURL     string
+	Size          int64
+	Checksums     http.Checksums
+	// Phases are the phases run so far, the last one being the one that failed if any.
+	Phases []PhaseResult
+	// Plan is what would be done, only set in dry-run mode where nothing else is done.
+	Plan *actions.ExecutionPlan
 }
 
-// SupportBundleCmd is the core of the command
+// SupportBundleCmd is the core of the command. It is aborted once ctx is done or the deadline set with --deadline
+// elapsed.
 func SupportBundleCmd(ctx context.Context, cli CliFacade) (*SupportBundleCmdResult, error) {
+	deadline, err := getDeadline(cli)
+	if err != nil {
+		return nil, configurationError(err)
+	}
+	var result *SupportBundleCmdResult
+	err = actions.RunWithTimeout(ctx, "Support case", deadline, func(ctx context.Context) (err error) {
+		result, err = runSupportCase(ctx, cli)
+		return err
+	})
+	return result, err
+}
+
+func runSupportCase(ctx context.Context, cli CliFacade) (*SupportBundleCmdResult, error) {
 	caseNumber, err := parseArguments(cli)
 	if err != nil {
-		return nil, err
+		return nil, configurationError(err)
 	}
 	log.Debug(fmt.Sprintf("Case number is %s", caseNumber))
+	customProperties, err := getCustomProperties(cli)
+	if err != nil {
+		return nil, configurationError(err)
+	}
 
-	client, err := getRtClient(cli.GetRtDetails)
+	client, err := getRtClient(cli, cli.GetRtDetails)
 	if err != nil {
-		return nil, err
+		return nil, configurationError(err)
 	}
 	log.Debug(fmt.Sprintf("Selected Artifactory: %s", client.GetURL()))
 
-	targetClient, err := getRtClient(cli.GetTargetDetails)
+	targetClient, err := getRtClient(cli, cli.GetTargetDetails)
 	if err != nil {
-		return nil, err
+		return nil, configurationError(err)
 	}
 	log.Debug(fmt.Sprintf("Selected \"dropbox\" Artifactory: %s", targetClient.GetURL()))
 
-	result := &SupportBundleCmdResult{}
+	target := actions.UploadTarget{
+		RepoKey:      getTargetRepo(cli),
+		CaseNumber:   caseNumber,
+		NameTemplate: getNameTemplate(cli),
+		ServerID:     client.RtDetails.ServerId,
+		Hostname:     getHostname(),
+	}
+	if err = target.Validate(); err != nil {
+		return nil, configurationError(err)
+	}
+	timeouts, err := getPhaseTimeouts(cli)
+	if err != nil {
+		return nil, configurationError(err)
+	}
+
+	result := &SupportBundleCmdResult{CaseNumber: caseNumber, SourceURL: client.GetURL(), TargetURL: targetClient.GetURL()}
 	// 1. Create Support Bundle
-	result.BundleID, err = actions.CreateSupportBundle(client, caseNumber, getPromptOptions(cli))
+	optionsProvider, err := getOptionsProvider(cli)
+	if err != nil {
+		return result, configurationError(err)
+	}
+	options, err := optionsProvider.GetOptions(caseNumber)
+	if err != nil {
+		return result, configurationError(err)
+	}
+	result.Options = &options
+	if isDryRun(cli) {
+		target.Properties = getUploadProperties(client, options, "", "", customProperties)
+		result.Plan, err = actions.PlanSupportBundle(client, targetClient, options, target, shouldStream(cli), time.Now)
+		return result, err
+	}
+	err = result.runPhase(phaseCreate, func() error {
+		run := func(ctx context.Context) (err error) {
+			result.BundleID, err = actions.CreateSupportBundleWithOptions(ctx, client, options)
+			return err
+		}
+		return actions.RunWithTimeout(ctx, createTimeoutPhase, timeouts.create, run)
+	})
 	if err != nil {
 		return result, err
 	}
+	target.Properties = getUploadProperties(client, options, getSourceVersion(ctx, client), result.BundleID,
+		customProperties)
+
+	err = transferSupportBundle(ctx, cli, timeouts, client, targetClient, target, result)
+	return result, err
+}
+
+// transferSupportBundle downloads the created Support Bundle and uploads it to the target, or streams it from one to
+// the other.
+func transferSupportBundle(ctx context.Context, cli CliFacade, timeouts phaseTimeouts, client *http.Client,
+	targetClient *http.Client, target actions.UploadTarget, result *SupportBundleCmdResult) error {
+	if shouldStream(cli) {
+		// 2. and 3. Stream Support Bundle from source to target
+		return result.runPhase(phaseStream, func() error {
+			streamed, err := actions.StreamSupportBundle(ctx, client, targetClient, timeouts.download,
+				client.Retry, result.BundleID, target, time.Now)
+			result.UploadURL = streamed.UploadURL
+			result.Size = streamed.Size
+			result.Checksums = streamed.Checksums
+			return err
+		})
+	}
 
 	// 2. Download Support Bundle
-	result.LocalFilePath, err = actions.DownloadSupportBundle(ctx, client, getTimeout(cli),
-		getRetryInterval(cli), result.BundleID)
+	err := result.runPhase(phaseDownload, func() (err error) {
+		result.LocalFilePath, result.Checksums, err = actions.DownloadSupportBundle(ctx, client, timeouts.download,
+			client.Retry, result.BundleID)
+		return err
+	})
 	if err != nil {
-		return result, err
+		return err
+	}
+	if info, err := os.Stat(result.LocalFilePath); err == nil {
+		result.Size = info.Size()
 	}
 	if shouldCleanup(cli) {
 		defer deleteSupportBundleArchive(result.LocalFilePath)
 	}
 
 	// 3. Upload Support Bundle
-	result.UploadURL, err = actions.UploadSupportBundle(targetClient, caseNumber, result.LocalFilePath, getTargetRepo(cli), time.Now)
-	return result, err
+	return result.runPhase(phaseUpload, func() error {
+		run := func(ctx context.Context) (err error) {
+			result.UploadURL, err = actions.UploadSupportBundle(ctx, targetClient, target, result.LocalFilePath,
+				result.Checksums, time.Now)
+			return err
+		}
+		return actions.RunWithTimeout(ctx, uploadTimeoutPhase, timeouts.upload, run)
+	})
+}
+
+func getUploadProperties(client *http.Client, options http.SupportBundleCreationOptions, sourceVersion string,
+	bundleID actions.BundleID, customProperties []servicesutils.Property) actions.UploadProperties {
+	return actions.UploadProperties{
+		SourceURL:     client.GetURL(),
+		SourceVersion: sourceVersion,
+		BundleID:      bundleID,
+		Options:       options.Parameters,
+		FlunkyVersion: PluginVersion,
+		Custom:        customProperties,
+	}
 }
 
-func getRtClient(rtDetailsProvider func() (*config.ArtifactoryDetails, error)) (*http.Client, error) {
+// getRtClient gives a client of the service given by rtDetailsProvider, retrying its requests according to the
+// retry flags.
+func getRtClient(flagProvider flagValueProvider, rtDetailsProvider func() (*config.ArtifactoryDetails, error)) (
+	*http.Client, error) {
+	retry, err := getRetryPolicy(flagProvider)
+	if err != nil {
+		return nil, err
+	}
 	rtDetails, err := rtDetailsProvider()
 	if err != nil {
 		return nil, err
 	}
-	return &http.Client{RtDetails: rtDetails}, nil
+	return &http.Client{RtDetails: rtDetails, Retry: retry}, nil
+}
+
+// getSourceVersion gives the version of the source Artifactory service, or an empty string if it cannot be retrieved
+// as it is only used as an informative property.
+func getSourceVersion(ctx context.Context, client *http.Client) string {
+	version, err := actions.GetVersion(ctx, client)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Failed to get the version of %s: %+v", client.GetURL(), err))
+		return ""
+	}
+	return version
 }
 
+// deleteSupportBundleArchive deletes a downloaded Support Bundle archive, along with the temporary directory it was
+// downloaded to once empty.
 func deleteSupportBundleArchive(supportBundleArchivePath string) {
 	log.Debug(fmt.Sprintf("Deleting generated support bundle: %s", supportBundleArchivePath))
 	err := os.Remove(supportBundleArchivePath)
 	if err != nil {
 		log.Warn(fmt.Sprintf("Error occurred while deleting the generated support bundle archive: %+v", err))
+		return
+	}
+	if err = os.Remove(filepath.Dir(supportBundleArchivePath)); err != nil {
+		log.Debug(fmt.Sprintf("Kept the directory of the support bundle archive: %+v", err))
 	}
 }
 
 func parseArguments(ctx argumentsProvider) (actions.CaseNumber, error) {
+	arguments, err := getTrimmedArguments(ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	return actions.CaseNumber(arguments[0]), nil
+}
+
+func getTrimmedArguments(ctx argumentsProvider, expected int) ([]string, error) {
 	arguments := ctx.GetArguments()
-	if len(arguments) != 1 {
-		return "", fmt.Errorf("wrong number of arguments. Expected: 1, Received: %d", len(arguments))
+	if len(arguments) != expected {
+		return nil, fmt.Errorf("wrong number of arguments. Expected: %d, Received: %d", expected, len(arguments))
+	}
+	trimmed := make([]string, len(arguments))
+	for i := range arguments {
+		trimmed[i] = strings.TrimSpace(arguments[i])
 	}
-	return actions.CaseNumber(strings.TrimSpace(arguments[0])), nil
+	return trimmed, nil
 }
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"context"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -30,24 +35,118 @@ func Test_GetSupportBundleCommand(t *testing.T) {
 			DefaultValue: "10m",
 		},
 		components.StringFlag{
-			Name:         "retry-interval",
-			Description:  "The duration to wait between retries.",
-			DefaultValue: "5s",
+			Name:         "create-timeout",
+			Description:  "The timeout for the creation request of the support bundle, including its retries. 0 disables it.",
+			DefaultValue: "5m",
 		},
+		components.StringFlag{
+			Name: "transfer-timeout",
+			Description: "The timeout for the transfer of the support bundle archive once ready, including resumed " +
+				"attempts, or for the whole transfer with --stream. 0 disables it.",
+			DefaultValue: "1h",
+		},
+		components.StringFlag{
+			Name:         "upload-timeout",
+			Description:  "The timeout for the upload of the support bundle, including its retries. 0 disables it.",
+			DefaultValue: "1h",
+		},
+		components.StringFlag{
+			Name: "deadline",
+			Description: "The timeout for the whole command, aborting the requests in flight once elapsed. " +
+				"Not set by default.",
+		},
+		flagDefinitions[maxAttemptsFlag],
+		flagDefinitions[retryIntervalFlag],
+		flagDefinitions[maxRetryIntervalFlag],
 		components.BoolFlag{
 			Name:        "prompt-options",
 			Description: "Ask for support bundle options or use Artifactory default options.",
 		},
+		components.StringFlag{
+			Name: "answers-file",
+			Description: "Path to a YAML or JSON file answering the questions asked with --prompt-options or without " +
+				"a case number, - to read it from stdin. Answers are read from stdin when it is not a terminal.",
+		},
+		components.StringFlag{
+			Name: "options-file",
+			Description: "Path to a YAML or JSON file describing the content of the support bundle. " +
+				"Cannot be used with --prompt-options.",
+		},
+		components.StringFlag{
+			Name: "preset",
+			Description: "Name of a built-in preset describing the content of the support bundle, " +
+				"see the presets command. Cannot be used with --prompt-options or --options-file.",
+		},
+		components.StringFlag{
+			Name:        "logs-since",
+			Description: "Include the logs from this long ago until today, for example 72h or 3d.",
+		},
+		components.StringFlag{
+			Name:        "logs-from",
+			Description: "Include the logs from this date, in the YYYY-MM-DD format.",
+		},
+		components.StringFlag{
+			Name:        "logs-to",
+			Description: "Include the logs until this date, in the YYYY-MM-DD format.",
+		},
+		components.StringFlag{
+			Name:        "thread-dumps",
+			Description: "The number of thread dumps to include.",
+		},
+		components.StringFlag{
+			Name:        "thread-dump-interval",
+			Description: "The interval between thread dumps, for example 10s.",
+		},
+		components.StringFlag{
+			Name:         "timezone",
+			Description:  "The time zone of the log dates and of the support bundle description, for example Europe/Paris.",
+			DefaultValue: "UTC",
+		},
 		components.BoolFlag{
 			Name:         "cleanup",
 			Description:  "Delete the support bundle local temp file after upload.",
 			DefaultValue: true,
 		},
+		components.BoolFlag{
+			Name:        "cleanup-remote",
+			Description: "Delete the support bundle from the source service when the command is interrupted.",
+		},
 		components.StringFlag{
 			Name:         "target-repo",
 			Description:  "The target repository key where the support bundle will be uploaded to.",
 			DefaultValue: "logs",
 		},
+		components.StringFlag{
+			Name: "name-template",
+			Description: "The name of the uploaded support bundle. Supports the {case}, {serverId}, {bundleId}, " +
+				"{timestamp}, {hostname} and {seq} placeholders.",
+			DefaultValue: "SB-{timestamp}-{hostname}-{seq}.zip",
+		},
+		components.BoolFlag{
+			Name: "stream",
+			Description: "Pipe the support bundle from the source Artifactory straight into the upload, " +
+				"without writing it to a local temp file.",
+		},
+		components.StringFlag{
+			Name: "property",
+			Description: "Additional properties to attach to the uploaded support bundle, " +
+				"in the form of key1=value1;key2=value2.",
+		},
+		components.BoolFlag{
+			Name:        "dry-run",
+			Description: "Print what would be done without changing anything.",
+		},
+		components.StringFlag{
+			Name:         "output",
+			Description:  "The output format: text, json or yaml.",
+			DefaultValue: "text",
+		},
+		components.StringFlag{
+			Name: "profile",
+			Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+				"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+				"for example JFROG_SB_FLUNKY_SERVER_ID.",
+		},
 	}
 
 	expectedArgs := []components.Argument{
@@ -63,8 +162,8 @@ func Test_GetSupportBundleCommand(t *testing.T) {
 		Aliases:     []string{"c", "case"},
 		Arguments:   expectedArgs,
 		Flags:       expectedFlags,
-		EnvVars:     nil,
 	}
+	expected.EnvVars = getEnvVars(expected.Flags)
 	assert.Empty(t, cmp.Diff(expected, GetSupportBundleCommand(),
 		cmpopts.IgnoreFields(components.Command{}, "Action")))
 }
@@ -119,15 +218,18 @@ func Test_parseArguments(t *testing.T) {
 }
 
 func Test_deleteFile(t *testing.T) {
-	dir := os.TempDir()
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
 	path := filepath.Join(dir, "testfile")
 	f, err := os.Create(path)
 	require.NoError(t, err)
-	defer assert.NoError(t, f.Close())
+	require.NoError(t, f.Close())
 
 	deleteSupportBundleArchive(path)
 
 	assert.True(t, !exists(path))
+	assert.False(t, exists(dir), "the temporary directory of the archive is deleted")
 }
 
 func Test_deleteNonExistentFile(t *testing.T) {
@@ -136,6 +238,42 @@ func Test_deleteNonExistentFile(t *testing.T) {
 	assert.False(t, exists(path))
 }
 
+func Test_SupportBundleCmd_dryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s in dry-run mode", r.Method, r.URL)
+	}))
+	defer ts.Close()
+
+	result, err := SupportBundleCmd(context.Background(), &cliStub{
+		arguments:       []string{"1234"},
+		stringFlags:     map[string]string{"target-repo": "logs", "preset": "minimal", "name-template": "{case}-{seq}.zip"},
+		boolFlags:       map[string]bool{"dry-run": true},
+		rtDetails:       &config.ArtifactoryDetails{Url: ts.URL + "/source/", ServerId: "prod"},
+		targetRtDetails: &config.ArtifactoryDetails{Url: ts.URL + "/target/"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.BundleID)
+	require.NotNil(t, result.Plan)
+	assert.Equal(t, ts.URL+"/target/logs/1234/1234-{seq}.zip", result.Plan.UploadURL)
+	assert.Equal(t, http.MethodPost, result.Plan.Steps[0].Method)
+	assert.Equal(t, ts.URL+"/source/api/system/support/bundle", result.Plan.Steps[0].URL)
+	assert.Contains(t, result.Plan.Properties, "support.sourceUrl="+ts.URL+"/source/")
+
+	output, err := formatExecutionPlan(result.Plan)
+	require.NoError(t, err)
+	assert.Contains(t, output, "  1. Create the Support Bundle\n     POST "+ts.URL+"/source/api/system/support/bundle\n")
+	assert.Contains(t, output, "Payload:\n{\n  \"name\": \"JFrog Support Case number 1234\",\n")
+}
+
+func Test_SupportBundleCmd_invalidDeadline(t *testing.T) {
+	_, err := SupportBundleCmd(context.Background(), &cliStub{
+		arguments:   []string{"1234"},
+		stringFlags: map[string]string{"deadline": "1x"},
+	})
+	assert.EqualError(t, err, "invalid deadline value 1x, expected a duration such as 30s or 1h")
+	assert.Equal(t, actions.CategoryConfiguration, actions.CategoryOf(err))
+}
+
 func exists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
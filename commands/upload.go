@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"time"
+)
+
+// GetUploadCommand returns the description of the "upload" command.
+func GetUploadCommand() components.Command {
+	flags := getFlags(targetServerIDFlag, targetRepoFlag, nameTemplateFlag, propertyFlag, uploadTimeoutFlag,
+		maxAttemptsFlag, retryIntervalFlag, maxRetryIntervalFlag, profileFlag)
+	return components.Command{
+		Name:        "upload",
+		Description: "Uploads a local Support Bundle archive and prints its URL",
+		Arguments: []components.Argument{
+			caseArgument(),
+			{
+				Name:        "file",
+				Description: "Path to the Support Bundle archive.",
+			},
+		},
+		Flags:   flags,
+		EnvVars: getEnvVars(flags),
+		Action:  uploadCmd,
+	}
+}
+
+func uploadCmd(componentContext *components.Context) error {
+	cli, err := newCliAdapter(componentContext, GetUploadCommand().Flags)
+	if err != nil {
+		return err
+	}
+	uploadURL, err := UploadCmd(context.Background(), cli)
+	if err != nil {
+		return toCliError(err)
+	}
+	log.Output(uploadURL)
+	return nil
+}
+
+// UploadCmd uploads a local Support Bundle archive to the target Artifactory service.
+func UploadCmd(ctx context.Context, cli CliFacade) (string, error) {
+	caseNumber, filePath, err := parseUploadArguments(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	customProperties, err := getCustomProperties(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	checksums, err := actions.ComputeChecksums(filePath)
+	if err != nil {
+		return "", configurationError(err)
+	}
+
+	targetClient, err := getRtClient(cli, cli.GetTargetDetails)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	log.Debug(fmt.Sprintf("Selected \"dropbox\" Artifactory: %s", targetClient.GetURL()))
+
+	target := actions.UploadTarget{
+		RepoKey:      getTargetRepo(cli),
+		CaseNumber:   caseNumber,
+		NameTemplate: getNameTemplate(cli),
+		Hostname:     getHostname(),
+		Properties:   actions.UploadProperties{FlunkyVersion: PluginVersion, Custom: customProperties},
+	}
+	timeout, err := getUploadTimeout(cli)
+	if err != nil {
+		return "", configurationError(err)
+	}
+	var uploadURL string
+	err = actions.RunWithTimeout(ctx, uploadTimeoutPhase, timeout, func(ctx context.Context) (err error) {
+		uploadURL, err = actions.UploadSupportBundle(ctx, targetClient, target, filePath, checksums, time.Now)
+		return err
+	})
+	return uploadURL, err
+}
+
+func parseUploadArguments(ctx argumentsProvider) (actions.CaseNumber, string, error) {
+	arguments, err := getTrimmedArguments(ctx, 2)
+	if err != nil {
+		return "", "", err
+	}
+	return actions.CaseNumber(arguments[0]), arguments[1], nil
+}
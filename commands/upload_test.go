@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func Test_GetUploadCommand(t *testing.T) {
+	expected := components.Command{
+		Name:        "upload",
+		Description: "Uploads a local Support Bundle archive and prints its URL",
+		Arguments: []components.Argument{
+			{
+				Name:        "case",
+				Description: "JFrog Support case number.",
+			},
+			{
+				Name:        "file",
+				Description: "Path to the Support Bundle archive.",
+			},
+		},
+		Flags: []components.Flag{
+			components.StringFlag{
+				Name: "target-server-id",
+				Description: "Artifactory server ID configured using the config command to be used as the target for " +
+					"uploading the generated Support Bundle. If not provided JFrog support logs will be used.",
+			},
+			components.StringFlag{
+				Name:         "target-repo",
+				Description:  "The target repository key where the support bundle will be uploaded to.",
+				DefaultValue: "logs",
+			},
+			components.StringFlag{
+				Name: "name-template",
+				Description: "The name of the uploaded support bundle. Supports the {case}, {serverId}, {bundleId}, " +
+					"{timestamp}, {hostname} and {seq} placeholders.",
+				DefaultValue: "SB-{timestamp}-{hostname}-{seq}.zip",
+			},
+			components.StringFlag{
+				Name: "property",
+				Description: "Additional properties to attach to the uploaded support bundle, " +
+					"in the form of key1=value1;key2=value2.",
+			},
+			components.StringFlag{
+				Name:         "upload-timeout",
+				Description:  "The timeout for the upload of the support bundle, including its retries. 0 disables it.",
+				DefaultValue: "1h",
+			},
+			flagDefinitions[maxAttemptsFlag],
+			flagDefinitions[retryIntervalFlag],
+			flagDefinitions[maxRetryIntervalFlag],
+			components.StringFlag{
+				Name: "profile",
+				Description: "Name of a profile of the plugin configuration giving default flag values, see the config " +
+					"command. Flags can also be set with JFROG_SB_FLUNKY_<FLAG> environment variables, " +
+					"for example JFROG_SB_FLUNKY_SERVER_ID.",
+			},
+		},
+	}
+	expected.EnvVars = getEnvVars(expected.Flags)
+	assert.Empty(t, cmp.Diff(expected, GetUploadCommand(),
+		cmpopts.IgnoreFields(components.Command{}, "Action")))
+}
+
+func Test_parseUploadArguments(t *testing.T) {
+	tests := []struct {
+		name          string
+		argsProvider  args
+		expectedCase  actions.CaseNumber
+		expectedPath  string
+		expectedError string
+	}{
+		{
+			name:         "parse valid arguments",
+			argsProvider: []string{" 1234", "/tmp/sb.zip "},
+			expectedCase: "1234",
+			expectedPath: "/tmp/sb.zip",
+		},
+		{
+			name:          "missing file",
+			argsProvider:  []string{"1234"},
+			expectedError: "wrong number of arguments. Expected: 2, Received: 1",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			caseNumber, path, err := parseUploadArguments(test.argsProvider)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedCase, caseNumber)
+				assert.Equal(t, test.expectedPath, path)
+			}
+		})
+	}
+}
+
+func Test_UploadCmd_MissingFile(t *testing.T) {
+	_, err := UploadCmd(context.Background(), &cliStub{arguments: []string{"1234", "file/does/not/exist"}})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Equal(t, actions.CategoryConfiguration, actions.CategoryOf(err))
+}
@@ -0,0 +1,4 @@
+package commands
+
+// PluginVersion is the version of the plugin, also attached to the uploaded Support Bundles.
+const PluginVersion = "v0.1.0"
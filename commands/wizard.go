@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+)
+
+// jfrogSupportTarget is the choice of the JFrog Support "dropbox" service as target in the wizard.
+const jfrogSupportTarget = "JFrog Support (https://supportlogs.jfrog.com/)"
+
+// runWizard asks for what is needed to run support-case without arguments: the case number, the source and target
+// services among the ones of JFrog CLI configuration, and the content of the Support Bundle unless an options file or
+// a preset is given. Flags given on the command line are not asked again.
+func runWizard(cli CliFacade, configHelper serviceHelper, prompter actions.WizardPrompter,
+	servers []*config.ArtifactoryDetails) (CliFacade, error) {
+	serverIDs, defaultServerID, err := getConfiguredServerIDs(servers)
+	if err != nil {
+		return nil, err
+	}
+
+	caseNumber, err := prompter.AskCaseNumber()
+	if err != nil {
+		return nil, err
+	}
+	wizard := &wizardCliFacade{
+		CliFacade:    cli,
+		configHelper: configHelper,
+		caseNumber:   caseNumber,
+		stringFlags:  map[string]string{},
+	}
+	if cli.GetStringFlagValue(serverIDFlag) == "" {
+		serverID, err := prompter.AskSourceServerID(serverIDs, defaultServerID)
+		if err != nil {
+			return nil, err
+		}
+		wizard.stringFlags[serverIDFlag] = serverID
+	}
+	if cli.GetStringFlagValue(targetServerIDFlag) == "" {
+		targets := append([]string{jfrogSupportTarget}, serverIDs...)
+		targetServerID, err := prompter.AskTargetServerID(targets, jfrogSupportTarget)
+		if err != nil {
+			return nil, err
+		}
+		if targetServerID != jfrogSupportTarget {
+			wizard.stringFlags[targetServerIDFlag] = targetServerID
+		}
+	}
+	wizard.promptOptions = cli.GetStringFlagValue(optionsFileFlag) == "" && cli.GetStringFlagValue(presetFlag) == ""
+	return wizard, nil
+}
+
+func getConfiguredServerIDs(servers []*config.ArtifactoryDetails) (serverIDs []string, defaultServerID string,
+	err error) {
+	if len(servers) == 0 {
+		return nil, "", errors.New("no Artifactory service configured, add one with 'jfrog rt config'")
+	}
+	serverIDs = make([]string, len(servers))
+	for i := range servers {
+		serverIDs[i] = servers[i].ServerId
+		if servers[i].IsDefault {
+			defaultServerID = servers[i].ServerId
+		}
+	}
+	return serverIDs, defaultServerID, nil
+}
+
+// wizardCliFacade gives the answers to the wizard as if they were given on the command line.
+type wizardCliFacade struct {
+	CliFacade
+	configHelper  serviceHelper
+	caseNumber    actions.CaseNumber
+	stringFlags   map[string]string
+	promptOptions bool
+}
+
+func (w *wizardCliFacade) GetArguments() []string {
+	return []string{string(w.caseNumber)}
+}
+
+func (w *wizardCliFacade) GetStringFlagValue(flagName string) string {
+	if value, ok := w.stringFlags[flagName]; ok {
+		return value
+	}
+	return w.CliFacade.GetStringFlagValue(flagName)
+}
+
+func (w *wizardCliFacade) GetBoolFlagValue(flagName string) bool {
+	if flagName == promptOptionsFlag && w.promptOptions {
+		return true
+	}
+	return w.CliFacade.GetBoolFlagValue(flagName)
+}
+
+func (w *wizardCliFacade) GetRtDetails() (*config.ArtifactoryDetails, error) {
+	return getRtDetails(w, w.configHelper)
+}
+
+func (w *wizardCliFacade) GetTargetDetails() (*config.ArtifactoryDetails, error) {
+	return getTargetDetails(w, w.configHelper)
+}
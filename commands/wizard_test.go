@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type wizardPrompterStub struct {
+	caseNumber    actions.CaseNumber
+	caseNumberErr error
+	answers       map[string]string
+	serverIDErr   error
+	asked         []string
+}
+
+func (s *wizardPrompterStub) AskCaseNumber() (actions.CaseNumber, error) {
+	return s.caseNumber, s.caseNumberErr
+}
+
+func (s *wizardPrompterStub) AskSourceServerID(_ []string, defaultServerID string) (string, error) {
+	return s.askServerID("source", defaultServerID)
+}
+
+func (s *wizardPrompterStub) AskTargetServerID(_ []string, defaultServerID string) (string, error) {
+	return s.askServerID("target", defaultServerID)
+}
+
+func (s *wizardPrompterStub) askServerID(question string, defaultServerID string) (string, error) {
+	s.asked = append(s.asked, question)
+	if answer, ok := s.answers[question]; ok {
+		return answer, s.serverIDErr
+	}
+	return defaultServerID, s.serverIDErr
+}
+
+func Test_runWizard(t *testing.T) {
+	servers := []*config.ArtifactoryDetails{
+		{ServerId: "dev", Url: "http://dev/"},
+		{ServerId: "prod", Url: "http://prod/", IsDefault: true},
+	}
+	tests := []struct {
+		name                 string
+		cli                  *cliStub
+		servers              []*config.ArtifactoryDetails
+		prompter             *wizardPrompterStub
+		expectAsked          []string
+		expectServerID       string
+		expectTargetServerID string
+		expectPromptOptions  bool
+		expectErr            string
+	}{
+		{
+			name:                "defaults",
+			cli:                 &cliStub{},
+			servers:             servers,
+			prompter:            &wizardPrompterStub{caseNumber: "1234"},
+			expectAsked:         []string{"source", "target"},
+			expectServerID:      "prod",
+			expectPromptOptions: true,
+		},
+		{
+			name:    "selected services",
+			cli:     &cliStub{},
+			servers: servers,
+			prompter: &wizardPrompterStub{caseNumber: "1234", answers: map[string]string{
+				"source": "dev",
+				"target": "prod",
+			}},
+			expectAsked:          []string{"source", "target"},
+			expectServerID:       "dev",
+			expectTargetServerID: "prod",
+			expectPromptOptions:  true,
+		},
+		{
+			name: "flags are not asked again",
+			cli: &cliStub{stringFlags: map[string]string{
+				"server-id":        "dev",
+				"target-server-id": "prod",
+				"preset":           "minimal",
+			}},
+			servers:              servers,
+			prompter:             &wizardPrompterStub{caseNumber: "1234"},
+			expectServerID:       "dev",
+			expectTargetServerID: "prod",
+			expectPromptOptions:  false,
+		},
+		{
+			name:      "no configured service",
+			cli:       &cliStub{},
+			prompter:  &wizardPrompterStub{caseNumber: "1234"},
+			expectErr: "no Artifactory service configured, add one with 'jfrog rt config'",
+		},
+		{
+			name:      "error on case number",
+			cli:       &cliStub{},
+			servers:   servers,
+			prompter:  &wizardPrompterStub{caseNumberErr: errors.New("oops")},
+			expectErr: "oops",
+		},
+		{
+			name:      "error on server",
+			cli:       &cliStub{},
+			servers:   servers,
+			prompter:  &wizardPrompterStub{caseNumber: "1234", serverIDErr: errors.New("oops")},
+			expectErr: "oops",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			cli, err := runWizard(test.cli, &serviceHelperStub{}, test.prompter, test.servers)
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectAsked, test.prompter.asked)
+			assert.Equal(t, []string{"1234"}, cli.GetArguments())
+			assert.Equal(t, test.expectServerID, cli.GetStringFlagValue("server-id"))
+			assert.Equal(t, test.expectTargetServerID, cli.GetStringFlagValue("target-server-id"))
+			assert.Equal(t, test.expectPromptOptions, cli.GetBoolFlagValue("prompt-options"))
+		})
+	}
+}
+
+func Test_wizardCliFacade_GetRtDetails(t *testing.T) {
+	helper := &serviceHelperStub{details: &config.ArtifactoryDetails{Url: "http://dev"}}
+	cli, err := runWizard(&cliStub{}, helper, &wizardPrompterStub{caseNumber: "1234"},
+		[]*config.ArtifactoryDetails{{ServerId: "dev", IsDefault: true}})
+	require.NoError(t, err)
+
+	details, err := cli.GetRtDetails()
+	require.NoError(t, err)
+	assert.Equal(t, "http://dev/", details.Url)
+	details, err = cli.GetTargetDetails()
+	require.NoError(t, err)
+	assert.Equal(t, "https://supportlogs.jfrog.com/", details.Url)
+}
@@ -8,8 +8,11 @@ require (
 	github.com/google/go-cmp v0.5.4
 	github.com/jfrog/jfrog-cli-core v0.0.1
 	github.com/jfrog/jfrog-client-go v0.16.0
+	github.com/mattn/go-isatty v0.0.12
 	github.com/stretchr/testify v1.6.1
 	github.com/testcontainers/testcontainers-go v0.9.0
+	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f
+	gopkg.in/yaml.v2 v2.3.0
 )
 
 replace github.com/jfrog/jfrog-cli-core => github.com/jfrog/jfrog-cli-core v1.1.2
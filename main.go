@@ -15,12 +15,23 @@ func getApp() components.App {
 		Name: "sb-flunky",
 		Description: "This plugin dutifully creates a Support Bundle on an Artifactory service and obediently " +
 			"uploads it to another Artifactory service.",
-		Version:  "v0.1.0",
+		Version:  commands.PluginVersion,
 		Commands: getCommands(),
 	}
 }
 
 func getCommands() []components.Command {
 	return []components.Command{
-		commands.GetSupportBundleCommand()}
+		commands.GetSupportBundleCommand(),
+		commands.GetCreateCommand(),
+		commands.GetStatusCommand(),
+		commands.GetDownloadCommand(),
+		commands.GetUploadCommand(),
+		commands.GetListCommand(),
+		commands.GetDeleteCommand(),
+		commands.GetPruneCommand(),
+		commands.GetPresetsCommand(),
+		commands.GetDoctorCommand(),
+		commands.GetConfigCommand(),
+	}
 }
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
@@ -10,15 +11,12 @@ import (
 )
 
 func Test_CreateIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping integration test")
-	}
 	tests := []integrationTest{
 		{
 			Name: "Success with default options",
 			Function: func(t *testing.T, rtDetails *config.ArtifactoryDetails,
 				targetRtDetails *config.ArtifactoryDetails) {
-				id, err := createSupportBundle(rtDetails, actions.NewDefaultOptionsProvider())
+				id, err := createSupportBundle(rtDetails, actions.NewDefaultOptionsProvider(time.Now))
 				require.NoError(t, err)
 				require.NotEmpty(t, id)
 			},
@@ -46,7 +44,7 @@ func Test_CreateIntegration(t *testing.T) {
 			Function: func(t *testing.T, rtDetails *config.ArtifactoryDetails,
 				targetRtDetails *config.ArtifactoryDetails) {
 				_, err := createSupportBundle(&config.ArtifactoryDetails{Url: "http://unknown.invalid/"},
-					actions.NewDefaultOptionsProvider())
+					actions.NewDefaultOptionsProvider(time.Now))
 				require.Error(t, err)
 				// exact message depends on OS
 				require.Contains(t, err.Error(), "dial tcp:")
@@ -67,5 +65,5 @@ func newPromptOptionsProviderStub(includeAll bool) *actions.PromptOptionsProvide
 
 func createSupportBundle(rtDetails *config.ArtifactoryDetails, optionsProvider actions.OptionsProvider) (
 	actions.BundleID, error) {
-	return actions.CreateSupportBundle(&http.Client{RtDetails: rtDetails}, "foo", optionsProvider)
+	return actions.CreateSupportBundle(context.Background(), &http.Client{RtDetails: rtDetails}, "foo", optionsProvider)
 }
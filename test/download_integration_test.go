@@ -14,29 +14,31 @@ import (
 )
 
 func Test_DownloadIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping integration test")
-	}
 	tests := []integrationTest{
 		{
 			Name: "Success",
 			Function: func(t *testing.T, rtDetails *config.ArtifactoryDetails,
 				targetRtDetails *config.ArtifactoryDetails) {
 				supportBundle := setUpSupportBundle(t, rtDetails)
-				bundle, err := actions.DownloadSupportBundle(context.Background(), &http.Client{RtDetails: rtDetails},
-					30*time.Second, 100*time.Millisecond, supportBundle)
+				bundle, checksums, err := actions.DownloadSupportBundle(context.Background(),
+					&http.Client{RtDetails: rtDetails}, actions.DownloadTimeouts{Ready: 30 * time.Second},
+					http.RetryPolicy{Interval: 100 * time.Millisecond}, supportBundle)
 				require.NoError(t, err)
 				assert.Contains(t, bundle, supportBundle)
 				assert.True(t, fileutils.IsZip(bundle))
 				assertBundleIsAZipArchive(t, bundle)
+				expected, err := actions.ComputeChecksums(bundle)
+				require.NoError(t, err)
+				assert.Equal(t, expected, checksums)
 			},
 		},
 		{
 			Name: "Not found",
 			Function: func(t *testing.T, rtDetails *config.ArtifactoryDetails,
 				targetRtDetails *config.ArtifactoryDetails) {
-				bundle, err := actions.DownloadSupportBundle(context.Background(), &http.Client{RtDetails: rtDetails},
-					1*time.Second, 100*time.Millisecond, "unknown")
+				bundle, _, err := actions.DownloadSupportBundle(context.Background(), &http.Client{RtDetails: rtDetails},
+					actions.DownloadTimeouts{Ready: 1 * time.Second},
+					http.RetryPolicy{Interval: 100 * time.Millisecond}, "unknown")
 				require.Empty(t, bundle)
 				assert.EqualError(t, err, "http request failed with: 404 Not Found")
 			},
@@ -53,8 +55,8 @@ func assertBundleIsAZipArchive(t *testing.T, bundle string) {
 
 func setUpSupportBundle(t *testing.T, rtDetails *config.ArtifactoryDetails) actions.BundleID {
 	t.Helper()
-	supportBundle, err := actions.CreateSupportBundle(&http.Client{RtDetails: rtDetails}, "foo",
-		actions.NewDefaultOptionsProvider())
+	supportBundle, err := actions.CreateSupportBundle(context.Background(), &http.Client{RtDetails: rtDetails}, "foo",
+		actions.NewDefaultOptionsProvider(time.Now))
 	require.NoError(t, err)
 	require.NotEmpty(t, supportBundle)
 	return supportBundle
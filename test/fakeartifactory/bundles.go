@@ -0,0 +1,231 @@
+package fakeartifactory
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Statuses of a Support Bundle, as reported by Artifactory.
+const (
+	StatusInProgress = "in progress"
+	StatusSuccess    = "success"
+	StatusFailed     = "failed"
+)
+
+const bundleIDLayout = "20060102-1504"
+
+// BundleBehavior tells how the Support Bundles created from now on behave. The zero value creates Support Bundles
+// which are generated at once.
+type BundleBehavior struct {
+	// CreateStatus is the status the creation is answered with instead of 200, in which case nothing is created.
+	CreateStatus int
+	// GenerationTime is how long a Support Bundle is reported in progress.
+	GenerationTime time.Duration
+	// Status is the status reported once the Support Bundle is generated, StatusSuccess when empty. Any status other
+	// than StatusSuccess, StatusFailed and StatusInProgress is one the plugin does not know.
+	Status string
+	// Message is the message reported along the status, such as why the generation failed.
+	Message string
+	// ArchiveStatus is the status the download of the archive is answered with instead of 200.
+	ArchiveStatus int
+}
+
+type supportBundle struct {
+	id          string
+	name        string
+	description string
+	parameters  json.RawMessage
+	created     time.Time
+	behavior    BundleBehavior
+	archive     []byte
+}
+
+type creationRequest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// SetBundleBehavior changes how the Support Bundles created from now on behave.
+func (s *Server) SetBundleBehavior(behavior BundleBehavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.behavior = behavior
+}
+
+// Archive gives the content of the archive of a Support Bundle, whether it is generated or not.
+func (s *Server) Archive(bundleID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bundle := s.findBundle(bundleID); bundle != nil {
+		return bundle.archive, true
+	}
+	return nil, false
+}
+
+func (s *Server) serveSupportBundles(w http.ResponseWriter, r *http.Request, name string, path string) {
+	if !s.isAdmin(name) {
+		denied(w, name)
+		return
+	}
+	// The ID of a Support Bundle is the second segment of its paths, such as bundle/{id}/archive.
+	segments := strings.Split(path, "/")
+	bundleID := ""
+	if len(segments) > 1 && segments[0] == "bundle" {
+		bundleID, segments[1] = segments[1], "{id}"
+	}
+	switch r.Method + " " + strings.Join(segments, "/") {
+	case "POST bundle":
+		s.createBundle(w, r)
+	case "GET bundles":
+		s.listBundles(w)
+	case "GET bundle/{id}":
+		s.withBundle(w, bundleID, s.getBundleStatus)
+	case "DELETE bundle/{id}":
+		s.withBundle(w, bundleID, func(w http.ResponseWriter, bundle *supportBundle) {
+			s.deleteBundle(bundle)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	case "GET bundle/{id}/archive":
+		s.withBundle(w, bundleID, func(w http.ResponseWriter, bundle *supportBundle) {
+			s.downloadArchive(w, r, bundle)
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) createBundle(w http.ResponseWriter, r *http.Request) {
+	behavior := s.behavior
+	if behavior.CreateStatus != 0 {
+		writeError(w, behavior.CreateStatus, "Failed to create the support bundle")
+		return
+	}
+	var request creationRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &request)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid support bundle request: %v", err))
+		return
+	}
+	created := s.now().UTC()
+	s.bundleSequence++
+	bundle := &supportBundle{
+		id:          fmt.Sprintf("%s-%04d", created.Format(bundleIDLayout), s.bundleSequence),
+		name:        request.Name,
+		description: request.Description,
+		parameters:  request.Parameters,
+		created:     created,
+		behavior:    behavior,
+	}
+	bundle.archive, err = newArchive(bundle)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.bundles = append(s.bundles, bundle)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id": bundle.id,
+		"artifactory": map[string]string{
+			"service_id": "jfrt@fake",
+			"bundle_url": fmt.Sprintf("%sapi/system/support/bundle/%s/archive", s.URL(), bundle.id),
+		},
+	})
+}
+
+// newArchive gives the archive of a Support Bundle, a zip file describing the request it was created with.
+func newArchive(bundle *supportBundle) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, err := archive.Create("artifactory/support-bundle.json")
+	if err != nil {
+		return nil, err
+	}
+	err = json.NewEncoder(file).Encode(creationRequest{Name: bundle.name, Description: bundle.description,
+		Parameters: bundle.parameters})
+	if err != nil {
+		return nil, err
+	}
+	if err = archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Server) listBundles(w http.ResponseWriter) {
+	bundles := make([]map[string]string, 0, len(s.bundles))
+	for _, bundle := range s.bundles {
+		bundles = append(bundles, map[string]string{"id": bundle.id, "name": bundle.name,
+			"description": bundle.description, "created": bundle.created.Format(time.RFC3339)})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"count": len(bundles), "bundles": bundles})
+}
+
+func (s *Server) withBundle(w http.ResponseWriter, bundleID string, serve func(http.ResponseWriter,
+	*supportBundle)) {
+	bundle := s.findBundle(bundleID)
+	if bundle == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Support bundle %s not found", bundleID))
+		return
+	}
+	serve(w, bundle)
+}
+
+func (s *Server) getBundleStatus(w http.ResponseWriter, bundle *supportBundle) {
+	status := map[string]string{"id": bundle.id, "name": bundle.name, "description": bundle.description,
+		"created": bundle.created.Format(time.RFC3339), "status": s.statusOf(bundle)}
+	if bundle.behavior.Message != "" {
+		status["message"] = bundle.behavior.Message
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// statusOf gives the status of a Support Bundle, which stays in progress for its generation time.
+func (s *Server) statusOf(bundle *supportBundle) string {
+	if s.now().Before(bundle.created.Add(bundle.behavior.GenerationTime)) {
+		return StatusInProgress
+	}
+	if bundle.behavior.Status == "" {
+		return StatusSuccess
+	}
+	return bundle.behavior.Status
+}
+
+func (s *Server) downloadArchive(w http.ResponseWriter, r *http.Request, bundle *supportBundle) {
+	if bundle.behavior.ArchiveStatus != 0 {
+		writeError(w, bundle.behavior.ArchiveStatus, "Failed to download the support bundle")
+		return
+	}
+	if s.statusOf(bundle) != StatusSuccess {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Support bundle %s is not ready", bundle.id))
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	http.ServeContent(w, r, bundle.id+".zip", bundle.created, bytes.NewReader(bundle.archive))
+}
+
+func (s *Server) findBundle(bundleID string) *supportBundle {
+	for _, bundle := range s.bundles {
+		if bundle.id == bundleID {
+			return bundle
+		}
+	}
+	return nil
+}
+
+func (s *Server) deleteBundle(deleted *supportBundle) {
+	for i, bundle := range s.bundles {
+		if bundle == deleted {
+			s.bundles = append(s.bundles[:i], s.bundles[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package fakeartifactory
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault is a failure injected in the responses to some requests, as seen with real services and the networks in
+// between. Unless it replaces the response with Status or Body, the response is the one of the wrapped handler.
+type Fault struct {
+	// Method is the method of the requests the fault applies to, any when empty.
+	Method string
+	// Path is a regular expression matched against the path of the requests the fault applies to, any when empty.
+	Path string
+	// Times is the number of requests the fault applies to before it is lifted, all of them when 0.
+	Times int
+
+	// Delay is how long to wait before answering, unless the client goes away first.
+	Delay time.Duration
+	// Status replaces the response with an error of this status, such as 429 or 503.
+	Status int
+	// RetryAfter is the Retry-After header sent along Status, in seconds or as an HTTP date.
+	RetryAfter string
+	// Body replaces the body of the response with this JSON document, such as a status which never changes.
+	Body string
+	// TruncateAfter closes the connection once this number of bytes of the body have been sent, when positive.
+	TruncateAfter int64
+	// ResetAfter resets the connection once this number of bytes of the body have been sent, when positive.
+	ResetAfter int64
+	// ContentLength replaces the Content-Length header of the response, when positive.
+	ContentLength int64
+}
+
+// interruptsBody tells whether the fault changes how the body is sent, which is then written to the connection
+// directly.
+func (f *Fault) interruptsBody() bool {
+	return f.TruncateAfter > 0 || f.ResetAfter > 0 || f.ContentLength > 0
+}
+
+// FaultInjector is an http.Handler injecting faults in the responses of another one.
+type FaultInjector struct {
+	handler http.Handler
+
+	mu     sync.Mutex
+	faults []*injectedFault
+}
+
+type injectedFault struct {
+	Fault
+	path    *regexp.Regexp
+	applied int
+}
+
+// NewFaultInjector wraps handler, which answers the requests no fault applies to.
+func NewFaultInjector(handler http.Handler) *FaultInjector {
+	return &FaultInjector{handler: handler}
+}
+
+// Inject adds a fault, applying to the matching requests before the faults injected later. The path of the fault
+// must be a valid regular expression.
+func (f *FaultInjector) Inject(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &injectedFault{Fault: fault, path: regexp.MustCompile(fault.Path)})
+}
+
+// Clear lifts all faults.
+func (f *FaultInjector) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+}
+
+// Applied gives the number of requests the faults have applied to so far.
+func (f *FaultInjector) Applied() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	applied := 0
+	for _, fault := range f.faults {
+		applied += fault.applied
+	}
+	return applied
+}
+
+func (f *FaultInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fault := f.match(r)
+	if fault == nil {
+		f.handler.ServeHTTP(w, r)
+		return
+	}
+	if !delay(r, fault.Delay) {
+		return
+	}
+	switch {
+	case fault.Status != 0:
+		if fault.RetryAfter != "" {
+			w.Header().Set("Retry-After", fault.RetryAfter)
+		}
+		writeError(w, fault.Status, http.StatusText(fault.Status))
+	case fault.Body != "":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, fault.Body)
+	case fault.interruptsBody():
+		recorder := httptest.NewRecorder()
+		f.handler.ServeHTTP(recorder, r)
+		if err := writeInterrupted(w, recorder, fault); err != nil {
+			log.Warn(fmt.Sprintf("Failed to inject a fault in the response to %s %s: %+v", r.Method, r.URL, err))
+		}
+	default:
+		f.handler.ServeHTTP(w, r)
+	}
+}
+
+// match gives the first fault applying to a request, if any, counting the request as one it applied to.
+func (f *FaultInjector) match(r *http.Request) *Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, fault := range f.faults {
+		if (fault.Method == "" || fault.Method == r.Method) && fault.path.MatchString(r.URL.Path) &&
+			(fault.Times == 0 || fault.applied < fault.Times) {
+			fault.applied++
+			copied := fault.Fault
+			return &copied
+		}
+	}
+	return nil
+}
+
+// delay waits before answering a request, and tells whether the client is still there. The body of the request is
+// read beforehand, as the server only notices that the client went away once it has been.
+func delay(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// writeInterrupted writes a recorded response to the connection of w, with the Content-Length of the fault if any,
+// then closes or resets the connection as the fault requires.
+func writeInterrupted(w http.ResponseWriter, recorder *httptest.ResponseRecorder, fault *Fault) error {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return errors.New("the connection cannot be taken over")
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	body := recorder.Body.Bytes()
+	contentLength := int64(len(body))
+	if fault.ContentLength > 0 {
+		contentLength = fault.ContentLength
+	}
+	header := recorder.Header()
+	header.Set("Content-Length", strconv.FormatInt(contentLength, 10))
+	header.Set("Connection", "close")
+	if _, err = fmt.Fprintf(buf, "HTTP/1.1 %d %s\r\n", recorder.Code, http.StatusText(recorder.Code)); err != nil {
+		return err
+	}
+	if err = header.Write(buf); err != nil {
+		return err
+	}
+	if _, err = buf.WriteString("\r\n"); err != nil {
+		return err
+	}
+	switch {
+	case fault.ResetAfter > 0 && fault.ResetAfter < int64(len(body)):
+		return reset(conn, buf, body[:fault.ResetAfter])
+	case fault.TruncateAfter > 0 && fault.TruncateAfter < int64(len(body)):
+		body = body[:fault.TruncateAfter]
+	}
+	if _, err = buf.Write(body); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// reset sends the beginning of a body, then resets the connection instead of closing it.
+func reset(conn net.Conn, buf *bufio.ReadWriter, sent []byte) error {
+	if _, err := buf.Write(sent); err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return errors.New("the connection cannot be reset")
+	}
+	return tcpConn.SetLinger(0)
+}
@@ -0,0 +1,344 @@
+package fakeartifactory
+
+import (
+	"crypto/md5"  // nolint: gosec // MD5 is one of the checksums stored by Artifactory, not used for security
+	"crypto/sha1" // nolint: gosec // SHA-1 is one of the checksums stored by Artifactory, not used for security
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Action is an action on a repository which can be granted to a user.
+type Action string
+
+const (
+	// ActionRead allows to get the storage info of the artifacts of a repository, and to find them by checksum.
+	ActionRead Action = "read"
+	// ActionDeploy allows to deploy artifacts to a repository, and implies ActionRead.
+	ActionDeploy Action = "deploy"
+)
+
+// Checksums are the checksums of an artifact, hex encoded.
+type Checksums struct {
+	SHA256 string `json:"sha256"`
+	SHA1   string `json:"sha1"`
+	MD5    string `json:"md5"`
+}
+
+// Artifact is a file deployed to a repository.
+type Artifact struct {
+	Repo       string
+	Path       string
+	Content    []byte
+	Checksums  Checksums
+	Properties map[string]string
+	Created    time.Time
+	// DeployedBy is the name of the user who deployed the artifact.
+	DeployedBy string
+}
+
+type repository struct {
+	artifacts   map[string]*Artifact
+	permissions map[string]map[Action]bool
+}
+
+// CreateRepository creates an empty local generic repository, where only admin users may deploy.
+func (s *Server) CreateRepository(repoKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repositories[repoKey] = &repository{artifacts: map[string]*Artifact{}, permissions: map[string]map[Action]bool{}}
+}
+
+// Grant allows a user, which may be Anonymous, to perform actions on a repository created with CreateRepository.
+func (s *Server) Grant(repoKey string, name string, actions ...Action) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repositories[repoKey]
+	if repo.permissions[name] == nil {
+		repo.permissions[name] = map[Action]bool{}
+	}
+	for _, action := range actions {
+		repo.permissions[name][action] = true
+	}
+}
+
+// Artifact gives a copy of an artifact deployed to a repository.
+func (s *Server) Artifact(repoKey string, path string) (Artifact, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo := s.repositories[repoKey]; repo != nil && repo.artifacts[path] != nil {
+		return *repo.artifacts[path], true
+	}
+	return Artifact{}, false
+}
+
+func (s *Server) isAllowed(repo *repository, name string, action Action) bool {
+	permissions := repo.permissions[name]
+	return s.isAdmin(name) || permissions[action] || (action == ActionRead && permissions[ActionDeploy])
+}
+
+// serveDeploy deploys an artifact, given as "{repo}/{path};key=value" where the matrix parameters are properties.
+// With the X-Checksum-Deploy header, the content is that of an artifact with the same checksum already stored.
+func (s *Server) serveDeploy(w http.ResponseWriter, r *http.Request, name string, path string) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+	repoKey, artifactPath, properties, err := parseDeployPath(path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	repo := s.repositories[repoKey]
+	if repo == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository %s not found", repoKey))
+		return
+	}
+	if !s.isAllowed(repo, name, ActionDeploy) {
+		denied(w, name)
+		return
+	}
+
+	content, status, err := s.deployedContent(r)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	checksums := computeChecksums(content)
+	if mismatch := checksumMismatch(r, checksums); mismatch != "" {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Checksum error for '%s': %s", artifactPath, mismatch))
+		return
+	}
+	artifact := &Artifact{Repo: repoKey, Path: artifactPath, Content: content, Checksums: checksums,
+		Properties: properties, Created: s.now().UTC(), DeployedBy: name}
+	repo.artifacts[artifactPath] = artifact
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"repo":        repoKey,
+		"path":        "/" + artifactPath,
+		"created":     artifact.Created.Format(time.RFC3339),
+		"createdBy":   name,
+		"downloadUri": s.URL() + repoKey + "/" + artifactPath,
+		"size":        fmt.Sprint(len(content)),
+		"checksums":   checksums,
+	})
+}
+
+// deployedContent gives the content of a deploy, or the status to answer with when it cannot be read.
+func (s *Server) deployedContent(r *http.Request) ([]byte, int, error) {
+	if !strings.EqualFold(r.Header.Get("X-Checksum-Deploy"), "true") {
+		content, err := ioutil.ReadAll(r.Body)
+		return content, http.StatusBadRequest, err
+	}
+	existing := s.findByChecksum(r.Header.Get("X-Checksum-Sha1"), r.Header.Get("X-Checksum-Sha256"))
+	if existing == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("checksum deploy failed. No existing file with SHA1: %s",
+			r.Header.Get("X-Checksum-Sha1"))
+	}
+	return existing.Content, http.StatusCreated, nil
+}
+
+// parseDeployPath splits an escaped deploy path into its repository, its artifact path and its properties.
+func parseDeployPath(path string) (string, string, map[string]string, error) {
+	parts := strings.Split(path, ";")
+	properties := map[string]string{}
+	for _, parameter := range parts[1:] {
+		keyValue := strings.SplitN(parameter, "=", 2)
+		key, err := url.PathUnescape(keyValue[0])
+		if err != nil {
+			return "", "", nil, err
+		}
+		value := ""
+		if len(keyValue) == 2 {
+			if value, err = url.PathUnescape(keyValue[1]); err != nil {
+				return "", "", nil, err
+			}
+		}
+		properties[key] = value
+	}
+	unescaped, err := url.PathUnescape(parts[0])
+	if err != nil {
+		return "", "", nil, err
+	}
+	segments := strings.Split(unescaped, "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", "", nil, fmt.Errorf("invalid path %s", unescaped)
+		}
+	}
+	if len(segments) < 2 {
+		return "", "", nil, fmt.Errorf("invalid path %s, no artifact path", unescaped)
+	}
+	return segments[0], strings.Join(segments[1:], "/"), properties, nil
+}
+
+func computeChecksums(content []byte) Checksums {
+	sha256Sum := sha256.Sum256(content)
+	sha1Sum := sha1.Sum(content) // nolint: gosec // not used for security
+	md5Sum := md5.Sum(content)   // nolint: gosec // not used for security
+	return Checksums{
+		SHA256: hex.EncodeToString(sha256Sum[:]),
+		SHA1:   hex.EncodeToString(sha1Sum[:]),
+		MD5:    hex.EncodeToString(md5Sum[:]),
+	}
+}
+
+// checksumMismatch tells which of the checksums sent along a deploy does not match the content, if any.
+func checksumMismatch(r *http.Request, checksums Checksums) string {
+	for header, actual := range map[string]string{"X-Checksum-Sha256": checksums.SHA256,
+		"X-Checksum-Sha1": checksums.SHA1, "X-Checksum": checksums.MD5} {
+		if expected := r.Header.Get(header); expected != "" && !strings.EqualFold(expected, actual) {
+			return fmt.Sprintf("received '%s' but actual is '%s'", expected, actual)
+		}
+	}
+	return ""
+}
+
+func (s *Server) findByChecksum(sha1Sum string, sha256Sum string) *Artifact {
+	for _, repo := range s.repositories {
+		for _, artifact := range repo.artifacts {
+			if (sha1Sum != "" && strings.EqualFold(artifact.Checksums.SHA1, sha1Sum)) ||
+				(sha256Sum != "" && strings.EqualFold(artifact.Checksums.SHA256, sha256Sum)) {
+				return artifact
+			}
+		}
+	}
+	return nil
+}
+
+// serveStorage gives the info of a file or a folder, as "{repo}/{path}".
+func (s *Server) serveStorage(w http.ResponseWriter, r *http.Request, name string, path string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	segments := strings.SplitN(strings.TrimSuffix(unescaped, "/"), "/", 2)
+	repo := s.repositories[segments[0]]
+	if repo == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository %s not found", segments[0]))
+		return
+	}
+	if !s.isAllowed(repo, name, ActionRead) {
+		denied(w, name)
+		return
+	}
+	artifactPath := ""
+	if len(segments) == 2 {
+		artifactPath = segments[1]
+	}
+	if artifact := repo.artifacts[artifactPath]; artifact != nil {
+		writeJSON(w, http.StatusOK, s.fileInfo(artifact))
+		return
+	}
+	children := folderChildren(repo, artifactPath)
+	if artifactPath != "" && len(children) == 0 {
+		writeError(w, http.StatusNotFound, "Unable to find item")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"repo":     segments[0],
+		"path":     "/" + artifactPath,
+		"uri":      s.storageURI(segments[0], artifactPath),
+		"children": children,
+	})
+}
+
+func (s *Server) fileInfo(artifact *Artifact) map[string]interface{} {
+	return map[string]interface{}{
+		"repo":        artifact.Repo,
+		"path":        "/" + artifact.Path,
+		"created":     artifact.Created.Format(time.RFC3339),
+		"createdBy":   artifact.DeployedBy,
+		"downloadUri": s.URL() + artifact.Repo + "/" + artifact.Path,
+		"size":        fmt.Sprint(len(artifact.Content)),
+		"checksums":   artifact.Checksums,
+		"uri":         s.storageURI(artifact.Repo, artifact.Path),
+	}
+}
+
+// folderChildren gives the files and folders directly in a folder, sorted by name.
+func folderChildren(repo *repository, folder string) []map[string]interface{} {
+	prefix := ""
+	if folder != "" {
+		prefix = folder + "/"
+	}
+	folders := map[string]bool{}
+	for path := range repo.artifacts {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		child := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
+		folders[child[0]] = len(child) == 2
+	}
+	names := make([]string, 0, len(folders))
+	for name := range folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	children := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		children = append(children, map[string]interface{}{"uri": "/" + name, "folder": folders[name]})
+	}
+	return children
+}
+
+// serveChecksumSearch finds the artifacts with the given checksum in the repositories the user may read, optionally
+// restricted to a comma separated list of repositories.
+func (s *Server) serveChecksumSearch(w http.ResponseWriter, r *http.Request, name string, _ string) {
+	query := r.URL.Query()
+	var repoKeys []string
+	if repos := query.Get("repos"); repos != "" {
+		repoKeys = strings.Split(repos, ",")
+	} else {
+		for repoKey := range s.repositories {
+			repoKeys = append(repoKeys, repoKey)
+		}
+	}
+	results := []map[string]string{}
+	for _, repoKey := range repoKeys {
+		repo := s.repositories[repoKey]
+		if repo == nil || !s.isAllowed(repo, name, ActionRead) {
+			continue
+		}
+		for _, artifact := range repo.artifacts {
+			if matchesChecksums(artifact.Checksums, query) {
+				results = append(results, map[string]string{"uri": s.storageURI(repoKey, artifact.Path)})
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i]["uri"] < results[j]["uri"]
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+func matchesChecksums(checksums Checksums, query url.Values) bool {
+	matched := false
+	for parameter, actual := range map[string]string{"sha256": checksums.SHA256, "sha1": checksums.SHA1,
+		"md5": checksums.MD5} {
+		if expected := query.Get(parameter); expected != "" {
+			if !strings.EqualFold(expected, actual) {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
+
+func (s *Server) storageURI(repoKey string, path string) string {
+	if path == "" {
+		return s.URL() + "api/storage/" + repoKey
+	}
+	return s.URL() + "api/storage/" + repoKey + "/" + path
+}
@@ -0,0 +1,195 @@
+// Package fakeartifactory is an in-process fake of the parts of the Artifactory REST API used by the plugin: the
+// Support Bundle API of a source service, and the deploy, storage and checksum search APIs of a target service.
+// It needs neither Docker nor a license, so that tests can run anywhere.
+package fakeartifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AdminUser is the name of the admin user every fake service has.
+	AdminUser = "admin"
+	// AdminPassword is the password of AdminUser.
+	AdminPassword = "password"
+	// Anonymous is the name of the user of the requests without credentials, to which permissions can be granted.
+	Anonymous = "anonymous"
+	// Version is the version of Artifactory reported by the fake.
+	Version = "7.12.5"
+
+	contextPath = "/artifactory/"
+	apiPrefix   = "api/"
+)
+
+// Server is a fake Artifactory service listening on a local port.
+type Server struct {
+	server *httptest.Server
+	faults *FaultInjector
+
+	mu      sync.Mutex
+	now     func() time.Time
+	users   map[string]user
+	bundles []*supportBundle
+	// bundleSequence is the number of Support Bundles created so far, the last part of their IDs.
+	bundleSequence int
+	behavior       BundleBehavior
+	repositories   map[string]*repository
+}
+
+type user struct {
+	password string
+	admin    bool
+}
+
+// New starts a fake Artifactory service with an admin user and no repository. It must be closed once done.
+func New() *Server {
+	s := &Server{
+		now:          time.Now,
+		users:        map[string]user{AdminUser: {password: AdminPassword, admin: true}},
+		repositories: map[string]*repository{},
+	}
+	s.faults = NewFaultInjector(http.HandlerFunc(s.serveHTTP))
+	s.server = httptest.NewServer(s.faults)
+	return s
+}
+
+// Faults gives the injector of faults in the responses of the service.
+func (s *Server) Faults() *FaultInjector {
+	return s.faults
+}
+
+// Close stops the service.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL gives the URL of the service, ending with a slash as in JFrog CLI configuration.
+func (s *Server) URL() string {
+	return s.server.URL + contextPath
+}
+
+// Details gives the details of the service as configured in JFrog CLI, using the credentials of the admin user.
+func (s *Server) Details() *config.ArtifactoryDetails {
+	return &config.ArtifactoryDetails{Url: s.URL(), User: AdminUser, Password: AdminPassword}
+}
+
+// SetClock replaces the clock of the service, which tells when Support Bundles are created and generated.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// AddUser adds a user authenticating with the given password. Admin users may do anything, other users only what
+// they are granted.
+func (s *Server) AddUser(name string, password string, admin bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[name] = user{password: password, admin: admin}
+}
+
+// authenticate gives the name of the user of a request, Anonymous when it has no credentials. It is false when the
+// credentials are wrong.
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	name, password, ok := r.BasicAuth()
+	if !ok {
+		return Anonymous, true
+	}
+	known, exists := s.users[name]
+	return name, exists && known.password == password
+}
+
+func (s *Server) isAdmin(name string) bool {
+	return s.users[name].admin
+}
+
+// serveHTTP serves a request with the lock of the service held.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := r.URL.EscapedPath()
+	name, ok := s.authenticate(r)
+	switch {
+	case !strings.HasPrefix(path, contextPath):
+		writeError(w, http.StatusNotFound, "Not Found")
+	case !ok:
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+	default:
+		s.route(w, r, name, strings.TrimPrefix(path, contextPath))
+	}
+}
+
+// route gives a request to the handler of the API it is sent to, with the path under that API. Paths which are not
+// under the REST API are those of artifacts.
+func (s *Server) route(w http.ResponseWriter, r *http.Request, name string, path string) {
+	routes := []struct {
+		prefix string
+		serve  func(w http.ResponseWriter, r *http.Request, name string, path string)
+	}{
+		{prefix: "api/system/ping", serve: servePing},
+		{prefix: "api/system/version", serve: serveVersion},
+		{prefix: "api/system/support/", serve: s.serveSupportBundles},
+		{prefix: "api/storage/", serve: s.serveStorage},
+		{prefix: "api/search/checksum", serve: s.serveChecksumSearch},
+		{prefix: apiPrefix, serve: serveNotFound},
+		{prefix: "", serve: s.serveDeploy},
+	}
+	for _, route := range routes {
+		if strings.HasPrefix(path, route.prefix) {
+			route.serve(w, r, name, strings.TrimPrefix(path, route.prefix))
+			return
+		}
+	}
+}
+
+func servePing(w http.ResponseWriter, _ *http.Request, _ string, _ string) {
+	writeText(w, http.StatusOK, "OK")
+}
+
+func serveVersion(w http.ResponseWriter, _ *http.Request, _ string, _ string) {
+	writeJSON(w, http.StatusOK, map[string]string{"version": Version, "revision": "71205900"})
+}
+
+func serveNotFound(w http.ResponseWriter, _ *http.Request, _ string, _ string) {
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// denied answers a request of a user lacking a permission, asking anonymous users to authenticate.
+func denied(w http.ResponseWriter, name string) {
+	if name == Anonymous {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	writeError(w, http.StatusForbidden, "Forbidden")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeText(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// writeError answers with an error in the format of Artifactory.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errors": []map[string]interface{}{{"status": status, "message": message}},
+	})
+}
+
+func writeText(w http.ResponseWriter, status int, text string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprint(w, text)
+}
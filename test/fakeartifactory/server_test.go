@@ -0,0 +1,235 @@
+package fakeartifactory_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/jfrog-cli-core/utils/config"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	flunkyhttp "github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/jfrog/jfrog-support-bundle-flunky/test/fakeartifactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_SupportBundleLifecycle(t *testing.T) {
+	now := time.Date(2020, 12, 3, 22, 10, 0, 0, time.UTC)
+	rt := fakeartifactory.New()
+	defer rt.Close()
+	rt.SetClock(func() time.Time { return now })
+	rt.SetBundleBehavior(fakeartifactory.BundleBehavior{GenerationTime: time.Minute})
+	client := &flunkyhttp.Client{RtDetails: rt.Details()}
+
+	bundleID, err := actions.CreateSupportBundle(context.Background(),
+		client, "1234", actions.NewDefaultOptionsProvider(time.Now))
+	require.NoError(t, err)
+	assert.Equal(t, actions.BundleID("20201203-2210-0001"), bundleID)
+
+	status, err := actions.GetSupportBundleStatus(context.Background(), client, bundleID)
+	require.NoError(t, err)
+	assert.Equal(t, actions.BundleStateInProgress, status.State)
+
+	now = now.Add(time.Minute)
+	path, checksums, err := actions.DownloadSupportBundle(context.Background(),
+		client, actions.DownloadTimeouts{Ready: time.Second},
+		flunkyhttp.RetryPolicy{Interval: 10 * time.Millisecond}, bundleID)
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(filepath.Dir(path)) }()
+	archive, ok := rt.Archive(string(bundleID))
+	require.True(t, ok)
+	downloaded, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, archive, downloaded)
+	expected, err := actions.ComputeChecksums(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, checksums)
+
+	bundles, err := actions.ListSupportBundles(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+	assert.Equal(t, "JFrog Support Case number 1234", bundles[0].Name)
+
+	require.NoError(t, actions.DeleteSupportBundle(context.Background(), client, bundleID))
+	_, err = actions.GetSupportBundleStatus(context.Background(), client, bundleID)
+	assert.EqualError(t, err, "http request failed with: 404 Not Found")
+}
+
+func Test_SupportBundleFailures(t *testing.T) {
+	tests := []struct {
+		name           string
+		behavior       fakeartifactory.BundleBehavior
+		expectCategory actions.ErrorCategory
+		expectError    string
+	}{
+		{
+			name:           "creation rejected",
+			behavior:       fakeartifactory.BundleBehavior{CreateStatus: http.StatusInternalServerError},
+			expectCategory: actions.CategoryCreation,
+			expectError:    "http request failed with: 500 Internal Server Error",
+		},
+		{
+			name: "generation failed",
+			behavior: fakeartifactory.BundleBehavior{Status: fakeartifactory.StatusFailed,
+				Message: "disk full"},
+			expectCategory: actions.CategoryGenerationFailure,
+			expectError:    "generation of support bundle 20201203-2210-0001 failed: disk full",
+		},
+		{
+			name:           "unknown status",
+			behavior:       fakeartifactory.BundleBehavior{Status: "archived"},
+			expectCategory: actions.CategoryGenerationFailure,
+			expectError:    `support bundle 20201203-2210-0001 has unknown status "archived"`,
+		},
+		{
+			name:           "never ready",
+			behavior:       fakeartifactory.BundleBehavior{GenerationTime: time.Hour},
+			expectCategory: actions.CategoryDownloadTimeout,
+			expectError:    "timeout waiting for support bundle to be ready",
+		},
+		{
+			name:           "archive not available",
+			behavior:       fakeartifactory.BundleBehavior{ArchiveStatus: http.StatusServiceUnavailable},
+			expectCategory: actions.CategoryDownloadTransfer,
+			expectError:    "http request failed with: 503 Service Unavailable",
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			rt := fakeartifactory.New()
+			defer rt.Close()
+			rt.SetClock(func() time.Time { return time.Date(2020, 12, 3, 22, 10, 0, 0, time.UTC) })
+			rt.SetBundleBehavior(test.behavior)
+			client := &flunkyhttp.Client{RtDetails: rt.Details()}
+
+			bundleID, err := actions.CreateSupportBundle(context.Background(),
+				client, "1234", actions.NewDefaultOptionsProvider(time.Now))
+			if err == nil {
+				_, _, err = actions.DownloadSupportBundle(context.Background(),
+					client, actions.DownloadTimeouts{Ready: 100 * time.Millisecond},
+					flunkyhttp.RetryPolicy{Interval: 10 * time.Millisecond}, bundleID)
+			}
+			require.Error(t, err)
+			assert.EqualError(t, err, test.expectError)
+			assert.Equal(t, test.expectCategory, actions.CategoryOf(err))
+		})
+	}
+}
+
+func Test_SupportBundleRequiresAdmin(t *testing.T) {
+	rt := fakeartifactory.New()
+	defer rt.Close()
+	rt.AddUser("joe", "secret", false)
+
+	for _, details := range []*config.ArtifactoryDetails{
+		{Url: rt.URL()},
+		{Url: rt.URL(), User: "joe", Password: "secret"},
+		{Url: rt.URL(), User: "joe", Password: "wrong"},
+	} {
+		_, err := actions.CreateSupportBundle(context.Background(), &flunkyhttp.Client{RtDetails: details}, "1234",
+			actions.NewDefaultOptionsProvider(time.Now))
+		assert.Equal(t, actions.CategoryAuthentication, actions.CategoryOf(err), details.User)
+	}
+}
+
+func Test_Deploy(t *testing.T) {
+	rt := fakeartifactory.New()
+	defer rt.Close()
+	rt.CreateRepository("logs")
+	rt.SetClock(func() time.Time { return time.Date(2020, 12, 3, 22, 10, 0, 0, time.UTC) })
+	archive := writeArchive(t, "content")
+	checksums, err := actions.ComputeChecksums(archive)
+	require.NoError(t, err)
+	target := actions.UploadTarget{RepoKey: "logs", CaseNumber: "1234", Hostname: "host-1",
+		Properties: actions.UploadProperties{FlunkyVersion: "v1"}}
+	now := func() time.Time { return time.Unix(1, 0) }
+
+	anonymous := &flunkyhttp.Client{RtDetails: &config.ArtifactoryDetails{Url: rt.URL()}}
+	_, err = actions.UploadSupportBundle(context.Background(), anonymous, target, archive, checksums, now)
+	assert.Equal(t, actions.CategoryAuthentication, actions.CategoryOf(err))
+
+	rt.Grant("logs", fakeartifactory.Anonymous, fakeartifactory.ActionDeploy)
+	url, err := actions.UploadSupportBundle(context.Background(), anonymous, target, archive, checksums, now)
+	require.NoError(t, err)
+	assert.Equal(t, rt.URL()+"logs/1234/SB-19700101-000001Z-host-1-1.zip", url)
+	artifact, ok := rt.Artifact("logs", "1234/SB-19700101-000001Z-host-1-1.zip")
+	require.True(t, ok)
+	assert.Equal(t, []byte("content"), artifact.Content)
+	assert.Equal(t, checksums.SHA256, artifact.Checksums.SHA256)
+	assert.Equal(t, "v1", artifact.Properties["support.flunkyVersion"])
+	assert.Equal(t, "1234", artifact.Properties["support.caseNumber"])
+	assert.Equal(t, fakeartifactory.Anonymous, artifact.DeployedBy)
+
+	// The same archive is found in the case folder and not uploaded again
+	url, err = actions.UploadSupportBundle(context.Background(),
+		anonymous, target, archive, checksums, func() time.Time {
+			return time.Unix(2, 0)
+		})
+	require.NoError(t, err)
+	assert.Equal(t, rt.URL()+"logs/1234/SB-19700101-000001Z-host-1-1.zip", url)
+
+	// Another case gets it by checksum, without the content being sent
+	target.CaseNumber = "5678"
+	url, err = actions.UploadSupportBundle(context.Background(), anonymous, target, "missing.zip", checksums, now)
+	require.NoError(t, err)
+	assert.Equal(t, rt.URL()+"logs/5678/SB-19700101-000001Z-host-1-1.zip", url)
+}
+
+func Test_Deploy_checksumMismatch(t *testing.T) {
+	rt := fakeartifactory.New()
+	defer rt.Close()
+	rt.CreateRepository("logs")
+	archive := writeArchive(t, "content")
+	checksums, err := actions.ComputeChecksums(writeArchive(t, "other content"))
+	require.NoError(t, err)
+
+	_, err = actions.UploadSupportBundle(context.Background(), &flunkyhttp.Client{RtDetails: rt.Details()},
+		actions.UploadTarget{RepoKey: "logs", CaseNumber: "1234", Hostname: "host-1"}, archive, checksums, time.Now)
+	var httpErr *actions.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusConflict, httpErr.StatusCode)
+	assert.Contains(t, string(httpErr.Body), "Checksum error")
+	assert.Equal(t, actions.CategoryUploadRejected, actions.CategoryOf(err))
+}
+
+func Test_Storage(t *testing.T) {
+	rt := fakeartifactory.New()
+	defer rt.Close()
+	rt.CreateRepository("logs")
+	client := &flunkyhttp.Client{RtDetails: rt.Details()}
+	_, err := actions.UploadSupportBundle(context.Background(),
+		client, actions.UploadTarget{RepoKey: "logs", CaseNumber: "1234", Hostname: "host-1"},
+		writeArchive(t, "content"), flunkyhttp.Checksums{}, func() time.Time { return time.Unix(1, 0) })
+	require.NoError(t, err)
+
+	status, body, err := client.GetSupportCaseFolder(context.Background(), "logs", "1234")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"repo":"logs","path":"/1234","uri":"`+rt.URL()+`api/storage/logs/1234",
+		"children":[{"uri":"/SB-19700101-000001Z-host-1-1.zip","folder":false}]}`, string(body))
+
+	status, _, err = client.GetSupportCaseFolder(context.Background(), "logs", "5678")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _, err = (&flunkyhttp.Client{RtDetails: &config.ArtifactoryDetails{Url: rt.URL()}}).
+		GetSupportCaseFolder(context.Background(), "logs", "1234")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func writeArchive(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "fakeartifactory")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "sb.zip")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
@@ -0,0 +1,286 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
+	"github.com/jfrog/jfrog-support-bundle-flunky/commands/http"
+	"github.com/jfrog/jfrog-support-bundle-flunky/test/fakeartifactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	statusPath  = `/api/system/support/bundle/[^/]+$`
+	archivePath = `/api/system/support/bundle/[^/]+/archive$`
+	uploadPath  = `/logs/foo/`
+)
+
+// faultsRetryPolicy retries quickly, waiting less than asked by the injected Retry-After headers.
+var faultsRetryPolicy = http.RetryPolicy{MaxAttempts: 3, Interval: 10 * time.Millisecond,
+	MaxInterval: 50 * time.Millisecond}
+
+func Test_DownloadSupportBundleWithFaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		fault          fakeartifactory.Fault
+		expectError    string
+		expectCategory actions.ErrorCategory
+		expectStatus   int
+		expectApplied  int
+		// transferTimeout is the timeout of the transfer of the archive, none when 0.
+		transferTimeout time.Duration
+	}{
+		{
+			name:  "slow status",
+			fault: fakeartifactory.Fault{Path: statusPath, Times: 1, Delay: 300 * time.Millisecond},
+		},
+		{
+			name:  "slow archive",
+			fault: fakeartifactory.Fault{Path: archivePath, Times: 1, Delay: 300 * time.Millisecond},
+		},
+		{
+			name:  "truncated archive",
+			fault: fakeartifactory.Fault{Path: archivePath, Times: 1, TruncateAfter: 100},
+		},
+		{
+			name:  "connection reset mid-download",
+			fault: fakeartifactory.Fault{Path: archivePath, Times: 2, ResetAfter: 100},
+		},
+		{
+			name:           "connection reset on every attempt",
+			fault:          fakeartifactory.Fault{Path: archivePath, ResetAfter: 10},
+			expectCategory: actions.CategoryDownloadTransfer,
+		},
+		{
+			name:  "archive longer than its content",
+			fault: fakeartifactory.Fault{Path: archivePath, Times: 1, ContentLength: 1 << 20},
+		},
+		{
+			name: "status unavailable during a failover",
+			fault: fakeartifactory.Fault{Path: statusPath, Times: 2, Status: nethttp.StatusServiceUnavailable,
+				RetryAfter: "1"},
+			expectApplied: 2,
+		},
+		{
+			name: "status unavailable",
+			fault: fakeartifactory.Fault{Path: statusPath, Status: nethttp.StatusServiceUnavailable,
+				RetryAfter: "1"},
+			expectError:    "http request failed with: 503 Service Unavailable",
+			expectCategory: actions.CategoryDownloadTransfer,
+			expectStatus:   nethttp.StatusServiceUnavailable,
+			expectApplied:  3,
+		},
+		{
+			name: "archive rate limited",
+			fault: fakeartifactory.Fault{Path: archivePath, Times: 2, Status: nethttp.StatusTooManyRequests,
+				RetryAfter: "Wed, 21 Oct 2015 07:28:00 GMT"},
+			expectApplied: 2,
+		},
+		{
+			name:           "archive not found",
+			fault:          fakeartifactory.Fault{Path: archivePath, Status: nethttp.StatusNotFound},
+			expectError:    "http request failed with: 404 Not Found",
+			expectCategory: actions.CategoryDownloadTransfer,
+			expectStatus:   nethttp.StatusNotFound,
+			expectApplied:  1,
+		},
+		{
+			name:           "never leaves in progress",
+			fault:          fakeartifactory.Fault{Path: statusPath, Body: `{"status":"in progress"}`},
+			expectError:    "timeout waiting for support bundle to be ready",
+			expectCategory: actions.CategoryDownloadTimeout,
+		},
+		{
+			name:            "hung archive",
+			fault:           fakeartifactory.Fault{Path: archivePath, Delay: time.Hour},
+			transferTimeout: 200 * time.Millisecond,
+			expectCategory:  actions.CategoryDownloadTransfer,
+			expectApplied:   1,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			log.SetLogger(&testLogger{t: t})
+			rt := fakeartifactory.New()
+			defer rt.Close()
+			client := &http.Client{RtDetails: rt.Details(), Retry: faultsRetryPolicy}
+			bundleID, err := actions.CreateSupportBundle(context.Background(),
+				client, "foo", actions.NewDefaultOptionsProvider(time.Now))
+			require.NoError(t, err)
+			rt.Faults().Inject(test.fault)
+
+			path, checksums, err := actions.DownloadSupportBundle(context.Background(),
+				client, actions.DownloadTimeouts{Ready: time.Second, Transfer: test.transferTimeout}, client.Retry,
+				bundleID)
+			assertApplied(t, test.expectApplied, rt.Faults())
+			if test.expectCategory != "" {
+				require.Error(t, err)
+				if test.expectError != "" {
+					assert.EqualError(t, err, test.expectError)
+				}
+				assert.Equal(t, test.expectCategory, actions.CategoryOf(err))
+				assertHTTPStatus(t, test.expectStatus, err)
+				return
+			}
+			require.NoError(t, err)
+			defer func() { _ = os.RemoveAll(filepath.Dir(path)) }()
+			archive, ok := rt.Archive(string(bundleID))
+			require.True(t, ok)
+			downloaded, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, archive, downloaded)
+			assert.Equal(t, getChecksums(t, path), checksums)
+		})
+	}
+}
+
+func Test_UploadSupportBundleWithFaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		fault          fakeartifactory.Fault
+		expectError    string
+		expectCategory actions.ErrorCategory
+		expectStatus   int
+		expectApplied  int
+	}{
+		{
+			name:  "slow deploy",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath, Delay: 300 * time.Millisecond},
+		},
+		{
+			// The deploy by checksum is the first PUT, the upload of the content is retried after the second one
+			name: "deploy unavailable once",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath, Times: 2,
+				Status: nethttp.StatusServiceUnavailable, RetryAfter: "1"},
+		},
+		{
+			// The checksum deploy and the upload of the content are both attempted three times
+			name: "deploy unavailable",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath,
+				Status: nethttp.StatusServiceUnavailable, RetryAfter: "1"},
+			expectError:    "http request failed with: 503 Service Unavailable",
+			expectCategory: actions.CategoryUploadRejected,
+			expectStatus:   nethttp.StatusServiceUnavailable,
+			expectApplied:  6,
+		},
+		{
+			name: "deploy rate limited",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath, Times: 4,
+				Status: nethttp.StatusTooManyRequests, RetryAfter: "1"},
+			expectApplied: 4,
+		},
+		{
+			name: "deploy rejected",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath,
+				Status: nethttp.StatusBadRequest},
+			expectError:    "http request failed with: 400 Bad Request",
+			expectCategory: actions.CategoryUploadRejected,
+			expectStatus:   nethttp.StatusBadRequest,
+			expectApplied:  2,
+		},
+		{
+			name:  "connection reset once",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath, Times: 2, ResetAfter: 10},
+		},
+		{
+			name:           "truncated deploy response",
+			fault:          fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath, TruncateAfter: 10},
+			expectError:    "unexpected EOF",
+			expectCategory: actions.CategoryUploadRejected,
+		},
+		{
+			name: "deploy response longer than its content",
+			fault: fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath,
+				ContentLength: 1 << 20},
+			expectError:    "unexpected EOF",
+			expectCategory: actions.CategoryUploadRejected,
+		},
+	}
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			log.SetLogger(&testLogger{t: t})
+			rt := fakeartifactory.New()
+			defer rt.Close()
+			rt.CreateRepository("logs")
+			rt.Faults().Inject(test.fault)
+			testBundle := getSupportBundle(t)
+
+			url, err := actions.UploadSupportBundle(context.Background(),
+				&http.Client{RtDetails: rt.Details(), Retry: faultsRetryPolicy},
+				actions.UploadTarget{RepoKey: "logs", CaseNumber: "foo", Hostname: "host-1"}, testBundle, getChecksums(t, testBundle),
+				func() time.Time { return time.Unix(1, 1) })
+			assertApplied(t, test.expectApplied, rt.Faults())
+			if test.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectError)
+				assert.Equal(t, test.expectCategory, actions.CategoryOf(err))
+				assertHTTPStatus(t, test.expectStatus, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, rt.URL()+"logs/foo/SB-19700101-000001Z-host-1-1.zip", url)
+			artifact, ok := rt.Artifact("logs", "foo/SB-19700101-000001Z-host-1-1.zip")
+			require.True(t, ok)
+			assert.Equal(t, getChecksums(t, testBundle).SHA256, artifact.Checksums.SHA256)
+		})
+	}
+}
+
+func Test_UploadSupportBundleHung(t *testing.T) {
+	log.SetLogger(&testLogger{t: t})
+	rt := fakeartifactory.New()
+	defer rt.Close()
+	rt.CreateRepository("logs")
+	rt.Faults().Inject(fakeartifactory.Fault{Method: nethttp.MethodPut, Path: uploadPath, Delay: time.Hour})
+	testBundle := getSupportBundle(t)
+	start := time.Now()
+
+	err := actions.RunWithTimeout(context.Background(), "Support Bundle upload", 200*time.Millisecond,
+		func(ctx context.Context) error {
+			_, err := actions.UploadSupportBundle(ctx, &http.Client{RtDetails: rt.Details(), Retry: faultsRetryPolicy},
+				actions.UploadTarget{RepoKey: "logs", CaseNumber: "foo", Hostname: "host-1"}, testBundle, getChecksums(t, testBundle),
+				time.Now)
+			return err
+		})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Support Bundle upload did not complete within 200ms")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, actions.CategoryUploadRejected, actions.CategoryOf(err))
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second), "the upload in flight is aborted")
+	assert.Equal(t, 1, rt.Faults().Applied(), "the aborted upload is not retried")
+}
+
+// assertHTTPStatus asserts that err wraps an HTTP error of the given status, or none when the status is 0.
+func assertHTTPStatus(t *testing.T, status int, err error) {
+	t.Helper()
+	var httpErr *actions.HTTPError
+	if status == 0 {
+		assert.False(t, errors.As(err, &httpErr))
+		return
+	}
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, status, httpErr.StatusCode)
+}
+
+// assertApplied asserts that the faults applied to the given number of requests, or to some when it is 0.
+func assertApplied(t *testing.T, expected int, faults *fakeartifactory.FaultInjector) {
+	t.Helper()
+	if expected == 0 {
+		assert.NotZero(t, faults.Applied())
+		return
+	}
+	assert.Equal(t, expected, faults.Applied())
+}
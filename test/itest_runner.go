@@ -6,10 +6,12 @@ import (
 	"github.com/docker/go-connections/nat"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-support-bundle-flunky/test/fakeartifactory"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"os"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -32,12 +34,61 @@ type rtInit struct {
 	err     error
 }
 
+// fakeGenerationTime is how long the fake source service takes to generate a Support Bundle, so that the plugin waits
+// for it as it does with a real service.
+const fakeGenerationTime = 250 * time.Millisecond
+
+// runIntegrationTests runs tests against in-process fakes of the source and target services, or against Artifactory
+// containers when the TEST_CONTAINERS environment variable is true. Containers need Docker and a license key in the
+// TEST_LICENSE environment variable, and run the ARTIFACTORY_VERSION version of Artifactory, the latest by default.
+// Tests against containers are skipped in short mode.
 func runIntegrationTests(t *testing.T, tests []integrationTest) {
 	t.Helper()
+	var rtDetails, targetRtDetails *config.ArtifactoryDetails
+	if useContainers() {
+		rtDetails, targetRtDetails = startContainers(t)
+	} else {
+		rtDetails, targetRtDetails = startFakeArtifactories(t)
+	}
+
+	log.SetLogger(&testLogger{t: t})
+
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.Name, func(t *testing.T) {
+			test.Function(t, rtDetails, targetRtDetails)
+		})
+	}
+}
+
+func useContainers() bool {
+	value, err := strconv.ParseBool(os.Getenv("TEST_CONTAINERS"))
+	return err == nil && value
+}
+
+// startFakeArtifactories starts the fake services. As with containers, anonymous users may deploy to the logs
+// repository of the target.
+func startFakeArtifactories(t *testing.T) (*config.ArtifactoryDetails, *config.ArtifactoryDetails) {
+	t.Helper()
+	rt := fakeartifactory.New()
+	t.Cleanup(rt.Close)
+	rt.SetBundleBehavior(fakeartifactory.BundleBehavior{GenerationTime: fakeGenerationTime})
+
+	targetRt := fakeartifactory.New()
+	t.Cleanup(targetRt.Close)
+	targetRt.CreateRepository("logs")
+	targetRt.Grant("logs", fakeartifactory.Anonymous, fakeartifactory.ActionDeploy)
+	return rt.Details(), targetRt.Details()
+}
+
+func startContainers(t *testing.T) (*config.ArtifactoryDetails, *config.ArtifactoryDetails) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping integration test against containers")
+	}
 	licenseKey, exists := os.LookupEnv("TEST_LICENSE")
 	if !exists || licenseKey == "" {
 		t.Skip("Environment variable TEST_LICENSE does not contain a license key")
-		return
 	}
 	version, exists := os.LookupEnv("ARTIFACTORY_VERSION")
 	if !exists {
@@ -71,15 +122,7 @@ func runIntegrationTests(t *testing.T, tests []integrationTest) {
 
 	require.NotNil(t, rt.details)
 	require.NotNil(t, targetRt.details)
-
-	log.SetLogger(&testLogger{t: t})
-
-	for i := range tests {
-		test := tests[i]
-		t.Run(test.Name, func(t *testing.T) {
-			test.Function(t, rt.details, targetRt.details)
-		})
-	}
+	return rt.details, targetRt.details
 }
 
 func terminate(ctx context.Context, l logger, rt *rtInit) {
@@ -16,9 +16,6 @@ import (
 )
 
 func Test_SupportBundleIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping integration test")
-	}
 	tests := []integrationTest{
 		{
 			Name: "Success with temp file deletion",
@@ -29,7 +26,8 @@ func Test_SupportBundleIntegration(t *testing.T) {
 					&cliStub{
 						arguments: []string{caseNumber},
 						stringFlags: map[string]string{
-							"target-repo": "logs",
+							"target-repo":    "logs",
+							"retry-interval": "100ms",
 						},
 						boolFlags: map[string]bool{
 							"cleanup": true,
@@ -66,7 +64,8 @@ func Test_SupportBundleIntegration(t *testing.T) {
 					&cliStub{
 						arguments: []string{caseNumber},
 						stringFlags: map[string]string{
-							"target-repo": "logs",
+							"target-repo":    "logs",
+							"retry-interval": "100ms",
 						},
 						boolFlags: map[string]bool{
 							"cleanup": false,
@@ -137,6 +136,9 @@ func Test_SupportBundleIntegration(t *testing.T) {
 					context.Background(),
 					&cliStub{
 						arguments: []string{"1234"},
+						stringFlags: map[string]string{
+							"target-repo": "logs",
+						},
 						rtDetails: &config.ArtifactoryDetails{
 							Url: "http://rt.invalid",
 						},
@@ -155,6 +157,10 @@ func Test_SupportBundleIntegration(t *testing.T) {
 					context.Background(),
 					&cliStub{
 						arguments: []string{"1234"},
+						stringFlags: map[string]string{
+							"target-repo":    "logs",
+							"retry-interval": "100ms",
+						},
 						rtDetails: rtDetails,
 						targetRtDetails: &config.ArtifactoryDetails{
 							Url: "http://rt.invalid",
@@ -222,6 +228,7 @@ type cliStub struct {
 	boolFlags       map[string]bool
 	rtDetails       *config.ArtifactoryDetails
 	targetRtDetails *config.ArtifactoryDetails
+	prompter        actions.FullPrompter
 }
 
 func (a *cliStub) GetRtDetails() (*config.ArtifactoryDetails, error) {
@@ -245,3 +252,9 @@ func (a *cliStub) GetStringFlagValue(flagName string) string {
 func (a *cliStub) GetBoolFlagValue(flagName string) bool {
 	return a.boolFlags[flagName]
 }
+func (a *cliStub) GetPrompter() (actions.FullPrompter, error) {
+	if a.prompter == nil {
+		return nil, errors.New("no prompter")
+	}
+	return a.prompter, nil
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"github.com/jfrog/jfrog-cli-core/utils/config"
 	"github.com/jfrog/jfrog-cli-core/utils/ioutils"
 	"github.com/jfrog/jfrog-support-bundle-flunky/commands/actions"
@@ -14,20 +15,17 @@ import (
 )
 
 func Test_UploadIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping integration test")
-	}
 	tests := []integrationTest{
 		{
 			Name: "Upload to specified target using target credentials",
 			Function: func(t *testing.T, rtDetails *config.ArtifactoryDetails,
 				targetRtDetails *config.ArtifactoryDetails) {
 				testBundle := getSupportBundle(t)
-				path, err := actions.UploadSupportBundle(&http.Client{RtDetails: targetRtDetails},
-					"foo", testBundle, "logs",
+				path, err := actions.UploadSupportBundle(context.Background(), &http.Client{RtDetails: targetRtDetails},
+					actions.UploadTarget{RepoKey: "logs", CaseNumber: "foo", Hostname: "host-1"}, testBundle, getChecksums(t, testBundle),
 					func() time.Time { return time.Unix(1, 1) })
 				assert.NoError(t, err)
-				assert.Equal(t, targetRtDetails.Url+"logs/foo/SB-19700101-000001Z.zip", path)
+				assert.Equal(t, targetRtDetails.Url+"logs/foo/SB-19700101-000001Z-host-1-1.zip", path)
 			},
 		},
 		{
@@ -36,10 +34,13 @@ func Test_UploadIntegration(t *testing.T) {
 				targetRtDetails *config.ArtifactoryDetails) {
 				testBundle := getSupportBundle(t)
 				targetDetailsWithoutCreds := &config.ArtifactoryDetails{Url: targetRtDetails.Url}
-				path, err := actions.UploadSupportBundle(&http.Client{RtDetails: targetDetailsWithoutCreds},
-					"foo", testBundle, "logs", func() time.Time { return time.Unix(2, 2) })
+				// Another case than the previous test, where the same archive would be found and not uploaded again
+				path, err := actions.UploadSupportBundle(context.Background(),
+					&http.Client{RtDetails: targetDetailsWithoutCreds},
+					actions.UploadTarget{RepoKey: "logs", CaseNumber: "bar", Hostname: "host-1"}, testBundle, getChecksums(t, testBundle),
+					func() time.Time { return time.Unix(2, 2) })
 				assert.NoError(t, err)
-				assert.Equal(t, targetRtDetails.Url+"logs/foo/SB-19700101-000002Z.zip", path)
+				assert.Equal(t, targetRtDetails.Url+"logs/bar/SB-19700101-000002Z-host-1-1.zip", path)
 			},
 		},
 		{
@@ -48,8 +49,9 @@ func Test_UploadIntegration(t *testing.T) {
 				targetRtDetails *config.ArtifactoryDetails) {
 				testBundle := getSupportBundle(t)
 				invalidTarget := &config.ArtifactoryDetails{Url: "http://invalid"}
-				_, err := actions.UploadSupportBundle(&http.Client{RtDetails: invalidTarget},
-					"foo", testBundle, "logs", func() time.Time { return time.Unix(3, 3) })
+				_, err := actions.UploadSupportBundle(context.Background(), &http.Client{RtDetails: invalidTarget},
+					actions.UploadTarget{RepoKey: "logs", CaseNumber: "foo", Hostname: "host-1"}, testBundle, getChecksums(t, testBundle),
+					func() time.Time { return time.Unix(3, 3) })
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), "dial tcp:")
 			},
@@ -66,3 +68,9 @@ func getSupportBundle(t *testing.T) string {
 	require.NoError(t, err)
 	return testBundle
 }
+
+func getChecksums(t *testing.T, path string) http.Checksums {
+	checksums, err := actions.ComputeChecksums(path)
+	require.NoError(t, err)
+	return checksums
+}